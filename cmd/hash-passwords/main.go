@@ -0,0 +1,83 @@
+// Command hash-passwords hashes every password that is still stored as plaintext.
+// Rows changed while it runs are left alone, users can also be migrated by simply logging in.
+package main
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/internal/app/user/repository"
+	"github.com/nathakusuma/auditorium-reservation-backend/internal/infra/database"
+	"github.com/nathakusuma/auditorium-reservation-backend/internal/infra/env"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/hasher"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/log"
+)
+
+func main() {
+	env.NewEnv()
+	log.NewLogger()
+
+	postgresDB := database.NewPostgresPool(
+		env.GetEnv().DBHost,
+		env.GetEnv().DBPort,
+		env.GetEnv().DBUser,
+		env.GetEnv().DBPass,
+		env.GetEnv().DBName,
+	)
+	defer postgresDB.Close()
+
+	hasherInstance, err := hasher.NewHasher(env.GetEnv().PasswordHashAlgorithm)
+	if err != nil {
+		log.Fatal(map[string]interface{}{
+			"error": err.Error(),
+		}, "[HashPasswords] failed to create password hasher")
+	}
+
+	userRepository := repository.NewUserRepository(postgresDB)
+	ctx := context.Background()
+
+	// Soft deleted users are included, their rows shouldn't keep a plaintext password either
+	var users []struct {
+		ID           uuid.UUID `db:"id"`
+		PasswordHash string    `db:"password_hash"`
+	}
+	if err = postgresDB.SelectContext(ctx, &users, `SELECT id, password_hash FROM users`); err != nil {
+		log.Fatal(map[string]interface{}{
+			"error": err.Error(),
+		}, "[HashPasswords] failed to get users")
+	}
+
+	var hashed, skipped int
+	for _, user := range users {
+		if hasher.IsHashed(user.PasswordHash) {
+			continue
+		}
+
+		passwordHash, err2 := hasherInstance.Hash(user.PasswordHash)
+		if err2 != nil {
+			log.Fatal(map[string]interface{}{
+				"error":   err2.Error(),
+				"user.id": user.ID,
+			}, "[HashPasswords] failed to hash password")
+		}
+
+		// Only replaces the value that was read, so a password changed in the meantime is kept
+		err2 = userRepository.UpdatePasswordHash(ctx, user.ID, user.PasswordHash, passwordHash)
+		if err2 != nil {
+			log.Warn(map[string]interface{}{
+				"error":   err2.Error(),
+				"user.id": user.ID,
+			}, "[HashPasswords] failed to update password, skipped")
+			skipped++
+			continue
+		}
+
+		hashed++
+	}
+
+	log.Info(map[string]interface{}{
+		"users":   len(users),
+		"hashed":  hashed,
+		"skipped": skipped,
+	}, "[HashPasswords] done")
+}
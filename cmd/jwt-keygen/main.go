@@ -0,0 +1,128 @@
+// Command jwt-keygen creates access token signing keys for the keyring directory.
+//
+//	jwt-keygen -dir keys              new Ed25519 key named after today's date
+//	jwt-keygen -dir keys -alg rs256   new 3072 bit RSA key
+//	jwt-keygen -dir keys -retire ID   replace the private key ID with its public key, so it only verifies
+package main
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+func main() {
+	dir := flag.String("dir", "", "keyring directory")
+	alg := flag.String("alg", "eddsa", "key algorithm, eddsa or rs256")
+	kid := flag.String("kid", "", "key ID, generated when empty")
+	retire := flag.String("retire", "", "ID of a private key to turn into a verify-only public key")
+	flag.Parse()
+
+	if *dir == "" {
+		fail("-dir is required")
+	}
+
+	if *retire != "" {
+		retireKey(*dir, *retire)
+		return
+	}
+
+	if *kid == "" {
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			fail(err.Error())
+		}
+		*kid = time.Now().Format("20060102") + "-" + hex.EncodeToString(suffix)
+	}
+
+	var private crypto.Signer
+	var err error
+	switch *alg {
+	case "eddsa":
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	case "rs256":
+		private, err = rsa.GenerateKey(rand.Reader, 3072)
+	default:
+		fail("unknown algorithm " + *alg)
+	}
+	if err != nil {
+		fail(err.Error())
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		fail(err.Error())
+	}
+
+	if err = os.MkdirAll(*dir, 0o700); err != nil {
+		fail(err.Error())
+	}
+
+	path := writeKey(filepath.Join(*dir, *kid+".pem"), &pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	fmt.Printf("created %s, set JWT_ACCESS_SIGNING_KEY_ID=%s once verifiers have picked it up\n", path, *kid)
+}
+
+func retireKey(dir, kid string) {
+	path := filepath.Join(dir, kid+".pem")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fail(err.Error())
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		fail(path + " is not a private key")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		fail(err.Error())
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		fail("unsupported private key")
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		fail(err.Error())
+	}
+
+	// Swapped in with a rename so the key is never missing from the directory
+	tmp := writeKey(path+".tmp", &pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	if err = os.Rename(tmp, path); err != nil {
+		fail(err.Error())
+	}
+	fmt.Printf("%s now only verifies, delete it once its tokens have expired\n", path)
+}
+
+func writeKey(path string, block *pem.Block) string {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		fail(err.Error())
+	}
+	if err = pem.Encode(file, block); err != nil {
+		fail(err.Error())
+	}
+
+	if err = file.Close(); err != nil {
+		fail(err.Error())
+	}
+
+	return path
+}
+
+func fail(msg string) {
+	fmt.Fprintln(os.Stderr, "jwt-keygen: "+msg)
+	os.Exit(1)
+}
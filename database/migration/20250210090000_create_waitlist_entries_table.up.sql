@@ -0,0 +1,18 @@
+CREATE TABLE waitlist_entries
+(
+    id               UUID PRIMARY KEY,
+    conference_id    UUID        NOT NULL REFERENCES conferences (id) ON DELETE CASCADE,
+    user_id          UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status           VARCHAR(50) NOT NULL DEFAULT 'waiting'
+        CHECK ( status IN ('waiting', 'offered', 'accepted', 'left', 'expired') ),
+    offered_at       TIMESTAMP,
+    offer_expires_at TIMESTAMP,
+    created_at       TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at       TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX waitlist_entries_active_key ON waitlist_entries (conference_id, user_id)
+    WHERE status IN ('waiting', 'offered');
+CREATE INDEX waitlist_entries_conference_id_status_idx ON waitlist_entries (conference_id, status);
+CREATE INDEX waitlist_entries_offer_expires_at_idx ON waitlist_entries (offer_expires_at)
+    WHERE status = 'offered';
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS registrations_active_conference_id_idx;
+
+DELETE
+FROM registrations
+WHERE cancelled_at IS NOT NULL;
+
+ALTER TABLE registrations
+    DROP COLUMN IF EXISTS cancelled_by,
+    DROP COLUMN IF EXISTS cancelled_at;
@@ -0,0 +1,5 @@
+ALTER TABLE registrations
+    ADD COLUMN cancelled_at TIMESTAMP,
+    ADD COLUMN cancelled_by UUID REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE INDEX registrations_active_conference_id_idx ON registrations (conference_id) WHERE cancelled_at IS NULL;
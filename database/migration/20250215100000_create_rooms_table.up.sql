@@ -0,0 +1,14 @@
+CREATE TABLE rooms
+(
+    id         UUID PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    building   VARCHAR(100) NOT NULL,
+    capacity   INT          NOT NULL CHECK ( capacity > 0 ),
+    amenities  JSONB        NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX rooms_building_name_key ON rooms (building, name) WHERE deleted_at IS NULL;
+CREATE INDEX rooms_deleted_at_idx ON rooms (deleted_at);
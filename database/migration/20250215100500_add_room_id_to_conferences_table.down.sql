@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS conferences_room_id_idx;
+
+ALTER TABLE conferences
+    DROP COLUMN IF EXISTS room_id;
+
+DELETE
+FROM rooms
+WHERE name = 'Main Auditorium'
+  AND building = 'Main Building';
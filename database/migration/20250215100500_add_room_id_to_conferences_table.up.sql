@@ -0,0 +1,15 @@
+ALTER TABLE conferences
+    ADD COLUMN room_id UUID REFERENCES rooms (id);
+
+-- Every existing conference took place in the single implicit auditorium, so it becomes the first room.
+INSERT INTO rooms (id, name, building, capacity)
+SELECT gen_random_uuid(), 'Main Auditorium', 'Main Building', GREATEST(COALESCE(MAX(seats), 0), 500)
+FROM conferences;
+
+UPDATE conferences
+SET room_id = (SELECT id FROM rooms WHERE name = 'Main Auditorium' AND building = 'Main Building');
+
+ALTER TABLE conferences
+    ALTER COLUMN room_id SET NOT NULL;
+
+CREATE INDEX conferences_room_id_idx ON conferences (room_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS conferences_series_id_idx;
+
+ALTER TABLE conferences
+    DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS conference_series;
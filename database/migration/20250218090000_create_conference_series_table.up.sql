@@ -0,0 +1,20 @@
+CREATE TABLE conference_series
+(
+    id           UUID PRIMARY KEY,
+    host_id      UUID        NOT NULL REFERENCES users (id),
+    frequency    VARCHAR(50) NOT NULL
+        CHECK ( frequency IN ('weekly', 'biweekly', 'monthly') ),
+    repeat_until TIMESTAMP,
+    repeat_count INT CHECK ( repeat_count > 0 ),
+    created_at   TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at   TIMESTAMP,
+    CHECK ( (repeat_until IS NULL) != (repeat_count IS NULL) )
+);
+
+CREATE INDEX conference_series_host_id_idx ON conference_series (host_id);
+
+ALTER TABLE conferences
+    ADD COLUMN series_id UUID REFERENCES conference_series (id);
+
+CREATE INDEX conferences_series_id_idx ON conferences (series_id);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS conference_reviews;
+
+UPDATE conferences
+SET status = 'pending'
+WHERE status = 'needs_revision';
+
+ALTER TABLE conferences
+    DROP CONSTRAINT conferences_status_check,
+    ADD CONSTRAINT conferences_status_check
+        CHECK ( status IN ('pending', 'approved', 'rejected') );
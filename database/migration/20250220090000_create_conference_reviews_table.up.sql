@@ -0,0 +1,17 @@
+ALTER TABLE conferences
+    DROP CONSTRAINT conferences_status_check,
+    ADD CONSTRAINT conferences_status_check
+        CHECK ( status IN ('pending', 'approved', 'rejected', 'needs_revision') );
+
+CREATE TABLE conference_reviews
+(
+    id            UUID PRIMARY KEY,
+    conference_id UUID        NOT NULL REFERENCES conferences (id) ON DELETE CASCADE,
+    reviewer_id   UUID        NOT NULL REFERENCES users (id),
+    status        VARCHAR(50) NOT NULL
+        CHECK ( status IN ('pending', 'approved', 'rejected', 'needs_revision') ),
+    comment       VARCHAR(1000),
+    created_at    TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX conference_reviews_conference_id_idx ON conference_reviews (conference_id);
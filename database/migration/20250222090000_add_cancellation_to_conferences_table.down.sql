@@ -0,0 +1,11 @@
+UPDATE conferences
+SET status = 'rejected'
+WHERE status = 'cancelled';
+
+ALTER TABLE conferences
+    DROP COLUMN IF EXISTS cancelled_by,
+    DROP COLUMN IF EXISTS cancelled_at,
+    DROP COLUMN IF EXISTS cancellation_reason,
+    DROP CONSTRAINT conferences_status_check,
+    ADD CONSTRAINT conferences_status_check
+        CHECK ( status IN ('pending', 'approved', 'rejected', 'needs_revision') );
@@ -0,0 +1,7 @@
+ALTER TABLE conferences
+    DROP CONSTRAINT conferences_status_check,
+    ADD CONSTRAINT conferences_status_check
+        CHECK ( status IN ('pending', 'approved', 'rejected', 'needs_revision', 'cancelled') ),
+    ADD COLUMN cancellation_reason VARCHAR(1000),
+    ADD COLUMN cancelled_at        TIMESTAMP,
+    ADD COLUMN cancelled_by        UUID REFERENCES users (id) ON DELETE SET NULL;
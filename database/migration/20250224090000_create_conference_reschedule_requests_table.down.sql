@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS registrations_reconfirm_by_idx;
+
+ALTER TABLE registrations
+    DROP COLUMN IF EXISTS reconfirm_by;
+
+DROP TABLE IF EXISTS conference_reschedule_requests;
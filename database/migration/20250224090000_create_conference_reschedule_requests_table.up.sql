@@ -0,0 +1,25 @@
+CREATE TABLE conference_reschedule_requests
+(
+    id             UUID PRIMARY KEY,
+    conference_id  UUID        NOT NULL REFERENCES conferences (id) ON DELETE CASCADE,
+    requested_by   UUID        NOT NULL REFERENCES users (id),
+    starts_at      TIMESTAMP   NOT NULL,
+    ends_at        TIMESTAMP   NOT NULL,
+    reason         VARCHAR(1000),
+    status         VARCHAR(50) NOT NULL DEFAULT 'pending'
+        CHECK ( status IN ('pending', 'approved', 'rejected') ),
+    reviewed_by    UUID REFERENCES users (id) ON DELETE SET NULL,
+    review_comment VARCHAR(1000),
+    reviewed_at    TIMESTAMP,
+    created_at     TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A conference can only have one reschedule request waiting for review
+CREATE UNIQUE INDEX conference_reschedule_requests_pending_key
+    ON conference_reschedule_requests (conference_id) WHERE status = 'pending';
+
+ALTER TABLE registrations
+    ADD COLUMN reconfirm_by TIMESTAMP;
+
+CREATE INDEX registrations_reconfirm_by_idx ON registrations (reconfirm_by) WHERE reconfirm_by IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS conference_ownership_transfers;
+
+DROP TABLE IF EXISTS conference_hosts;
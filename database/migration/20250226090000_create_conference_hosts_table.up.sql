@@ -0,0 +1,33 @@
+CREATE TABLE conference_hosts
+(
+    conference_id UUID        NOT NULL REFERENCES conferences (id) ON DELETE CASCADE,
+    user_id       UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role          VARCHAR(50) NOT NULL
+        CHECK ( role IN ('owner', 'co_host') ),
+    created_at    TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (conference_id, user_id)
+);
+
+-- conferences.host_id keeps pointing to the owner, so there can only be one
+CREATE UNIQUE INDEX conference_hosts_owner_key ON conference_hosts (conference_id) WHERE role = 'owner';
+CREATE INDEX conference_hosts_user_id_idx ON conference_hosts (user_id);
+
+INSERT INTO conference_hosts (conference_id, user_id, role, created_at)
+SELECT id, host_id, 'owner', created_at
+FROM conferences;
+
+CREATE TABLE conference_ownership_transfers
+(
+    id            UUID PRIMARY KEY,
+    conference_id UUID        NOT NULL REFERENCES conferences (id) ON DELETE CASCADE,
+    from_user_id  UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    to_user_id    UUID        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status        VARCHAR(50) NOT NULL DEFAULT 'pending'
+        CHECK ( status IN ('pending', 'accepted', 'declined', 'cancelled') ),
+    created_at    TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    responded_at  TIMESTAMP
+);
+
+CREATE UNIQUE INDEX conference_ownership_transfers_pending_key
+    ON conference_ownership_transfers (conference_id) WHERE status = 'pending';
+CREATE INDEX conference_ownership_transfers_to_user_id_idx ON conference_ownership_transfers (to_user_id);
@@ -0,0 +1,4 @@
+ALTER TABLE registrations
+    DROP COLUMN IF EXISTS checked_in_by,
+    DROP COLUMN IF EXISTS checked_in_at,
+    DROP COLUMN IF EXISTS ticket_id;
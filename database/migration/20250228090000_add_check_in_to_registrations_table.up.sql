@@ -0,0 +1,6 @@
+-- Tickets are random so they can't be guessed from the registration time, unlike our ULID-style IDs
+ALTER TABLE registrations
+    ADD COLUMN ticket_id     UUID NOT NULL DEFAULT gen_random_uuid(),
+    ADD COLUMN checked_in_at TIMESTAMP,
+    ADD COLUMN checked_in_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    ADD CONSTRAINT registrations_ticket_id_key UNIQUE (ticket_id);
@@ -0,0 +1,6 @@
+ALTER TABLE registrations
+    DROP COLUMN IF EXISTS checked_in_device_id;
+
+DROP TABLE IF EXISTS kiosk_scans;
+
+DROP TABLE IF EXISTS kiosk_devices;
@@ -0,0 +1,29 @@
+CREATE TABLE kiosk_devices
+(
+    id             UUID PRIMARY KEY,
+    conference_id  UUID         NOT NULL REFERENCES conferences (id) ON DELETE CASCADE,
+    name           VARCHAR(100) NOT NULL,
+    created_by     UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at     TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_synced_at TIMESTAMP,
+    revoked_at     TIMESTAMP
+);
+
+CREATE INDEX kiosk_devices_conference_id_idx ON kiosk_devices (conference_id);
+
+-- scan_id is generated on the device, so a retried upload is recognized and answered with the original result
+CREATE TABLE kiosk_scans
+(
+    device_id  UUID        NOT NULL REFERENCES kiosk_devices (id) ON DELETE CASCADE,
+    scan_id    UUID        NOT NULL,
+    ticket_id  UUID,
+    user_id    UUID REFERENCES users (id) ON DELETE SET NULL,
+    scanned_at TIMESTAMP   NOT NULL,
+    result     VARCHAR(50) NOT NULL
+        CHECK ( result IN ('checked_in', 'duplicate', 'invalid', 'foreign', 'revoked') ),
+    synced_at  TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (device_id, scan_id)
+);
+
+ALTER TABLE registrations
+    ADD COLUMN checked_in_device_id UUID REFERENCES kiosk_devices (id) ON DELETE SET NULL;
@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS feedbacks_rating_stats ON feedbacks;
+DROP FUNCTION IF EXISTS feedbacks_rating_stats_trigger();
+DROP FUNCTION IF EXISTS apply_feedback_rating(feedbacks, INT);
+DROP TABLE IF EXISTS conference_rating_stats;
+DROP INDEX IF EXISTS feedbacks_conference_id_rating_idx;
+
+ALTER TABLE feedbacks
+    DROP COLUMN IF EXISTS venue_score,
+    DROP COLUMN IF EXISTS speaker_score,
+    DROP COLUMN IF EXISTS content_score,
+    DROP COLUMN IF EXISTS rating;
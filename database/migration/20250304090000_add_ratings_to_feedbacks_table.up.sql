@@ -0,0 +1,81 @@
+-- Feedback given before ratings existed keeps a NULL rating and is left out of the aggregates
+ALTER TABLE feedbacks
+    ADD COLUMN rating        SMALLINT CHECK (rating BETWEEN 1 AND 5),
+    ADD COLUMN content_score SMALLINT CHECK (content_score BETWEEN 1 AND 5),
+    ADD COLUMN speaker_score SMALLINT CHECK (speaker_score BETWEEN 1 AND 5),
+    ADD COLUMN venue_score   SMALLINT CHECK (venue_score BETWEEN 1 AND 5);
+
+CREATE INDEX feedbacks_conference_id_rating_idx ON feedbacks (conference_id, rating, id) WHERE deleted_at IS NULL;
+
+-- Running totals per conference, kept up to date by the trigger below so reads never scan feedbacks
+CREATE TABLE conference_rating_stats
+(
+    conference_id UUID PRIMARY KEY REFERENCES conferences (id) ON DELETE CASCADE,
+    rating_count  INT NOT NULL DEFAULT 0,
+    rating_sum    INT NOT NULL DEFAULT 0,
+    rating_1      INT NOT NULL DEFAULT 0,
+    rating_2      INT NOT NULL DEFAULT 0,
+    rating_3      INT NOT NULL DEFAULT 0,
+    rating_4      INT NOT NULL DEFAULT 0,
+    rating_5      INT NOT NULL DEFAULT 0,
+    content_count INT NOT NULL DEFAULT 0,
+    content_sum   INT NOT NULL DEFAULT 0,
+    speaker_count INT NOT NULL DEFAULT 0,
+    speaker_sum   INT NOT NULL DEFAULT 0,
+    venue_count   INT NOT NULL DEFAULT 0,
+    venue_sum     INT NOT NULL DEFAULT 0
+);
+
+-- Adds (direction = 1) or removes (direction = -1) a feedback from its conference totals.
+-- Soft deleted and unrated feedback never counts.
+CREATE FUNCTION apply_feedback_rating(f feedbacks, direction INT)
+    RETURNS VOID AS
+$$
+BEGIN
+    IF f.deleted_at IS NOT NULL OR f.rating IS NULL THEN
+        RETURN;
+    END IF;
+
+    INSERT INTO conference_rating_stats (conference_id)
+    VALUES (f.conference_id)
+    ON CONFLICT (conference_id) DO NOTHING;
+
+    UPDATE conference_rating_stats
+    SET rating_count  = rating_count + direction,
+        rating_sum    = rating_sum + direction * f.rating,
+        rating_1      = rating_1 + CASE WHEN f.rating = 1 THEN direction ELSE 0 END,
+        rating_2      = rating_2 + CASE WHEN f.rating = 2 THEN direction ELSE 0 END,
+        rating_3      = rating_3 + CASE WHEN f.rating = 3 THEN direction ELSE 0 END,
+        rating_4      = rating_4 + CASE WHEN f.rating = 4 THEN direction ELSE 0 END,
+        rating_5      = rating_5 + CASE WHEN f.rating = 5 THEN direction ELSE 0 END,
+        content_count = content_count + CASE WHEN f.content_score IS NULL THEN 0 ELSE direction END,
+        content_sum   = content_sum + direction * COALESCE(f.content_score, 0),
+        speaker_count = speaker_count + CASE WHEN f.speaker_score IS NULL THEN 0 ELSE direction END,
+        speaker_sum   = speaker_sum + direction * COALESCE(f.speaker_score, 0),
+        venue_count   = venue_count + CASE WHEN f.venue_score IS NULL THEN 0 ELSE direction END,
+        venue_sum     = venue_sum + direction * COALESCE(f.venue_score, 0)
+    WHERE conference_id = f.conference_id;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION feedbacks_rating_stats_trigger()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM apply_feedback_rating(OLD, -1);
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM apply_feedback_rating(NEW, 1);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER feedbacks_rating_stats
+    AFTER INSERT OR UPDATE OR DELETE
+    ON feedbacks
+    FOR EACH ROW
+EXECUTE FUNCTION feedbacks_rating_stats_trigger();
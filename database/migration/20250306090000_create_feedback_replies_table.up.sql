@@ -0,0 +1,10 @@
+CREATE TABLE feedback_replies
+(
+    id          UUID PRIMARY KEY,
+    feedback_id UUID          NOT NULL REFERENCES feedbacks (id) ON DELETE CASCADE,
+    user_id     UUID          NOT NULL REFERENCES users (id),
+    body        VARCHAR(1000) NOT NULL,
+    created_at  TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT feedback_replies_feedback_id_key UNIQUE (feedback_id)
+);
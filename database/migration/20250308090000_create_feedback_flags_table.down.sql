@@ -0,0 +1,35 @@
+DROP TABLE IF EXISTS feedback_moderation_actions;
+DROP TABLE IF EXISTS feedback_flags;
+
+CREATE OR REPLACE FUNCTION apply_feedback_rating(f feedbacks, direction INT)
+    RETURNS VOID AS
+$$
+BEGIN
+    IF f.deleted_at IS NOT NULL OR f.rating IS NULL THEN
+        RETURN;
+    END IF;
+
+    INSERT INTO conference_rating_stats (conference_id)
+    VALUES (f.conference_id)
+    ON CONFLICT (conference_id) DO NOTHING;
+
+    UPDATE conference_rating_stats
+    SET rating_count  = rating_count + direction,
+        rating_sum    = rating_sum + direction * f.rating,
+        rating_1      = rating_1 + CASE WHEN f.rating = 1 THEN direction ELSE 0 END,
+        rating_2      = rating_2 + CASE WHEN f.rating = 2 THEN direction ELSE 0 END,
+        rating_3      = rating_3 + CASE WHEN f.rating = 3 THEN direction ELSE 0 END,
+        rating_4      = rating_4 + CASE WHEN f.rating = 4 THEN direction ELSE 0 END,
+        rating_5      = rating_5 + CASE WHEN f.rating = 5 THEN direction ELSE 0 END,
+        content_count = content_count + CASE WHEN f.content_score IS NULL THEN 0 ELSE direction END,
+        content_sum   = content_sum + direction * COALESCE(f.content_score, 0),
+        speaker_count = speaker_count + CASE WHEN f.speaker_score IS NULL THEN 0 ELSE direction END,
+        speaker_sum   = speaker_sum + direction * COALESCE(f.speaker_score, 0),
+        venue_count   = venue_count + CASE WHEN f.venue_score IS NULL THEN 0 ELSE direction END,
+        venue_sum     = venue_sum + direction * COALESCE(f.venue_score, 0)
+    WHERE conference_id = f.conference_id;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE feedbacks
+    DROP COLUMN IF EXISTS hidden_at;
@@ -0,0 +1,61 @@
+ALTER TABLE feedbacks
+    ADD COLUMN hidden_at TIMESTAMP;
+
+CREATE TABLE feedback_flags
+(
+    id          UUID PRIMARY KEY,
+    feedback_id UUID         NOT NULL REFERENCES feedbacks (id) ON DELETE CASCADE,
+    user_id     UUID         NOT NULL REFERENCES users (id),
+    reason      VARCHAR(20)  NOT NULL CHECK (reason IN ('spam', 'harassment', 'off_topic', 'other')),
+    details     VARCHAR(500),
+    created_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- Set once a coordinator acts on the feedback. Only open flags count toward the queue and auto-hide.
+    resolved_at TIMESTAMP,
+    CONSTRAINT feedback_flags_feedback_id_user_id_key UNIQUE (feedback_id, user_id)
+);
+
+CREATE INDEX feedback_flags_open_idx ON feedback_flags (feedback_id) WHERE resolved_at IS NULL;
+
+CREATE TABLE feedback_moderation_actions
+(
+    id           UUID PRIMARY KEY,
+    feedback_id  UUID        NOT NULL REFERENCES feedbacks (id) ON DELETE CASCADE,
+    -- NULL when the system hid the feedback after too many flags
+    moderator_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    action       VARCHAR(20) NOT NULL CHECK (action IN ('hide', 'restore', 'dismiss', 'delete', 'auto_hide')),
+    note         VARCHAR(500),
+    created_at   TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX feedback_moderation_actions_feedback_id_idx ON feedback_moderation_actions (feedback_id);
+
+-- Hidden feedback doesn't count toward the conference rating either
+CREATE OR REPLACE FUNCTION apply_feedback_rating(f feedbacks, direction INT)
+    RETURNS VOID AS
+$$
+BEGIN
+    IF f.deleted_at IS NOT NULL OR f.hidden_at IS NOT NULL OR f.rating IS NULL THEN
+        RETURN;
+    END IF;
+
+    INSERT INTO conference_rating_stats (conference_id)
+    VALUES (f.conference_id)
+    ON CONFLICT (conference_id) DO NOTHING;
+
+    UPDATE conference_rating_stats
+    SET rating_count  = rating_count + direction,
+        rating_sum    = rating_sum + direction * f.rating,
+        rating_1      = rating_1 + CASE WHEN f.rating = 1 THEN direction ELSE 0 END,
+        rating_2      = rating_2 + CASE WHEN f.rating = 2 THEN direction ELSE 0 END,
+        rating_3      = rating_3 + CASE WHEN f.rating = 3 THEN direction ELSE 0 END,
+        rating_4      = rating_4 + CASE WHEN f.rating = 4 THEN direction ELSE 0 END,
+        rating_5      = rating_5 + CASE WHEN f.rating = 5 THEN direction ELSE 0 END,
+        content_count = content_count + CASE WHEN f.content_score IS NULL THEN 0 ELSE direction END,
+        content_sum   = content_sum + direction * COALESCE(f.content_score, 0),
+        speaker_count = speaker_count + CASE WHEN f.speaker_score IS NULL THEN 0 ELSE direction END,
+        speaker_sum   = speaker_sum + direction * COALESCE(f.speaker_score, 0),
+        venue_count   = venue_count + CASE WHEN f.venue_score IS NULL THEN 0 ELSE direction END,
+        venue_sum     = venue_sum + direction * COALESCE(f.venue_score, 0)
+    WHERE conference_id = f.conference_id;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,2 @@
+ALTER TABLE feedbacks
+    DROP COLUMN IF EXISTS is_anonymous;
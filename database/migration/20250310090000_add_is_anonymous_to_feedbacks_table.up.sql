@@ -0,0 +1,3 @@
+-- The author is still stored so the one-feedback-per-user rule and abuse handling keep working
+ALTER TABLE feedbacks
+    ADD COLUMN is_anonymous BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS survey_answers;
+DROP TABLE IF EXISTS conference_surveys;
+DROP TABLE IF EXISTS survey_questions;
+DROP TABLE IF EXISTS survey_templates;
@@ -0,0 +1,48 @@
+CREATE TABLE survey_templates
+(
+    id          UUID PRIMARY KEY,
+    title       VARCHAR(100) NOT NULL,
+    description VARCHAR(1000),
+    created_by  UUID         REFERENCES users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at  TIMESTAMP
+);
+
+-- Questions never change once created, so answers always match the question they were given for
+CREATE TABLE survey_questions
+(
+    id          UUID PRIMARY KEY,
+    template_id UUID         NOT NULL REFERENCES survey_templates (id) ON DELETE CASCADE,
+    position    SMALLINT     NOT NULL,
+    type        VARCHAR(20)  NOT NULL CHECK (type IN ('rating', 'single_choice', 'multiple_choice', 'text')),
+    prompt      VARCHAR(255) NOT NULL,
+    required    BOOLEAN      NOT NULL DEFAULT FALSE,
+    -- Choices of single_choice and multiple_choice questions, as a JSON array of strings
+    options     JSONB,
+    -- Highest value of a rating question, the lowest is always 1
+    scale_max   SMALLINT CHECK (scale_max BETWEEN 2 AND 10),
+    CONSTRAINT survey_questions_template_id_position_key UNIQUE (template_id, position)
+);
+
+CREATE TABLE conference_surveys
+(
+    conference_id UUID PRIMARY KEY REFERENCES conferences (id) ON DELETE CASCADE,
+    template_id   UUID      NOT NULL REFERENCES survey_templates (id),
+    attached_by   UUID      REFERENCES users (id) ON DELETE SET NULL,
+    attached_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per value. A multiple choice answer has a row per selected choice, numbered by answer_index.
+CREATE TABLE survey_answers
+(
+    feedback_id  UUID     NOT NULL REFERENCES feedbacks (id) ON DELETE CASCADE,
+    question_id  UUID     NOT NULL REFERENCES survey_questions (id),
+    answer_index SMALLINT NOT NULL DEFAULT 0,
+    rating_value SMALLINT,
+    choice_value VARCHAR(100),
+    text_value   VARCHAR(2000),
+    PRIMARY KEY (feedback_id, question_id, answer_index)
+);
+
+CREATE INDEX survey_answers_question_id_idx ON survey_answers (question_id);
@@ -0,0 +1,3 @@
+-- Fails while argon2id hashes are stored, switch them back to bcrypt first
+ALTER TABLE users
+    ALTER COLUMN password_hash TYPE VARCHAR(60);
@@ -0,0 +1,3 @@
+-- bcrypt hashes are 60 characters, argon2id hashes with their encoded parameters are longer
+ALTER TABLE users
+    ALTER COLUMN password_hash TYPE VARCHAR(255);
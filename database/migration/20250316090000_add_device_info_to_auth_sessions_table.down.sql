@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS auth_sessions_user_id_idx;
+DROP INDEX IF EXISTS auth_sessions_id_key;
+
+-- Keep only the most recently used session of each user so the unique index can come back
+DELETE FROM auth_sessions a
+    USING auth_sessions b
+WHERE a.user_id = b.user_id
+  AND (a.last_used_at, a.token) < (b.last_used_at, b.token);
+
+ALTER TABLE auth_sessions
+    DROP COLUMN IF EXISTS last_used_at,
+    DROP COLUMN IF EXISTS ip_address,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS id;
+
+CREATE UNIQUE INDEX auth_sessions_user_id_key ON auth_sessions (user_id);
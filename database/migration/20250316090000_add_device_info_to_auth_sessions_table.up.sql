@@ -0,0 +1,15 @@
+-- Sessions are per device now, a user can have any number of them
+DROP INDEX IF EXISTS auth_sessions_user_id_key;
+
+ALTER TABLE auth_sessions
+    ADD COLUMN id           UUID         NOT NULL DEFAULT gen_random_uuid(),
+    ADD COLUMN user_agent   VARCHAR(512) NOT NULL DEFAULT '',
+    ADD COLUMN ip_address   VARCHAR(45)  NOT NULL DEFAULT '',
+    ADD COLUMN last_used_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- The default only backfills existing sessions, new IDs come from the application
+ALTER TABLE auth_sessions
+    ALTER COLUMN id DROP DEFAULT;
+
+CREATE UNIQUE INDEX auth_sessions_id_key ON auth_sessions (id);
+CREATE INDEX auth_sessions_user_id_idx ON auth_sessions (user_id, last_used_at DESC);
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS auth_session_rotated_tokens;
+
+-- Raw tokens can't be recovered from their hashes, so every session ends
+DELETE FROM auth_sessions;
+
+DROP INDEX IF EXISTS auth_sessions_token_hash_key;
+
+ALTER TABLE auth_sessions
+    DROP CONSTRAINT auth_sessions_pkey,
+    DROP COLUMN token_hash,
+    ADD COLUMN token CHAR(32) PRIMARY KEY;
+
+CREATE UNIQUE INDEX auth_sessions_id_key ON auth_sessions (id);
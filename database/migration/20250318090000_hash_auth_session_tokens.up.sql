@@ -0,0 +1,27 @@
+ALTER TABLE auth_sessions
+    ADD COLUMN token_hash CHAR(64);
+
+-- Hashed the same way the application does, so existing refresh tokens keep working
+UPDATE auth_sessions
+SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex');
+
+ALTER TABLE auth_sessions
+    ALTER COLUMN token_hash SET NOT NULL,
+    DROP CONSTRAINT auth_sessions_pkey,
+    DROP COLUMN token;
+
+DROP INDEX IF EXISTS auth_sessions_id_key;
+ALTER TABLE auth_sessions
+    ADD PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX auth_sessions_token_hash_key ON auth_sessions (token_hash);
+
+-- Refresh tokens a session has already rotated away from. Seeing one again means it was stolen.
+CREATE TABLE auth_session_rotated_tokens
+(
+    token_hash CHAR(64) PRIMARY KEY,
+    session_id UUID      NOT NULL REFERENCES auth_sessions (id) ON DELETE CASCADE,
+    rotated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX auth_session_rotated_tokens_session_id_idx ON auth_session_rotated_tokens (session_id);
@@ -0,0 +1,18 @@
+-- A row without enabled_at is an enrollment that hasn't been confirmed with a code yet
+CREATE TABLE user_mfa
+(
+    user_id          UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret_encrypted TEXT      NOT NULL,
+    enabled_at       TIMESTAMP,
+    -- The time step of the last accepted code, so a code can't be used twice
+    last_used_step   BIGINT,
+    created_at       TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE user_mfa_recovery_codes
+(
+    user_id   UUID     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    used_at   TIMESTAMP,
+    PRIMARY KEY (user_id, code_hash)
+);
@@ -0,0 +1,15 @@
+-- Accounts at external identity providers that users log in with
+CREATE TABLE user_identities
+(
+    id            UUID PRIMARY KEY,
+    user_id       UUID         NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    issuer        VARCHAR(255) NOT NULL,
+    subject       VARCHAR(255) NOT NULL,
+    -- The email the provider last reported, which may differ from the user's
+    email         VARCHAR(320) NOT NULL,
+    created_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX user_identities_issuer_subject_key ON user_identities (issuer, subject);
+CREATE INDEX user_identities_user_id_idx ON user_identities (user_id);
@@ -7,6 +7,11 @@ WHERE host_id IN (SELECT id
                   FROM users
                   WHERE email LIKE '%@seeder.nathakusuma.com');
 
+-- Delete all seeded rooms
+DELETE
+FROM rooms
+WHERE building = 'Seeder Building';
+
 -- Delete all seeded users
 DELETE
 FROM users
@@ -40,6 +40,8 @@ $$
         ec1_id    UUID;
         ec2_id    UUID;
         admin1_id UUID;
+        -- Room IDs declarations
+        main_room_id UUID;
     BEGIN
         -- Initialize UUIDs
         user1_id := generate_ulid_at_time(NOW() - INTERVAL '30 days');
@@ -49,143 +51,151 @@ $$
         ec1_id := generate_ulid_at_time(NOW() - INTERVAL '30 days' + INTERVAL '4 hours');
         ec2_id := generate_ulid_at_time(NOW() - INTERVAL '30 days' + INTERVAL '5 hours');
         admin1_id := generate_ulid_at_time(NOW() - INTERVAL '30 days' + INTERVAL '6 hours');
+        main_room_id := generate_ulid_at_time(NOW() - INTERVAL '30 days');
 
-        -- Users seeder
+        -- Users seeder, every password is "DevSecOps"
         INSERT INTO users (id, name, email, password_hash, role, bio, created_at)
         VALUES
             -- Regular users
             (user1_id, 'User 1', 'user1@seeder.nathakusuma.com',
-             'DevSecOps', 'user',
+             '$2a$12$kymNXBeNqOd/9yR48pGCOO9R94cF.vWri9dWkBFouUzcRTcoYr3lm', 'user',
              'Software Engineer with 5 years experience', NOW() - INTERVAL '30 days'),
             (user2_id, 'User 2', 'user2@seeder.nathakusuma.com',
-             'DevSecOps', 'user', 'Product Manager at Tech Corp',
+             '$2a$12$kymNXBeNqOd/9yR48pGCOO9R94cF.vWri9dWkBFouUzcRTcoYr3lm', 'user', 'Product Manager at Tech Corp',
              NOW() - INTERVAL '30 days' + INTERVAL '1 hour'),
             (user3_id, 'User 3', 'user3@seeder.nathakusuma.com',
-             'DevSecOps', 'user', 'Full Stack Developer',
+             '$2a$12$kymNXBeNqOd/9yR48pGCOO9R94cF.vWri9dWkBFouUzcRTcoYr3lm', 'user', 'Full Stack Developer',
              NOW() - INTERVAL '30 days' + INTERVAL '2 hours'),
             (user4_id, 'User 4', 'user4@seeder.nathakusuma.com',
-             'DevSecOps', 'user', 'Back End Developer',
+             '$2a$12$kymNXBeNqOd/9yR48pGCOO9R94cF.vWri9dWkBFouUzcRTcoYr3lm', 'user', 'Back End Developer',
              NOW() - INTERVAL '30 days' + INTERVAL '3 hours'),
             -- Event coordinator
             (ec1_id, 'Event Coordinator 1', 'ec1@seeder.nathakusuma.com',
-             'DevSecOps', 'event_coordinator',
+             '$2a$12$kymNXBeNqOd/9yR48pGCOO9R94cF.vWri9dWkBFouUzcRTcoYr3lm', 'event_coordinator',
              'Professional Event Coordinator', NOW() - INTERVAL '30 days' + INTERVAL '4 hours'),
             (ec2_id, 'Event Coordinator 2', 'ec2@seeder.nathakusuma.com',
-             'DevSecOps', 'event_coordinator',
+             '$2a$12$kymNXBeNqOd/9yR48pGCOO9R94cF.vWri9dWkBFouUzcRTcoYr3lm', 'event_coordinator',
              'Professional Event Coordinator', NOW() - INTERVAL '30 days' + INTERVAL '5 hours'),
             -- Admin
             (admin1_id, 'Admin User 1', 'admin1@seeder.nathakusuma.com',
-             'DevSecOps', 'admin', 'System Administrator',
+             '$2a$12$kymNXBeNqOd/9yR48pGCOO9R94cF.vWri9dWkBFouUzcRTcoYr3lm', 'admin', 'System Administrator',
              NOW() - INTERVAL '30 days' + INTERVAL '6 hours');
 
+        -- Rooms seeder
+        INSERT INTO rooms (id, name, building, capacity, amenities, created_at)
+        VALUES (main_room_id, 'Seeder Auditorium', 'Seeder Building', 300, '["projector", "sound_system", "stage"]',
+                NOW() - INTERVAL '30 days'),
+               (generate_ulid_at_time(NOW() - INTERVAL '30 days'), 'Seeder Seminar Room', 'Seeder Building', 40,
+                '["projector", "whiteboard"]', NOW() - INTERVAL '30 days');
+
         -- Conferences seeder
         INSERT INTO conferences (id, title, description, speaker_name, speaker_title, target_audience, prerequisites,
-                                 seats, starts_at, ends_at, host_id, status, created_at)
+                                 seats, starts_at, ends_at, host_id, room_id, status, created_at)
         VALUES
             -- Past conferences (approved)
             (generate_ulid_at_time(NOW() - INTERVAL '12 days'), 'Past Conference 1',
              'Description for past conference 1', 'Dr. Smith', 'Professor', 'Developers', 'Basic programming', 100,
-             NOW() - INTERVAL '7 days', NOW() - INTERVAL '7 days' + INTERVAL '2 hours', user1_id, 'approved',
+             NOW() - INTERVAL '7 days', NOW() - INTERVAL '7 days' + INTERVAL '2 hours', user1_id, main_room_id, 'approved',
              NOW() - INTERVAL '12 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '12 days'), 'Past Conference 2',
              'Description for past conference 2', 'Jane Doe', 'Tech Lead', 'Architects', 'System design experience', 50,
-             NOW() - INTERVAL '6 days', NOW() - INTERVAL '6 days' + INTERVAL '2 hours', user2_id, 'approved',
+             NOW() - INTERVAL '6 days', NOW() - INTERVAL '6 days' + INTERVAL '2 hours', user2_id, main_room_id, 'approved',
              NOW() - INTERVAL '12 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '17 days'), 'Past Conference 3', 'Advanced JavaScript Patterns',
              'Lisa Johnson', 'Senior JS Developer', 'Advanced developers', 'JavaScript experience', 120,
-             NOW() - INTERVAL '9 days', NOW() - INTERVAL '9 days' + INTERVAL '2 hours', user1_id, 'approved',
+             NOW() - INTERVAL '9 days', NOW() - INTERVAL '9 days' + INTERVAL '2 hours', user1_id, main_room_id, 'approved',
              NOW() - INTERVAL '17 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '17 days'), 'Past Conference 4', 'Microservices Architecture',
              'Mike Chen', 'Solutions Architect', 'System architects', 'Distributed systems knowledge', 80,
-             NOW() - INTERVAL '8 days', NOW() - INTERVAL '8 days' + INTERVAL '2 hours', user2_id, 'approved',
+             NOW() - INTERVAL '8 days', NOW() - INTERVAL '8 days' + INTERVAL '2 hours', user2_id, main_room_id, 'approved',
              NOW() - INTERVAL '17 days'),
 
             -- Current approved conference
             (generate_ulid_at_time(NOW() - INTERVAL '7 days'), 'Current Active Conference',
              'Currently running conference', 'Dr. Johnson', 'CTO', 'All developers', NULL, 200, NOW(),
-             NOW() + INTERVAL '4 hours', user3_id, 'approved', NOW() - INTERVAL '7 days'),
+             NOW() + INTERVAL '4 hours', user3_id, main_room_id, 'approved', NOW() - INTERVAL '7 days'),
 
             -- Future conferences (approved)
             (generate_ulid_at_time(NOW() - INTERVAL '7 days'), 'Future Conference 1',
              'Description for future conference 1', 'Alice Brown', 'Senior Developer', 'Junior developers', 'None', 150,
-             NOW() + INTERVAL '5 days', NOW() + INTERVAL '5 days' + INTERVAL '2 hours', user1_id, 'approved',
+             NOW() + INTERVAL '5 days', NOW() + INTERVAL '5 days' + INTERVAL '2 hours', user1_id, main_room_id, 'approved',
              NOW() - INTERVAL '7 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '7 days'), 'Future Conference 2',
              'Description for future conference 2', 'Bob Williams', 'Architect', 'Senior developers',
              'Advanced programming', 75, NOW() + INTERVAL '9 days', NOW() + INTERVAL '9 days' + INTERVAL '2 hours',
-             user2_id, 'approved', NOW() - INTERVAL '7 days'),
+             user2_id, main_room_id, 'approved', NOW() - INTERVAL '7 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '3 days'), 'Future Conference 3',
              'Description for future conference 3', 'Henry Ford', 'Tech Lead', 'All levels', NULL, 200,
-             NOW() + INTERVAL '37 days', NOW() + INTERVAL '37 days' + INTERVAL '2 hours', user3_id, 'approved',
+             NOW() + INTERVAL '37 days', NOW() + INTERVAL '37 days' + INTERVAL '2 hours', user3_id, main_room_id, 'approved',
              NOW() - INTERVAL '3 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '3 days'), 'Future Conference 4',
              'Description for future conference 4', 'Ivy Chen', 'Senior Architect', 'Senior developers',
              'Architecture experience', 100, NOW() + INTERVAL '42 days',
-             NOW() + INTERVAL '42 days' + INTERVAL '2 hours', user1_id, 'approved', NOW() - INTERVAL '3 days'),
+             NOW() + INTERVAL '42 days' + INTERVAL '2 hours', user1_id, main_room_id, 'approved', NOW() - INTERVAL '3 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '3 days'), 'Future Conference 5', 'Cloud Native Applications',
              'Nathan Black', 'Cloud Architect', 'DevOps engineers', 'Kubernetes basics', 150,
-             NOW() + INTERVAL '57 days', NOW() + INTERVAL '57 days' + INTERVAL '2 hours', user2_id, 'approved',
+             NOW() + INTERVAL '57 days', NOW() + INTERVAL '57 days' + INTERVAL '2 hours', user2_id, main_room_id, 'approved',
              NOW() - INTERVAL '3 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '3 days'), 'Future Conference 6', 'AI in Production',
              'Olivia Green', 'ML Engineer', 'Data scientists', 'Python, ML basics', 100, NOW() + INTERVAL '64 days',
-             NOW() + INTERVAL '64 days' + INTERVAL '2 hours', user3_id, 'approved', NOW() - INTERVAL '3 days'),
+             NOW() + INTERVAL '64 days' + INTERVAL '2 hours', user3_id, main_room_id, 'approved', NOW() - INTERVAL '3 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '3 days'), 'Future Conference 7', 'Blockchain Development',
              'Peter White', 'Blockchain Developer', 'Developers', 'Cryptography basics', 120,
-             NOW() + INTERVAL '68 days', NOW() + INTERVAL '68 days' + INTERVAL '2 hours', user1_id, 'approved',
+             NOW() + INTERVAL '68 days', NOW() + INTERVAL '68 days' + INTERVAL '2 hours', user1_id, main_room_id, 'approved',
              NOW() - INTERVAL '3 days'),
 
             -- Pending conferences (one per user)
             (generate_ulid_at_time(NOW() - INTERVAL '5 days'), 'Pending Conference 1',
              'Description for pending conference 1', 'Charlie Brown', 'Developer', 'All levels', NULL, 100,
-             NOW() + INTERVAL '14 days', NOW() + INTERVAL '14 days' + INTERVAL '2 hours', user1_id, 'pending',
+             NOW() + INTERVAL '14 days', NOW() + INTERVAL '14 days' + INTERVAL '2 hours', user1_id, main_room_id, 'pending',
              NOW() - INTERVAL '5 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '5 days'), 'Pending Conference 2',
              'Description for pending conference 2', 'Diana Prince', 'Manager', 'Team leads', 'Management experience',
-             50, NOW() + INTERVAL '19 days', NOW() + INTERVAL '19 days' + INTERVAL '2 hours', user2_id, 'pending',
+             50, NOW() + INTERVAL '19 days', NOW() + INTERVAL '19 days' + INTERVAL '2 hours', user2_id, main_room_id, 'pending',
              NOW() - INTERVAL '5 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '5 days'), 'Pending Conference 3',
              'Description for pending conference 3', 'Edward Smith', 'Lead Developer', 'Developers',
              'Coding experience', 75, NOW() + INTERVAL '24 days', NOW() + INTERVAL '24 days' + INTERVAL '2 hours',
-             user3_id, 'pending', NOW() - INTERVAL '5 days'),
+             user3_id, main_room_id, 'pending', NOW() - INTERVAL '5 days'),
 
             -- Rejected conferences
             (generate_ulid_at_time(NOW() - INTERVAL '4 days'), 'Rejected Conference 1',
              'Description for rejected conference 1', 'Frank Miller', 'Developer', 'Beginners', NULL, 100,
-             NOW() + INTERVAL '29 days', NOW() + INTERVAL '29 days' + INTERVAL '2 hours', user1_id, 'rejected',
+             NOW() + INTERVAL '29 days', NOW() + INTERVAL '29 days' + INTERVAL '2 hours', user1_id, main_room_id, 'rejected',
              NOW() - INTERVAL '4 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '4 days'), 'Rejected Conference 2',
              'Description for rejected conference 2', 'Grace Lee', 'Senior Developer', 'Intermediate',
              'Basic programming', 150, NOW() + INTERVAL '33 days', NOW() + INTERVAL '33 days' + INTERVAL '2 hours',
-             user2_id, 'rejected', NOW() - INTERVAL '4 days'),
+             user2_id, main_room_id, 'rejected', NOW() - INTERVAL '4 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '3 days'), 'Rejected Conference 3', 'Gaming Development',
              'Quinn Adams', 'Game Developer', 'Game developers', 'C++ knowledge', 90, NOW() + INTERVAL '73 days',
-             NOW() + INTERVAL '73 days' + INTERVAL '2 hours', user3_id, 'rejected', NOW() - INTERVAL '3 days'),
+             NOW() + INTERVAL '73 days' + INTERVAL '2 hours', user3_id, main_room_id, 'rejected', NOW() - INTERVAL '3 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '3 days'), 'Rejected Conference 4', 'Mobile App Security',
              'Rachel Torres', 'Security Engineer', 'Mobile developers', 'iOS/Android development', 80,
-             NOW() + INTERVAL '78 days', NOW() + INTERVAL '78 days' + INTERVAL '2 hours', user1_id, 'rejected',
+             NOW() + INTERVAL '78 days', NOW() + INTERVAL '78 days' + INTERVAL '2 hours', user1_id, main_room_id, 'rejected',
              NOW() - INTERVAL '3 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '3 days'), 'Rejected Conference 5', 'DevOps Best Practices',
              'Sam Lee', 'DevOps Lead', 'Operations teams', 'Linux administration', 100, NOW() + INTERVAL '83 days',
-             NOW() + INTERVAL '83 days' + INTERVAL '2 hours', user2_id, 'rejected', NOW() - INTERVAL '3 days'),
+             NOW() + INTERVAL '83 days' + INTERVAL '2 hours', user2_id, main_room_id, 'rejected', NOW() - INTERVAL '3 days'),
 
             -- Some deleted conferences
             (generate_ulid_at_time(NOW() - INTERVAL '2 days'), 'Deleted Conference 1',
              'Description for deleted conference 1', 'Jack Black', 'Developer', 'All levels', NULL, 100,
-             NOW() + INTERVAL '47 days', NOW() + INTERVAL '47 days' + INTERVAL '2 hours', user2_id, 'approved',
+             NOW() + INTERVAL '47 days', NOW() + INTERVAL '47 days' + INTERVAL '2 hours', user2_id, main_room_id, 'approved',
              NOW() - INTERVAL '2 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '2 days'), 'Deleted Conference 2',
              'Description for deleted conference 2', 'Kelly White', 'Manager', 'Team leads', 'Management experience',
-             75, NOW() + INTERVAL '52 days', NOW() + INTERVAL '52 days' + INTERVAL '2 hours', user3_id, 'pending',
+             75, NOW() + INTERVAL '52 days', NOW() + INTERVAL '52 days' + INTERVAL '2 hours', user3_id, main_room_id, 'pending',
              NOW() - INTERVAL '2 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '2 days'), 'Deleted Conference 3', 'Frontend Testing', 'Tom Wilson',
              'QA Lead', 'Frontend developers', 'JavaScript, React', 70, NOW() + INTERVAL '88 days',
-             NOW() + INTERVAL '88 days' + INTERVAL '2 hours', user1_id, 'approved', NOW() - INTERVAL '2 days'),
+             NOW() + INTERVAL '88 days' + INTERVAL '2 hours', user1_id, main_room_id, 'approved', NOW() - INTERVAL '2 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '2 days'), 'Deleted Conference 4', 'Data Engineering', 'Uma Patel',
              'Data Engineer', 'Data engineers', 'SQL, Python', 85, NOW() + INTERVAL '93 days',
-             NOW() + INTERVAL '93 days' + INTERVAL '2 hours', user2_id, 'pending', NOW() - INTERVAL '2 days'),
+             NOW() + INTERVAL '93 days' + INTERVAL '2 hours', user2_id, main_room_id, 'pending', NOW() - INTERVAL '2 days'),
             (generate_ulid_at_time(NOW() - INTERVAL '2 days'), 'Deleted Conference 5', 'API Design', 'Victor Kim',
              'API Architect', 'Backend developers', 'REST fundamentals', 95, NOW() + INTERVAL '98 days',
-             NOW() + INTERVAL '98 days' + INTERVAL '2 hours', user3_id, 'rejected', NOW() - INTERVAL '2 days');
+             NOW() + INTERVAL '98 days' + INTERVAL '2 hours', user3_id, main_room_id, 'rejected', NOW() - INTERVAL '2 days');
 
 
         -- Update deleted conferences
@@ -193,6 +203,12 @@ $$
         SET deleted_at = NOW() - INTERVAL '1 day'
         WHERE title LIKE 'Deleted Conference%';
 
+        -- Every host owns their conference
+        INSERT INTO conference_hosts (conference_id, user_id, role, created_at)
+        SELECT id, host_id, 'owner', created_at
+        FROM conferences
+        WHERE host_id IN (user1_id, user2_id, user3_id);
+
         -- Register all users for Past Conference 1
         INSERT INTO registrations (user_id, conference_id, created_at)
         SELECT users.id,
@@ -284,87 +300,96 @@ $$
           AND users.id = user2_id;
 
         -- Feedback for Past Conference 1 (all users were registered)
-        INSERT INTO feedbacks (id, user_id, conference_id, comment, created_at)
+        INSERT INTO feedbacks (id, user_id, conference_id, comment, rating, content_score, speaker_score, venue_score, created_at)
         SELECT generate_ulid_at_time(NOW() - INTERVAL '6 days'),
                user1_id,
                conferences.id,
                'Great introduction to the topic. The speaker was very knowledgeable and engaging.',
+               5, 5, 5, 4,
                NOW() - INTERVAL '6 days'
         FROM conferences
         WHERE title = 'Past Conference 1';
 
-        INSERT INTO feedbacks (id, user_id, conference_id, comment, created_at)
+        INSERT INTO feedbacks (id, user_id, conference_id, comment, rating, content_score, speaker_score, venue_score, created_at)
         SELECT generate_ulid_at_time(NOW() - INTERVAL '6 days' + INTERVAL '1 hour'),
                user2_id,
                conferences.id,
                'Well-organized conference with valuable insights. Would recommend to others.',
+               4, 4, NULL, 5,
                NOW() - INTERVAL '6 days' + INTERVAL '1 hour'
         FROM conferences
         WHERE title = 'Past Conference 1';
 
-        INSERT INTO feedbacks (id, user_id, conference_id, comment, created_at)
+        INSERT INTO feedbacks (id, user_id, conference_id, comment, rating, content_score, speaker_score, venue_score, created_at)
         SELECT generate_ulid_at_time(NOW() - INTERVAL '6 days' + INTERVAL '2 hours'),
                user3_id,
                conferences.id,
                'The practical examples were particularly helpful. Looking forward to applying these concepts.',
+               5, 5, 4, NULL,
                NOW() - INTERVAL '6 days' + INTERVAL '2 hours'
         FROM conferences
         WHERE title = 'Past Conference 1';
 
         -- Feedback for Past Conference 2 (User 1, 2, 3 were registered)
-        INSERT INTO feedbacks (id, user_id, conference_id, comment, created_at)
+        INSERT INTO feedbacks (id, user_id, conference_id, comment, rating, content_score, speaker_score, venue_score, created_at)
         SELECT generate_ulid_at_time(NOW() - INTERVAL '5 days'),
                user1_id,
                conferences.id,
                'The advanced concepts were explained clearly. Excellent presentation skills.',
+               5, 4, 5, 4,
                NOW() - INTERVAL '5 days'
         FROM conferences
         WHERE title = 'Past Conference 2';
 
-        INSERT INTO feedbacks (id, user_id, conference_id, comment, created_at)
+        INSERT INTO feedbacks (id, user_id, conference_id, comment, rating, content_score, speaker_score, venue_score, created_at)
         SELECT generate_ulid_at_time(NOW() - INTERVAL '5 days' + INTERVAL '1 hour'),
                user2_id,
                conferences.id,
                'Very informative session. The Q&A portion was particularly enlightening.',
+               4, NULL, NULL, NULL,
                NOW() - INTERVAL '5 days' + INTERVAL '1 hour'
         FROM conferences
         WHERE title = 'Past Conference 2';
 
         -- Feedback for Past Conference 3 (User 2, 3, 4 were registered)
-        INSERT INTO feedbacks (id, user_id, conference_id, comment, created_at)
+        INSERT INTO feedbacks (id, user_id, conference_id, comment, rating, content_score, speaker_score, venue_score, created_at)
         SELECT generate_ulid_at_time(NOW() - INTERVAL '8 days'),
                user2_id,
                conferences.id,
                'The JavaScript patterns shared will definitely improve our codebase. Thanks!',
+               4, 5, 4, 3,
                NOW() - INTERVAL '8 days'
         FROM conferences
         WHERE title = 'Past Conference 3';
 
-        INSERT INTO feedbacks (id, user_id, conference_id, comment, created_at)
+        INSERT INTO feedbacks (id, user_id, conference_id, comment, rating, content_score, speaker_score, venue_score, created_at)
         SELECT generate_ulid_at_time(NOW() - INTERVAL '8 days' + INTERVAL '1 hour'),
                user4_id,
                conferences.id,
                'Excellent deep dive into advanced JavaScript concepts. Very practical examples.',
+               5, 5, 5, 5,
                NOW() - INTERVAL '8 days' + INTERVAL '1 hour'
         FROM conferences
         WHERE title = 'Past Conference 3';
 
         -- Feedback for Past Conference 4 (User 1, 4 were registered)
-        INSERT INTO feedbacks (id, user_id, conference_id, comment, created_at)
+        INSERT INTO feedbacks (id, user_id, conference_id, comment, rating, content_score, speaker_score, venue_score, created_at)
         SELECT generate_ulid_at_time(NOW() - INTERVAL '7 days'),
                user1_id,
                conferences.id,
                'The microservices architecture patterns presented were very relevant to our current projects.',
+               3, 4, 3, 2,
                NOW() - INTERVAL '7 days'
         FROM conferences
         WHERE title = 'Past Conference 4';
 
         -- Add some deleted feedbacks
-        INSERT INTO feedbacks (id, user_id, conference_id, comment, created_at, deleted_at)
+        INSERT INTO feedbacks (id, user_id, conference_id, comment, rating, content_score, speaker_score, venue_score, created_at, deleted_at)
         SELECT generate_ulid_at_time(NOW() - INTERVAL '7 days' + INTERVAL '1 hour'),
                user4_id,
                conferences.id,
                'This feedback has been deleted',
+               1, NULL, NULL, NULL,
                NOW() - INTERVAL '7 days' + INTERVAL '1 hour',
                NOW() - INTERVAL '1 day'
         FROM conferences
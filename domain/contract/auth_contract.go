@@ -2,23 +2,71 @@ package contract
 
 import (
 	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+)
+
+type OTPCheckResult int
+
+const (
+	OTPLocked OTPCheckResult = iota - 2
+	OTPMissing
+	OTPMismatch
+	OTPMatched
 )
 
 type IAuthRepository interface {
-	SetOTPRegisterUser(ctx context.Context, email, otp string) error
-	GetOTPRegisterUser(ctx context.Context, email string) (string, error)
-	DeleteOTPRegisterUser(ctx context.Context, email string) error
+	// OTPs are stored as hashes, one per purpose and email
+	SetOTP(ctx context.Context, purpose enum.OTPPurpose, email, otpHash string, ttl time.Duration) error
+	// CheckOTP compares the OTP, counting a mismatch as a failed attempt. Reaching maxAttempts deletes the OTP
+	// and locks the email for lockDuration. With consume, a matching OTP is deleted in the same step.
+	CheckOTP(ctx context.Context, purpose enum.OTPPurpose, email, otpHash string, maxAttempts int,
+		lockDuration time.Duration, consume bool) (OTPCheckResult, error)
+	// GetOTPLockRemaining returns how long the email stays locked, zero if it isn't
+	GetOTPLockRemaining(ctx context.Context, purpose enum.OTPPurpose, email string,
+		maxAttempts int) (time.Duration, error)
+	// AcquireOTPCooldown starts the resend cooldown. If one is already running, its remaining time is returned.
+	AcquireOTPCooldown(ctx context.Context, purpose enum.OTPPurpose, email string,
+		cooldown time.Duration) (time.Duration, error)
 
-	CreateAuthSession(ctx context.Context, authSession *entity.AuthSession) error
-	GetAuthSessionByToken(ctx context.Context, token string) (*entity.AuthSession, error)
-	DeleteAuthSession(ctx context.Context, userID uuid.UUID) error
+	// Login failures are counted per subject, such as an account or an IP address.
+	// RecordLoginFailure returns how long the subject is locked for, zero if it isn't.
+	RecordLoginFailure(ctx context.Context, subject string, maxFailures int,
+		window, lockDuration, maxLockDuration time.Duration) (time.Duration, error)
+	GetLoginLockRemaining(ctx context.Context, subject string) (time.Duration, error)
+	ClearLoginFailures(ctx context.Context, subject string) error
+
+	// An MFA challenge links the token from the first login step to the user. Missing challenges are redis.Nil.
+	SetMFAChallenge(ctx context.Context, tokenHash string, userID uuid.UUID, ttl time.Duration) error
+	GetMFAChallenge(ctx context.Context, tokenHash string) (uuid.UUID, error)
+	// UseMFAChallengeAttempt counts an attempt at answering the challenge. Once maxAttempts is exceeded,
+	// the challenge is deleted.
+	UseMFAChallengeAttempt(ctx context.Context, tokenHash string, maxAttempts int) (uuid.UUID, error)
+	// DeleteMFAChallenge returns redis.Nil if the challenge was already gone, so it can only be completed once
+	DeleteMFAChallenge(ctx context.Context, tokenHash string) error
 
-	SetOTPResetPassword(ctx context.Context, email, otp string) error
-	GetOTPResetPassword(ctx context.Context, email string) (string, error)
-	DeleteOTPResetPassword(ctx context.Context, email string) error
+	// The state of an OIDC login in progress, used once. Missing states are redis.Nil.
+	SetOIDCState(ctx context.Context, stateHash, nonce, codeVerifier string, ttl time.Duration) error
+	ConsumeOIDCState(ctx context.Context, stateHash string) (nonce, codeVerifier string, err error)
+
+	GetUserIdentity(ctx context.Context, issuer, subject string) (*entity.UserIdentity, error)
+	CreateUserIdentity(ctx context.Context, identity *entity.UserIdentity) error
+	UpdateUserIdentityLogin(ctx context.Context, id uuid.UUID, email string, lastLoginAt time.Time) error
+
+	CreateAuthSession(ctx context.Context, authSession *entity.AuthSession) error
+	GetAuthSessionByTokenHash(ctx context.Context, tokenHash string) (*entity.AuthSession, error)
+	// GetAuthSessionByRotatedTokenHash finds the session a refresh token was rotated out of
+	GetAuthSessionByRotatedTokenHash(ctx context.Context, tokenHash string) (*entity.AuthSession, error)
+	GetAuthSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]entity.AuthSession, error)
+	// RotateAuthSession replaces the session's token, provided it still holds oldTokenHash
+	RotateAuthSession(ctx context.Context, authSession *entity.AuthSession, oldTokenHash string) error
+	DeleteAuthSession(ctx context.Context, userID, id uuid.UUID) error
+	DeleteAuthSessionsByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteExpiredAuthSessions(ctx context.Context) (int64, error)
 }
 
 type IAuthService interface {
@@ -26,10 +74,29 @@ type IAuthService interface {
 	CheckOTPRegisterUser(ctx context.Context, email, otp string) error
 	RegisterUser(ctx context.Context, req dto.RegisterUserRequest) (dto.LoginResponse, error)
 	Login(ctx context.Context, req dto.LoginUserRequest) (dto.LoginResponse, error)
+	UnlockLogin(ctx context.Context, userID uuid.UUID) error
+	// LoginMFA completes a login that returned an MFA challenge
+	LoginMFA(ctx context.Context, req dto.MFALoginRequest) (dto.LoginResponse, error)
+	// StartMFALoginEnrollment enrolls a user whose role requires MFA during login, before they have a session
+	StartMFALoginEnrollment(ctx context.Context, mfaToken string) (dto.MFAEnrollmentResponse, error)
+
+	// StartOIDCLogin returns the identity provider URL to send the user to
+	StartOIDCLogin(ctx context.Context) (string, error)
+	// LoginOIDC completes the login with what the identity provider redirected back with
+	LoginOIDC(ctx context.Context, req dto.OIDCCallbackRequest) (dto.LoginResponse, error)
+
+	// RequestMagicLink emails a single use login link. Unregistered emails get no email, but the same response.
+	RequestMagicLink(ctx context.Context, email string) error
+	LoginMagicLink(ctx context.Context, req dto.MagicLinkLoginRequest) (dto.LoginResponse, error)
 
 	RefreshToken(ctx context.Context, refreshToken string) (dto.LoginResponse, error)
 	Logout(ctx context.Context) error
 
+	GetSessions(ctx context.Context) ([]dto.AuthSessionResponse, error)
+	RevokeSession(ctx context.Context, id uuid.UUID) error
+	RevokeAllSessions(ctx context.Context) error
+	DeleteExpiredSessions(ctx context.Context) error
+
 	RequestOTPResetPassword(ctx context.Context, email string) error
 	ResetPassword(ctx context.Context, req dto.ResetPasswordRequest) (dto.LoginResponse, error)
 }
@@ -18,7 +18,33 @@ type IConferenceService interface {
 	UpdateConference(ctx context.Context, id uuid.UUID, req dto.UpdateConferenceRequest) error
 	DeleteConference(ctx context.Context, id uuid.UUID) error
 
-	UpdateConferenceStatus(ctx context.Context, id uuid.UUID, status enum.ConferenceStatus) error
+	UpdateConferenceStatus(ctx context.Context, id uuid.UUID, status enum.ConferenceStatus, comment *string) error
+	ResubmitConference(ctx context.Context, id uuid.UUID, comment *string) error
+	CancelConference(ctx context.Context, id uuid.UUID, reason string) error
+
+	RequestReschedule(ctx context.Context, id uuid.UUID, req *dto.CreateRescheduleRequest) (uuid.UUID, error)
+	GetRescheduleRequests(ctx context.Context, conferenceID uuid.UUID) ([]dto.RescheduleRequestResponse, error)
+	// ReviewRescheduleRequest approves or rejects a reschedule request. On approval, it returns the attendees
+	// whose other registrations conflict with the new schedule.
+	ReviewRescheduleRequest(ctx context.Context, requestID uuid.UUID, status enum.RescheduleStatus,
+		comment *string) ([]dto.UserResponse, error)
+
+	// IsConferenceHost reports whether the user is the owner or a co-host of the conference.
+	IsConferenceHost(ctx context.Context, conferenceID, userID uuid.UUID) (bool, error)
+	// GetConferenceForStaff returns the conference if the requester is one of its hosts or a staff member.
+	GetConferenceForStaff(ctx context.Context, conferenceID uuid.UUID) (*dto.ConferenceResponse, error)
+	GetConferenceHosts(ctx context.Context, conferenceID uuid.UUID) ([]dto.ConferenceHostResponse, error)
+	AddCoHost(ctx context.Context, conferenceID, userID uuid.UUID) error
+	RemoveCoHost(ctx context.Context, conferenceID, userID uuid.UUID) error
+	TransferOwnership(ctx context.Context, conferenceID, toUserID uuid.UUID) (uuid.UUID, error)
+	GetPendingOwnershipTransfers(ctx context.Context) ([]dto.OwnershipTransferResponse, error)
+	RespondOwnershipTransfer(ctx context.Context, transferID uuid.UUID, accept bool) error
+	CancelOwnershipTransfer(ctx context.Context, transferID uuid.UUID) error
+
+	CreateConferenceSeries(ctx context.Context, req *dto.CreateConferenceSeriesRequest) (uuid.UUID, error)
+	GetConferenceSeriesByID(ctx context.Context, id uuid.UUID) (*dto.ConferenceSeriesResponse, error)
+	UpdateConferenceSeriesStatus(ctx context.Context, id uuid.UUID, status enum.ConferenceStatus,
+		comment *string) error
 }
 
 type IConferenceRepository interface {
@@ -28,6 +54,42 @@ type IConferenceRepository interface {
 	UpdateConference(ctx context.Context, conference *entity.Conference) error
 	DeleteConference(ctx context.Context, id uuid.UUID) error
 
-	GetConferencesConflictingWithTime(ctx context.Context, startsAt, endsAt time.Time,
+	GetConferencesConflictingWithTime(ctx context.Context, roomID uuid.UUID, startsAt, endsAt time.Time,
 		excludeID uuid.UUID) ([]entity.Conference, error)
+
+	CreateConferenceSeries(ctx context.Context, series *entity.ConferenceSeries,
+		conferences []entity.Conference) error
+	GetConferenceSeriesByID(ctx context.Context, id uuid.UUID) (*entity.ConferenceSeries, error)
+	GetConferencesBySeriesID(ctx context.Context, seriesID uuid.UUID) ([]entity.Conference, error)
+	// UpdateConferencesStatus moves the conference of every review from one status to the review status
+	// and records the reviews, all in a single transaction. It returns sql.ErrNoRows and changes nothing
+	// if any of the conferences is no longer in the expected status.
+	UpdateConferencesStatus(ctx context.Context, from enum.ConferenceStatus,
+		reviews []entity.ConferenceReview) error
+	GetConferenceReviews(ctx context.Context, conferenceID uuid.UUID) ([]entity.ConferenceReview, error)
+	GetConferenceRatingStats(ctx context.Context, conferenceIDs []uuid.UUID) ([]entity.ConferenceRatingStats, error)
+
+	// CancelConference marks an approved conference as cancelled and closes its waitlist.
+	// It returns sql.ErrNoRows if the conference is not approved anymore.
+	CancelConference(ctx context.Context, id uuid.UUID, reason string, cancelledBy uuid.UUID) error
+	GetRegisteredUsersByConference(ctx context.Context, conferenceID uuid.UUID) ([]entity.User, error)
+
+	CreateRescheduleRequest(ctx context.Context, request *entity.RescheduleRequest) error
+	GetRescheduleRequestByID(ctx context.Context, id uuid.UUID) (*entity.RescheduleRequest, error)
+	GetRescheduleRequestsByConference(ctx context.Context, conferenceID uuid.UUID) ([]entity.RescheduleRequest, error)
+	RejectRescheduleRequest(ctx context.Context, request *entity.RescheduleRequest) error
+	// ApproveRescheduleRequest moves the conference to the requested schedule and asks every active registration
+	// to reconfirm before reconfirmBy. It returns sql.ErrNoRows if the request or conference changed meanwhile.
+	ApproveRescheduleRequest(ctx context.Context, request *entity.RescheduleRequest, reconfirmBy time.Time) error
+
+	GetConferenceHosts(ctx context.Context, conferenceID uuid.UUID) ([]entity.ConferenceHost, error)
+	GetConferenceHost(ctx context.Context, conferenceID, userID uuid.UUID) (*entity.ConferenceHost, error)
+	AddConferenceHost(ctx context.Context, host *entity.ConferenceHost) error
+	RemoveConferenceHost(ctx context.Context, conferenceID, userID uuid.UUID) error
+	CreateOwnershipTransfer(ctx context.Context, transfer *entity.OwnershipTransfer) error
+	GetOwnershipTransferByID(ctx context.Context, id uuid.UUID) (*entity.OwnershipTransfer, error)
+	GetPendingOwnershipTransfersByUser(ctx context.Context, userID uuid.UUID) ([]entity.OwnershipTransfer, error)
+	UpdateOwnershipTransferStatus(ctx context.Context, id uuid.UUID, status enum.OwnershipTransferStatus) error
+	// AcceptOwnershipTransfer makes the recipient the owner and demotes the previous owner to co-host.
+	AcceptOwnershipTransfer(ctx context.Context, transfer *entity.OwnershipTransfer) error
 }
@@ -9,15 +9,41 @@ import (
 
 type IFeedbackRepository interface {
 	CreateFeedback(ctx context.Context, feedback *entity.Feedback) error
-	GetFeedbacksByConferenceID(ctx context.Context, conferenceID uuid.UUID,
-		lazyReq dto.LazyLoadQuery) ([]entity.Feedback, dto.LazyLoadResponse, error)
-	DeleteFeedback(ctx context.Context, id uuid.UUID) error
+	// GetFeedbacksByConferenceID leaves out hidden feedback unless includeHidden is set
+	GetFeedbacksByConferenceID(ctx context.Context, conferenceID uuid.UUID, lazyReq dto.LazyLoadQuery,
+		filter dto.FeedbackFilterQuery, includeHidden bool) ([]entity.Feedback, dto.LazyLoadResponse, error)
+	GetFeedbackByID(ctx context.Context, id uuid.UUID) (*entity.Feedback, error)
 	IsFeedbackGiven(ctx context.Context, userID, conferenceID uuid.UUID) (bool, error)
+
+	CreateFeedbackReply(ctx context.Context, reply *entity.FeedbackReply) error
+	UpdateFeedbackReply(ctx context.Context, reply *entity.FeedbackReply) error
+	DeleteFeedbackReply(ctx context.Context, feedbackID uuid.UUID) error
+
+	// CreateFeedbackFlag stores the flag and hides the feedback once it has autoHideThreshold open flags,
+	// recording autoHide as the moderation action. It reports whether the feedback got hidden.
+	CreateFeedbackFlag(ctx context.Context, flag *entity.FeedbackFlag, autoHideThreshold int,
+		autoHide *entity.FeedbackModerationAction) (bool, error)
+	GetFlaggedFeedbacks(ctx context.Context, lazyReq dto.LazyLoadQuery) ([]entity.Feedback, dto.LazyLoadResponse, error)
+	GetOpenFlags(ctx context.Context, feedbackIDs []uuid.UUID) ([]entity.FeedbackFlag, error)
+	// ModerateFeedback applies the action (hide, restore, dismiss or delete), resolves the open flags of the
+	// feedback and records the action, in a single transaction. It returns sql.ErrNoRows if the feedback is gone.
+	ModerateFeedback(ctx context.Context, action *entity.FeedbackModerationAction) error
+	GetModerationActions(ctx context.Context, feedbackID uuid.UUID) ([]entity.FeedbackModerationAction, error)
 }
 
 type IFeedbackService interface {
-	CreateFeedback(ctx context.Context, userID, conferenceID uuid.UUID, comment string) (uuid.UUID, error)
-	GetFeedbacksByConferenceID(ctx context.Context, conferenceID uuid.UUID,
-		lazyReq dto.LazyLoadQuery) ([]dto.FeedbackResponse, dto.LazyLoadResponse, error)
+	CreateFeedback(ctx context.Context, userID uuid.UUID, req dto.CreateFeedbackRequest) (uuid.UUID, error)
+	GetFeedbacksByConferenceID(ctx context.Context, conferenceID uuid.UUID, lazyReq dto.LazyLoadQuery,
+		filter dto.FeedbackFilterQuery) ([]dto.FeedbackResponse, dto.LazyLoadResponse, error)
 	DeleteFeedback(ctx context.Context, id uuid.UUID) error
+
+	CreateReply(ctx context.Context, feedbackID uuid.UUID, body string) (dto.FeedbackReplyResponse, error)
+	UpdateReply(ctx context.Context, feedbackID uuid.UUID, body string) error
+	DeleteReply(ctx context.Context, feedbackID uuid.UUID) error
+
+	FlagFeedback(ctx context.Context, feedbackID uuid.UUID, req dto.FlagFeedbackRequest) error
+	GetModerationQueue(ctx context.Context,
+		lazyReq dto.LazyLoadQuery) ([]dto.FlaggedFeedbackResponse, dto.LazyLoadResponse, error)
+	ModerateFeedback(ctx context.Context, feedbackID uuid.UUID, req dto.ModerateFeedbackRequest) error
+	GetModerationHistory(ctx context.Context, feedbackID uuid.UUID) ([]dto.FeedbackModerationActionResponse, error)
 }
@@ -0,0 +1,33 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+)
+
+type IKioskService interface {
+	CreateDevice(ctx context.Context, req *dto.CreateKioskDeviceRequest) (dto.CreateKioskDeviceResponse, error)
+	GetDevicesByConference(ctx context.Context, conferenceID uuid.UUID) ([]dto.KioskDeviceResponse, error)
+	RevokeDevice(ctx context.Context, id uuid.UUID) error
+
+	// GetManifest and Sync are called by the device itself, identified by the device ID from its token.
+	GetManifest(ctx context.Context, deviceID uuid.UUID) (dto.KioskManifestResponse, error)
+	Sync(ctx context.Context, deviceID uuid.UUID, req *dto.KioskSyncRequest) ([]dto.KioskScanResultResponse, error)
+}
+
+type IKioskRepository interface {
+	CreateDevice(ctx context.Context, device *entity.KioskDevice) error
+	GetDeviceByID(ctx context.Context, id uuid.UUID) (*entity.KioskDevice, error)
+	GetDevicesByConference(ctx context.Context, conferenceID uuid.UUID) ([]entity.KioskDevice, error)
+	RevokeDevice(ctx context.Context, id uuid.UUID) error
+	MarkDeviceSynced(ctx context.Context, id uuid.UUID) error
+
+	GetManifestRegistrations(ctx context.Context, conferenceID uuid.UUID) ([]entity.Registration, error)
+	// RecordScan stores the scan and, unless it was already rejected, checks the attendee in. The earliest scan
+	// of a ticket wins, whichever order the devices upload in. If the device already uploaded the scan, the
+	// stored result is loaded into scan instead.
+	RecordScan(ctx context.Context, scan *entity.KioskScan, conferenceID uuid.UUID) error
+}
@@ -0,0 +1,43 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+)
+
+type IMFAService interface {
+	GetStatus(ctx context.Context) (dto.MFAStatusResponse, error)
+	IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error)
+
+	// StartEnrollment and ConfirmEnrollment take the user ID, since they are also used while logging in,
+	// before there is a requester.
+	StartEnrollment(ctx context.Context, userID uuid.UUID) (dto.MFAEnrollmentResponse, error)
+	// ConfirmEnrollment enables MFA once the user proves the app works, and returns the recovery codes
+	ConfirmEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	// Verify accepts either a code or a recovery code. Neither can be used again.
+	Verify(ctx context.Context, userID uuid.UUID, req dto.VerifyMFARequest) error
+
+	RegenerateRecoveryCodes(ctx context.Context, req dto.VerifyMFARequest) ([]string, error)
+	Disable(ctx context.Context, req dto.VerifyMFARequest) error
+	// Reset removes a user's MFA for them, for when they lost both their app and recovery codes
+	Reset(ctx context.Context, userID uuid.UUID) error
+}
+
+type IMFARepository interface {
+	GetMFA(ctx context.Context, userID uuid.UUID) (*entity.UserMFA, error)
+	// SavePendingMFA starts or restarts an enrollment. It returns sql.ErrNoRows if MFA is already enabled.
+	SavePendingMFA(ctx context.Context, mfa *entity.UserMFA) error
+	// EnableMFA confirms a pending enrollment, replacing any recovery codes
+	EnableMFA(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error
+	// UseStep records the time step of an accepted code. It returns sql.ErrNoRows if that step, or a later one,
+	// was already used.
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) error
+	// UseRecoveryCode marks the code as used. It returns sql.ErrNoRows if there is no such unused code.
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error)
+	DeleteMFA(ctx context.Context, userID uuid.UUID) error
+}
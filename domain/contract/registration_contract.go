@@ -7,29 +7,63 @@ import (
 	"github.com/google/uuid"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
 )
 
 type IRegistrationRepository interface {
-	CreateRegistration(ctx context.Context, registration *entity.Registration) error
+	CreateRegistrationWithinCapacity(ctx context.Context, registration *entity.Registration) (bool, error)
 
 	GetRegisteredUsersByConference(ctx context.Context, conferenceID uuid.UUID,
 		lazyReq dto.LazyLoadQuery) ([]entity.User, dto.LazyLoadResponse, error)
 	GetRegisteredConferencesByUser(ctx context.Context, userID uuid.UUID, includePast bool,
-		lazyReq dto.LazyLoadQuery) ([]entity.Conference, dto.LazyLoadResponse, error)
+		lazyReq dto.LazyLoadQuery) ([]entity.Registration, dto.LazyLoadResponse, error)
 
 	IsUserRegisteredToConference(ctx context.Context, conferenceID, userID uuid.UUID) (bool, error)
 	GetConflictingRegistrations(ctx context.Context, userID uuid.UUID, startsAt,
-		endsAt time.Time) ([]entity.Conference, error)
+		endsAt time.Time, excludeConferenceID uuid.UUID) ([]entity.Conference, error)
 	CountRegistrationsByConference(ctx context.Context, conferenceID uuid.UUID) (int, error)
+	CancelRegistration(ctx context.Context, conferenceID, userID, cancelledBy uuid.UUID) error
+	GetActiveRegistration(ctx context.Context, conferenceID, userID uuid.UUID) (*entity.Registration, error)
+	ReconfirmRegistration(ctx context.Context, conferenceID, userID uuid.UUID) error
+	// ReleaseUnconfirmedRegistrations cancels registrations whose reconfirmation deadline has passed and
+	// returns the affected conference IDs.
+	ReleaseUnconfirmedRegistrations(ctx context.Context) ([]uuid.UUID, error)
+	// CheckInRegistration marks the registration holding the ticket as attended. It returns sql.ErrNoRows if the
+	// ticket was revoked or already used in the meantime.
+	CheckInRegistration(ctx context.Context, registration *entity.Registration) error
+	CountAttendance(ctx context.Context, conferenceID uuid.UUID) (registered int, checkedIn int, err error)
+
+	CreateWaitlistEntry(ctx context.Context, entry *entity.WaitlistEntry) error
+	GetActiveWaitlistEntry(ctx context.Context, conferenceID, userID uuid.UUID) (*entity.WaitlistEntry, error)
+	GetWaitlistPosition(ctx context.Context, entry *entity.WaitlistEntry) (int, error)
+	UpdateWaitlistEntryStatus(ctx context.Context, id uuid.UUID, status enum.WaitlistStatus) error
+	CountActiveWaitlistOffers(ctx context.Context, conferenceID, excludeUserID uuid.UUID) (int, error)
+	OfferWaitlistSeats(ctx context.Context, conferenceID uuid.UUID,
+		offerExpiresAt time.Time) ([]entity.WaitlistEntry, error)
+	ExpireWaitlistOffers(ctx context.Context) ([]entity.WaitlistEntry, error)
 }
 
 type IRegistrationService interface {
 	Register(ctx context.Context, conferenceID, userID uuid.UUID) error
+	CancelRegistration(ctx context.Context, conferenceID, userID uuid.UUID) error
+	ReconfirmRegistration(ctx context.Context, conferenceID, userID uuid.UUID) error
+	ReleaseUnconfirmedRegistrations(ctx context.Context) error
 
 	GetRegisteredUsersByConference(ctx context.Context, conferenceID uuid.UUID,
 		lazyReq dto.LazyLoadQuery) ([]dto.UserResponse, dto.LazyLoadResponse, error)
 	GetRegisteredConferencesByUser(ctx context.Context, userID uuid.UUID,
-		includePast bool, lazyReq dto.LazyLoadQuery) ([]dto.ConferenceResponse, dto.LazyLoadResponse, error)
+		includePast bool, lazyReq dto.LazyLoadQuery) ([]dto.RegisteredConferenceResponse, dto.LazyLoadResponse,
+		error)
+
+	CheckIn(ctx context.Context, conferenceID uuid.UUID, ticket string) (dto.CheckInResponse, error)
+	GetAttendance(ctx context.Context, conferenceID uuid.UUID) (dto.AttendanceResponse, error)
 
 	IsUserRegisteredToConference(ctx context.Context, conferenceID, userID uuid.UUID) (bool, error)
+
+	JoinWaitlist(ctx context.Context, conferenceID, userID uuid.UUID) (dto.WaitlistEntryResponse, error)
+	GetWaitlistEntry(ctx context.Context, conferenceID, userID uuid.UUID) (dto.WaitlistEntryResponse, error)
+	LeaveWaitlist(ctx context.Context, conferenceID, userID uuid.UUID) error
+	ConfirmWaitlistOffer(ctx context.Context, conferenceID, userID uuid.UUID) error
+	PromoteWaitlist(ctx context.Context, conferenceID uuid.UUID) error
+	ExpireWaitlistOffers(ctx context.Context) error
 }
@@ -0,0 +1,30 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+)
+
+type IRoomService interface {
+	CreateRoom(ctx context.Context, req *dto.CreateRoomRequest) (uuid.UUID, error)
+	GetRoomByID(ctx context.Context, id uuid.UUID) (*dto.RoomResponse, error)
+	GetRooms(ctx context.Context, lazy dto.LazyLoadQuery) ([]dto.RoomResponse, dto.LazyLoadResponse, error)
+	UpdateRoom(ctx context.Context, id uuid.UUID, req dto.UpdateRoomRequest) error
+	DeleteRoom(ctx context.Context, id uuid.UUID) error
+}
+
+type IRoomRepository interface {
+	CreateRoom(ctx context.Context, room *entity.Room) error
+	GetRoomByID(ctx context.Context, id uuid.UUID) (*entity.Room, error)
+	GetRooms(ctx context.Context, lazy dto.LazyLoadQuery) ([]entity.Room, dto.LazyLoadResponse, error)
+	UpdateRoom(ctx context.Context, room *entity.Room) error
+	DeleteRoom(ctx context.Context, id uuid.UUID) error
+
+	// GetUpcomingConferencesExceedingSeats returns conferences in the room that have not ended yet
+	// and are pending or approved with more seats than the given amount.
+	GetUpcomingConferencesExceedingSeats(ctx context.Context, roomID uuid.UUID,
+		seats int) ([]entity.Conference, error)
+}
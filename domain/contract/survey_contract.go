@@ -0,0 +1,48 @@
+package contract
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+)
+
+type ISurveyService interface {
+	CreateTemplate(ctx context.Context, req dto.CreateSurveyTemplateRequest) (dto.SurveyTemplateResponse, error)
+	GetTemplates(ctx context.Context,
+		lazyReq dto.LazyLoadQuery) ([]dto.SurveyTemplateResponse, dto.LazyLoadResponse, error)
+	GetTemplateByID(ctx context.Context, id uuid.UUID) (*dto.SurveyTemplateResponse, error)
+	DeleteTemplate(ctx context.Context, id uuid.UUID) error
+
+	AttachTemplate(ctx context.Context, conferenceID, templateID uuid.UUID) error
+	DetachTemplate(ctx context.Context, conferenceID uuid.UUID) error
+	GetConferenceSurvey(ctx context.Context, conferenceID uuid.UUID) (*dto.SurveyTemplateResponse, error)
+
+	// ValidateAnswers checks the answers against the survey attached to the conference and turns them into
+	// answer rows, without a feedback ID yet.
+	ValidateAnswers(ctx context.Context, conferenceID uuid.UUID,
+		answers []dto.SurveyAnswerRequest) ([]entity.SurveyAnswer, error)
+	GetResults(ctx context.Context, conferenceID uuid.UUID) (dto.SurveyResultsResponse, error)
+	// ExportResponses returns the survey responses of the conference as CSV, one row per feedback
+	ExportResponses(ctx context.Context, conferenceID uuid.UUID) ([]byte, error)
+}
+
+type ISurveyRepository interface {
+	CreateTemplate(ctx context.Context, template *entity.SurveyTemplate) error
+	GetTemplates(ctx context.Context,
+		lazyReq dto.LazyLoadQuery) ([]entity.SurveyTemplate, dto.LazyLoadResponse, error)
+	// GetTemplateByID returns the template with its questions, including soft deleted templates
+	GetTemplateByID(ctx context.Context, id uuid.UUID) (*entity.SurveyTemplate, error)
+	DeleteTemplate(ctx context.Context, id uuid.UUID) error
+
+	AttachTemplate(ctx context.Context, survey *entity.ConferenceSurvey) error
+	DetachTemplate(ctx context.Context, conferenceID uuid.UUID) error
+	GetConferenceSurvey(ctx context.Context, conferenceID uuid.UUID) (*entity.ConferenceSurvey, error)
+	HasAnswers(ctx context.Context, conferenceID uuid.UUID) (bool, error)
+
+	// CountAnswers leaves out deleted and hidden feedback
+	CountAnswers(ctx context.Context, conferenceID uuid.UUID) ([]dto.SurveyAnswerCountRow, int, error)
+	// GetAnswersByConference returns the feedbacks that answered the survey, with their answers
+	GetAnswersByConference(ctx context.Context, conferenceID uuid.UUID) ([]entity.Feedback, error)
+}
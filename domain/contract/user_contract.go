@@ -13,6 +13,8 @@ type IUserRepository interface {
 	CreateUser(ctx context.Context, user *entity.User) error
 	GetUserByField(ctx context.Context, field, value string) (*entity.User, error)
 	UpdateUser(ctx context.Context, user *entity.User) error
+	// UpdatePasswordHash replaces the password hash only if it still is oldHash
+	UpdatePasswordHash(ctx context.Context, id uuid.UUID, oldHash, newHash string) error
 	DeleteUser(ctx context.Context, id uuid.UUID) error
 	UploadProfile(ctx context.Context, id uuid.UUID, url string) error
 }
@@ -21,6 +23,9 @@ type IUserService interface {
 	CreateUser(ctx context.Context, req *dto.CreateUserRequest) (uuid.UUID, error)
 	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
+	// CheckPassword reports whether the password is the user's. Legacy plaintext and outdated hashes are
+	// rehashed on a match.
+	CheckPassword(ctx context.Context, user *entity.User, password string) bool
 	UpdatePassword(ctx context.Context, email, newPassword string) error
 	UpdateUser(ctx context.Context, id uuid.UUID, req dto.UpdateUserRequest) error
 	DeleteUser(ctx context.Context, id uuid.UUID) error
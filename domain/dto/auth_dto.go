@@ -1,5 +1,12 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+)
+
 type RequestOTPRegisterUserRequest struct {
 	Email string `json:"email" validate:"required,email,max=320"`
 }
@@ -21,10 +28,44 @@ type LoginUserRequest struct {
 	Password string `json:"password" validate:"required,ascii"`
 }
 
+// LoginResponse either carries the tokens, or an MFA challenge to complete first
 type LoginResponse struct {
-	AccessToken  string        `json:"access_token"`
-	RefreshToken string        `json:"refresh_token"`
-	User         *UserResponse `json:"user"`
+	AccessToken  string                `json:"access_token,omitempty"`
+	RefreshToken string                `json:"refresh_token,omitempty"`
+	User         *UserResponse         `json:"user,omitempty"`
+	MFA          *MFAChallengeResponse `json:"mfa,omitempty"`
+	// RecoveryCodes is only set when the login also completed an MFA enrollment
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+type MFAChallengeResponse struct {
+	Token string `json:"token"`
+	// EnrollmentRequired is set when the role requires MFA but the user hasn't enrolled yet
+	EnrollmentRequired bool `json:"enrollment_required"`
+}
+
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfa_token" validate:"required"`
+	Code         string `json:"code" validate:"required_without=RecoveryCode,omitempty,numeric,len=6"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code,omitempty,max=32"`
+}
+
+type MFAEnrollLoginRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" validate:"required,max=2048"`
+	State string `json:"state" validate:"required,max=256"`
+}
+
+type RequestMagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email,max=320"`
+}
+
+type MagicLinkLoginRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Token string `json:"token" validate:"required,max=64"`
 }
 
 type RefreshTokenRequest struct {
@@ -40,3 +81,26 @@ type ResetPasswordRequest struct {
 	OTP         string `json:"otp" validate:"required"`
 	NewPassword string `json:"new_password" validate:"required,min=8,max=72,ascii"`
 }
+
+type AuthSessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	IsCurrent  bool      `json:"is_current"`
+}
+
+func (a *AuthSessionResponse) PopulateFromEntity(session *entity.AuthSession,
+	currentSessionID uuid.UUID) *AuthSessionResponse {
+
+	a.ID = session.ID
+	a.UserAgent = session.UserAgent
+	a.IPAddress = session.IPAddress
+	a.CreatedAt = session.CreatedAt
+	a.LastUsedAt = session.LastUsedAt
+	a.ExpiresAt = session.ExpiresAt
+	a.IsCurrent = session.ID == currentSessionID
+	return a
+}
@@ -9,21 +9,28 @@ import (
 )
 
 type ConferenceResponse struct {
-	ID             uuid.UUID             `json:"id"`
-	Title          string                `json:"title,omitempty"`
-	Description    string                `json:"description,omitempty"`
-	SpeakerName    string                `json:"speaker_name,omitempty"`
-	SpeakerTitle   string                `json:"speaker_title,omitempty"`
-	TargetAudience string                `json:"target_audience,omitempty"`
-	Prerequisites  *string               `json:"prerequisites,omitempty"`
-	Seats          int                   `json:"seats,omitempty"`
-	StartsAt       *time.Time            `json:"starts_at,omitempty"`
-	EndsAt         *time.Time            `json:"ends_at,omitempty"`
-	Host           *UserResponse         `json:"host,omitempty"`
-	Status         enum.ConferenceStatus `json:"status,omitempty"`
-	CreatedAt      *time.Time            `json:"created_at,omitempty"`
-	UpdatedAt      *time.Time            `json:"updated_at,omitempty"`
-	SeatsTaken     *int                  `json:"seats_taken,omitempty"`
+	ID                 uuid.UUID                  `json:"id"`
+	Title              string                     `json:"title,omitempty"`
+	Description        string                     `json:"description,omitempty"`
+	SpeakerName        string                     `json:"speaker_name,omitempty"`
+	SpeakerTitle       string                     `json:"speaker_title,omitempty"`
+	TargetAudience     string                     `json:"target_audience,omitempty"`
+	Prerequisites      *string                    `json:"prerequisites,omitempty"`
+	Seats              int                        `json:"seats,omitempty"`
+	StartsAt           *time.Time                 `json:"starts_at,omitempty"`
+	EndsAt             *time.Time                 `json:"ends_at,omitempty"`
+	Host               *UserResponse              `json:"host,omitempty"`
+	Room               *RoomResponse              `json:"room,omitempty"`
+	SeriesID           *uuid.UUID                 `json:"series_id,omitempty"`
+	Status             enum.ConferenceStatus      `json:"status,omitempty"`
+	CreatedAt          *time.Time                 `json:"created_at,omitempty"`
+	UpdatedAt          *time.Time                 `json:"updated_at,omitempty"`
+	SeatsTaken         *int                       `json:"seats_taken,omitempty"`
+	CancellationReason *string                    `json:"cancellation_reason,omitempty"`
+	CancelledAt        *time.Time                 `json:"cancelled_at,omitempty"`
+	Reviews            []ConferenceReviewResponse `json:"reviews,omitempty"`
+	Hosts              []ConferenceHostResponse   `json:"hosts,omitempty"`
+	Rating             *ConferenceRatingResponse  `json:"rating,omitempty"`
 }
 
 func (c *ConferenceResponse) PopulateFromEntity(conference *entity.Conference) *ConferenceResponse {
@@ -37,15 +44,81 @@ func (c *ConferenceResponse) PopulateFromEntity(conference *entity.Conference) *
 	c.Seats = conference.Seats
 	c.StartsAt = &conference.StartsAt
 	c.EndsAt = &conference.EndsAt
+	c.SeriesID = conference.SeriesID
 	c.Status = conference.Status
 	c.CreatedAt = &conference.CreatedAt
 	c.UpdatedAt = &conference.UpdatedAt
+	c.CancellationReason = conference.CancellationReason
+	c.CancelledAt = conference.CancelledAt
 
 	c.SeatsTaken = &conference.RegistrationCount
 	c.Host = new(UserResponse).PopulateMinimalFromEntity(&conference.Host)
+	c.Room = new(RoomResponse).PopulateMinimalFromEntity(&conference.Room)
 	return c
 }
 
+// IsHostedBy reports whether the user is the owner or a co-host. Hosts must have been populated.
+func (c *ConferenceResponse) IsHostedBy(userID uuid.UUID) bool {
+	for _, host := range c.Hosts {
+		if host.User != nil && host.User.ID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+type ConferenceReviewResponse struct {
+	ID        uuid.UUID             `json:"id"`
+	Reviewer  *UserResponse         `json:"reviewer,omitempty"`
+	Status    enum.ConferenceStatus `json:"status,omitempty"`
+	Comment   *string               `json:"comment,omitempty"`
+	CreatedAt *time.Time            `json:"created_at,omitempty"`
+}
+
+func (r *ConferenceReviewResponse) PopulateFromEntity(review *entity.ConferenceReview) *ConferenceReviewResponse {
+	r.ID = review.ID
+	r.Reviewer = new(UserResponse).PopulateMinimalFromEntity(&review.Reviewer)
+	r.Status = review.Status
+	r.Comment = review.Comment
+	r.CreatedAt = &review.CreatedAt
+	return r
+}
+
+type ConferenceHostResponse struct {
+	User      *UserResponse `json:"user,omitempty"`
+	Role      enum.HostRole `json:"role,omitempty"`
+	CreatedAt *time.Time    `json:"created_at,omitempty"`
+}
+
+func (h *ConferenceHostResponse) PopulateFromEntity(host *entity.ConferenceHost) *ConferenceHostResponse {
+	h.User = new(UserResponse).PopulateMinimalFromEntity(&host.User)
+	h.Role = host.Role
+	h.CreatedAt = &host.CreatedAt
+	return h
+}
+
+type OwnershipTransferResponse struct {
+	ID           uuid.UUID                    `json:"id"`
+	ConferenceID uuid.UUID                    `json:"conference_id,omitempty"`
+	FromUserID   uuid.UUID                    `json:"from_user_id,omitempty"`
+	ToUserID     uuid.UUID                    `json:"to_user_id,omitempty"`
+	Status       enum.OwnershipTransferStatus `json:"status,omitempty"`
+	CreatedAt    *time.Time                   `json:"created_at,omitempty"`
+	RespondedAt  *time.Time                   `json:"responded_at,omitempty"`
+}
+
+func (t *OwnershipTransferResponse) PopulateFromEntity(transfer *entity.OwnershipTransfer) *OwnershipTransferResponse {
+	t.ID = transfer.ID
+	t.ConferenceID = transfer.ConferenceID
+	t.FromUserID = transfer.FromUserID
+	t.ToUserID = transfer.ToUserID
+	t.Status = transfer.Status
+	t.CreatedAt = &transfer.CreatedAt
+	t.RespondedAt = transfer.RespondedAt
+	return t
+}
+
 type CreateConferenceProposalRequest struct {
 	Title          string
 	Description    string
@@ -56,6 +129,44 @@ type CreateConferenceProposalRequest struct {
 	Seats          int
 	StartsAt       time.Time
 	EndsAt         time.Time
+	RoomID         uuid.UUID
+}
+
+type CreateConferenceSeriesRequest struct {
+	CreateConferenceProposalRequest
+	Frequency   enum.RecurrenceFrequency
+	RepeatUntil *time.Time
+	RepeatCount *int
+}
+
+type ConferenceSeriesResponse struct {
+	ID          uuid.UUID                `json:"id"`
+	Frequency   enum.RecurrenceFrequency `json:"frequency,omitempty"`
+	RepeatUntil *time.Time               `json:"repeat_until,omitempty"`
+	RepeatCount *int                     `json:"repeat_count,omitempty"`
+	Host        *UserResponse            `json:"host,omitempty"`
+	CreatedAt   *time.Time               `json:"created_at,omitempty"`
+	Conferences []ConferenceResponse     `json:"conferences,omitempty"`
+}
+
+func (c *ConferenceSeriesResponse) PopulateFromEntity(series *entity.ConferenceSeries,
+	conferences []entity.Conference) *ConferenceSeriesResponse {
+
+	c.ID = series.ID
+	c.Frequency = series.Frequency
+	c.RepeatUntil = series.RepeatUntil
+	c.RepeatCount = series.RepeatCount
+	c.CreatedAt = &series.CreatedAt
+
+	c.Conferences = make([]ConferenceResponse, len(conferences))
+	for i := range conferences {
+		c.Conferences[i].PopulateFromEntity(&conferences[i])
+	}
+
+	if len(conferences) > 0 {
+		c.Host = new(UserResponse).PopulateMinimalFromEntity(&conferences[0].Host)
+	}
+	return c
 }
 
 type GetConferenceQuery struct {
@@ -63,6 +174,7 @@ type GetConferenceQuery struct {
 	BeforeID     *uuid.UUID
 	Limit        int
 	HostID       *uuid.UUID
+	RoomID       *uuid.UUID
 	Status       enum.ConferenceStatus
 	StartsBefore *time.Time
 	StartsAfter  *time.Time
@@ -81,6 +193,7 @@ type UpdateConferenceRequest struct {
 	Prerequisites  *string
 	StartsAt       *time.Time
 	EndsAt         *time.Time
+	RoomID         *uuid.UUID
 }
 
 func (p *UpdateConferenceRequest) GenerateUpdateEntity(original *entity.Conference) *entity.Conference {
@@ -108,6 +221,40 @@ func (p *UpdateConferenceRequest) GenerateUpdateEntity(original *entity.Conferen
 	if p.EndsAt != nil {
 		original.EndsAt = *p.EndsAt
 	}
+	if p.RoomID != nil {
+		original.RoomID = *p.RoomID
+	}
 
 	return original
 }
+
+type RescheduleRequestResponse struct {
+	ID            uuid.UUID             `json:"id"`
+	ConferenceID  uuid.UUID             `json:"conference_id,omitempty"`
+	StartsAt      *time.Time            `json:"starts_at,omitempty"`
+	EndsAt        *time.Time            `json:"ends_at,omitempty"`
+	Reason        *string               `json:"reason,omitempty"`
+	Status        enum.RescheduleStatus `json:"status,omitempty"`
+	ReviewComment *string               `json:"review_comment,omitempty"`
+	ReviewedAt    *time.Time            `json:"reviewed_at,omitempty"`
+	CreatedAt     *time.Time            `json:"created_at,omitempty"`
+}
+
+func (r *RescheduleRequestResponse) PopulateFromEntity(request *entity.RescheduleRequest) *RescheduleRequestResponse {
+	r.ID = request.ID
+	r.ConferenceID = request.ConferenceID
+	r.StartsAt = &request.StartsAt
+	r.EndsAt = &request.EndsAt
+	r.Reason = request.Reason
+	r.Status = request.Status
+	r.ReviewComment = request.ReviewComment
+	r.ReviewedAt = request.ReviewedAt
+	r.CreatedAt = &request.CreatedAt
+	return r
+}
+
+type CreateRescheduleRequest struct {
+	StartsAt time.Time
+	EndsAt   time.Time
+	Reason   *string
+}
@@ -20,11 +20,18 @@ type ConferenceJoinUserRow struct {
 	StartsAt       time.Time             `db:"starts_at"`
 	EndsAt         time.Time             `db:"ends_at"`
 	HostID         uuid.UUID             `db:"host_id"`
+	RoomID         uuid.UUID             `db:"room_id"`
+	SeriesID       *uuid.UUID            `db:"series_id"`
 	Status         enum.ConferenceStatus `db:"status"`
 	CreatedAt      time.Time             `db:"created_at"`
 	UpdatedAt      time.Time             `db:"updated_at"`
 
+	CancellationReason *string    `db:"cancellation_reason"`
+	CancelledAt        *time.Time `db:"cancelled_at"`
+
 	HostName          string `db:"host_name"`
+	RoomName          string `db:"room_name"`
+	RoomBuilding      string `db:"room_building"`
 	RegistrationCount int    `db:"registration_count"`
 }
 
@@ -41,13 +48,23 @@ func (r *ConferenceJoinUserRow) ToEntity() entity.Conference {
 		StartsAt:       r.StartsAt,
 		EndsAt:         r.EndsAt,
 		HostID:         r.HostID,
+		RoomID:         r.RoomID,
+		SeriesID:       r.SeriesID,
 		Status:         r.Status,
 		CreatedAt:      r.CreatedAt,
 		UpdatedAt:      r.UpdatedAt,
+
+		CancellationReason: r.CancellationReason,
+		CancelledAt:        r.CancelledAt,
 		Host: entity.User{
 			ID:   r.HostID,
 			Name: r.HostName,
 		},
+		Room: entity.Room{
+			ID:       r.RoomID,
+			Name:     r.RoomName,
+			Building: r.RoomBuilding,
+		},
 		RegistrationCount: r.RegistrationCount,
 	}
 }
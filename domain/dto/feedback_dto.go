@@ -1,25 +1,186 @@
 package dto
 
 import (
+	"math"
+
 	"github.com/google/uuid"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
 	"time"
 )
 
 type FeedbackResponse struct {
-	ID        uuid.UUID     `json:"id"`
-	Comment   string        `json:"comment,omitempty"`
-	CreatedAt *time.Time    `json:"created_at,omitempty"`
-	User      *UserResponse `json:"user,omitempty"`
+	ID           uuid.UUID              `json:"id"`
+	Comment      string                 `json:"comment,omitempty"`
+	Rating       *int                   `json:"rating,omitempty"`
+	ContentScore *int                   `json:"content_score,omitempty"`
+	SpeakerScore *int                   `json:"speaker_score,omitempty"`
+	VenueScore   *int                   `json:"venue_score,omitempty"`
+	IsAnonymous  bool                   `json:"is_anonymous"`
+	CreatedAt    *time.Time             `json:"created_at,omitempty"`
+	HiddenAt     *time.Time             `json:"hidden_at,omitempty"`
+	User         *UserResponse          `json:"user,omitempty"`
+	Reply        *FeedbackReplyResponse `json:"reply,omitempty"`
 }
 
 func (f *FeedbackResponse) PopulateFromEntity(feedback *entity.Feedback) *FeedbackResponse {
 	f.ID = feedback.ID
 	f.Comment = feedback.Comment
+	f.Rating = feedback.Rating
+	f.ContentScore = feedback.ContentScore
+	f.SpeakerScore = feedback.SpeakerScore
+	f.VenueScore = feedback.VenueScore
+	f.IsAnonymous = feedback.IsAnonymous
 	f.CreatedAt = &feedback.CreatedAt
+	f.HiddenAt = feedback.HiddenAt
 	f.User = &UserResponse{
 		ID:   feedback.UserID,
 		Name: feedback.User.Name,
 	}
+	if feedback.Reply != nil {
+		f.Reply = new(FeedbackReplyResponse).PopulateFromEntity(feedback.Reply)
+	}
+	return f
+}
+
+// HideAnonymousAuthor drops the author of an anonymous feedback. Only admins may see who wrote it.
+func (f *FeedbackResponse) HideAnonymousAuthor(requesterRole enum.UserRole) *FeedbackResponse {
+	if f.IsAnonymous && requesterRole != enum.RoleAdmin {
+		f.User = nil
+	}
+	return f
+}
+
+type FeedbackReplyResponse struct {
+	ID        uuid.UUID     `json:"id"`
+	Body      string        `json:"body,omitempty"`
+	CreatedAt *time.Time    `json:"created_at,omitempty"`
+	UpdatedAt *time.Time    `json:"updated_at,omitempty"`
+	User      *UserResponse `json:"user,omitempty"`
+}
+
+func (r *FeedbackReplyResponse) PopulateFromEntity(reply *entity.FeedbackReply) *FeedbackReplyResponse {
+	r.ID = reply.ID
+	r.Body = reply.Body
+	r.CreatedAt = &reply.CreatedAt
+	r.UpdatedAt = &reply.UpdatedAt
+	if reply.User != nil {
+		r.User = &UserResponse{
+			ID:   reply.UserID,
+			Name: reply.User.Name,
+		}
+	}
+	return r
+}
+
+type CreateFeedbackRequest struct {
+	ConferenceID uuid.UUID `json:"conference_id" validate:"required,uuid"`
+	Comment      string    `json:"comment" validate:"required,min=3,max=1000"`
+	Rating       int       `json:"rating" validate:"required,min=1,max=5"`
+	ContentScore *int      `json:"content_score" validate:"omitempty,min=1,max=5"`
+	SpeakerScore *int      `json:"speaker_score" validate:"omitempty,min=1,max=5"`
+	VenueScore   *int      `json:"venue_score" validate:"omitempty,min=1,max=5"`
+	IsAnonymous  bool      `json:"is_anonymous"`
+	// SurveyAnswers answers the survey attached to the conference, if any
+	SurveyAnswers []SurveyAnswerRequest `json:"survey_answers" validate:"omitempty,max=30,dive"`
+}
+
+type FeedbackFilterQuery struct {
+	MinRating int `query:"min_rating" validate:"omitempty,min=1,max=5"`
+	MaxRating int `query:"max_rating" validate:"omitempty,min=1,max=5"`
+	// Sort is empty for oldest first, or rating_desc / rating_asc. Ties are broken by feedback ID.
+	Sort string `query:"sort" validate:"omitempty,oneof=rating_desc rating_asc"`
+}
+
+type ConferenceRatingResponse struct {
+	Average      float64     `json:"average"`
+	Count        int         `json:"count"`
+	Distribution map[int]int `json:"distribution"`
+	// Sub-score averages are nil until someone rates that aspect
+	ContentAverage *float64 `json:"content_average,omitempty"`
+	SpeakerAverage *float64 `json:"speaker_average,omitempty"`
+	VenueAverage   *float64 `json:"venue_average,omitempty"`
+}
+
+func averageOf(sum, count int) *float64 {
+	if count == 0 {
+		return nil
+	}
+
+	avg := math.Round(float64(sum)/float64(count)*100) / 100
+	return &avg
+}
+
+func (r *ConferenceRatingResponse) PopulateFromEntity(stats *entity.ConferenceRatingStats) *ConferenceRatingResponse {
+	if avg := averageOf(stats.RatingSum, stats.RatingCount); avg != nil {
+		r.Average = *avg
+	}
+	r.Count = stats.RatingCount
+	r.Distribution = map[int]int{
+		1: stats.Rating1,
+		2: stats.Rating2,
+		3: stats.Rating3,
+		4: stats.Rating4,
+		5: stats.Rating5,
+	}
+	r.ContentAverage = averageOf(stats.ContentSum, stats.ContentCount)
+	r.SpeakerAverage = averageOf(stats.SpeakerSum, stats.SpeakerCount)
+	r.VenueAverage = averageOf(stats.VenueSum, stats.VenueCount)
+	return r
+}
+
+type FlagFeedbackRequest struct {
+	Reason  enum.FeedbackFlagReason `json:"reason" validate:"required,oneof=spam harassment off_topic other"`
+	Details *string                 `json:"details" validate:"omitempty,max=500"`
+}
+
+type ModerateFeedbackRequest struct {
+	Action enum.ModerationAction `json:"action" validate:"required,oneof=hide restore dismiss"`
+	Note   *string               `json:"note" validate:"omitempty,max=500"`
+}
+
+type FeedbackFlagResponse struct {
+	ID        uuid.UUID               `json:"id"`
+	UserID    uuid.UUID               `json:"user_id,omitempty"`
+	Reason    enum.FeedbackFlagReason `json:"reason,omitempty"`
+	Details   *string                 `json:"details,omitempty"`
+	CreatedAt *time.Time              `json:"created_at,omitempty"`
+}
+
+func (f *FeedbackFlagResponse) PopulateFromEntity(flag *entity.FeedbackFlag) *FeedbackFlagResponse {
+	f.ID = flag.ID
+	f.UserID = flag.UserID
+	f.Reason = flag.Reason
+	f.Details = flag.Details
+	f.CreatedAt = &flag.CreatedAt
 	return f
 }
+
+type FlaggedFeedbackResponse struct {
+	Feedback FeedbackResponse       `json:"feedback"`
+	Flags    []FeedbackFlagResponse `json:"flags"`
+}
+
+type FeedbackModerationActionResponse struct {
+	ID        uuid.UUID             `json:"id"`
+	Action    enum.ModerationAction `json:"action,omitempty"`
+	Note      *string               `json:"note,omitempty"`
+	Moderator *UserResponse         `json:"moderator,omitempty"`
+	CreatedAt *time.Time            `json:"created_at,omitempty"`
+}
+
+func (a *FeedbackModerationActionResponse) PopulateFromEntity(
+	action *entity.FeedbackModerationAction) *FeedbackModerationActionResponse {
+
+	a.ID = action.ID
+	a.Action = action.Action
+	a.Note = action.Note
+	a.CreatedAt = &action.CreatedAt
+	if action.Moderator != nil {
+		a.Moderator = &UserResponse{
+			ID:   action.Moderator.ID,
+			Name: action.Moderator.Name,
+		}
+	}
+	return a
+}
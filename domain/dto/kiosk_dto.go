@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+)
+
+type KioskDeviceResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	ConferenceID uuid.UUID  `json:"conference_id,omitempty"`
+	Name         string     `json:"name,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (k *KioskDeviceResponse) PopulateFromEntity(device *entity.KioskDevice) *KioskDeviceResponse {
+	k.ID = device.ID
+	k.ConferenceID = device.ConferenceID
+	k.Name = device.Name
+	k.CreatedAt = &device.CreatedAt
+	k.LastSyncedAt = device.LastSyncedAt
+	k.RevokedAt = device.RevokedAt
+	return k
+}
+
+type CreateKioskDeviceRequest struct {
+	ConferenceID uuid.UUID `json:"conference_id" validate:"required"`
+	Name         string    `json:"name" validate:"required,min=1,max=100"`
+}
+
+type CreateKioskDeviceResponse struct {
+	Device KioskDeviceResponse `json:"device"`
+	// Token is only shown once, the device sends it as a bearer token
+	Token string `json:"token"`
+	// ManifestPublicKey is the base64 Ed25519 key the device verifies manifests with
+	ManifestPublicKey string `json:"manifest_public_key"`
+}
+
+type KioskManifestResponse struct {
+	ConferenceID uuid.UUID `json:"conference_id"`
+	Attendees    int       `json:"attendees"`
+	// Manifest is an EdDSA signed JWT carrying the attendee list
+	Manifest string `json:"manifest"`
+}
+
+type KioskScanRequest struct {
+	ScanID    uuid.UUID `json:"scan_id" validate:"required"`
+	Ticket    string    `json:"ticket" validate:"required"`
+	ScannedAt time.Time `json:"scanned_at" validate:"required"`
+}
+
+type KioskSyncRequest struct {
+	Scans []KioskScanRequest `json:"scans" validate:"required,min=1,max=500,dive"`
+}
+
+type KioskScanResultResponse struct {
+	ScanID uuid.UUID            `json:"scan_id"`
+	UserID *uuid.UUID           `json:"user_id,omitempty"`
+	Result enum.KioskScanResult `json:"result"`
+}
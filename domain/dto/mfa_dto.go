@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+)
+
+type MFAStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	Required               bool       `json:"required"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RemainingRecoveryCodes int        `json:"remaining_recovery_codes"`
+}
+
+type MFAEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type ConfirmMFAEnrollmentRequest struct {
+	Code string `json:"code" validate:"required,numeric,len=6"`
+}
+
+// VerifyMFARequest proves the user still holds their second factor, with either a code or a recovery code
+type VerifyMFARequest struct {
+	Code         string `json:"code" validate:"required_without=RecoveryCode,omitempty,numeric,len=6"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code,omitempty,max=32"`
+}
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+)
+
+type WaitlistEntryResponse struct {
+	ID             uuid.UUID           `json:"id"`
+	ConferenceID   uuid.UUID           `json:"conference_id,omitempty"`
+	Status         enum.WaitlistStatus `json:"status,omitempty"`
+	Position       *int                `json:"position,omitempty"`
+	OfferExpiresAt *time.Time          `json:"offer_expires_at,omitempty"`
+	CreatedAt      *time.Time          `json:"created_at,omitempty"`
+}
+
+func (w *WaitlistEntryResponse) PopulateFromEntity(entry *entity.WaitlistEntry) *WaitlistEntryResponse {
+	w.ID = entry.ID
+	w.ConferenceID = entry.ConferenceID
+	w.Status = entry.Status
+	w.OfferExpiresAt = entry.OfferExpiresAt
+	w.CreatedAt = &entry.CreatedAt
+	return w
+}
+
+type RegisteredConferenceResponse struct {
+	ConferenceResponse
+	RegisteredAt *time.Time `json:"registered_at,omitempty"`
+	ReconfirmBy  *time.Time `json:"reconfirm_by,omitempty"`
+	Ticket       string     `json:"ticket,omitempty"`
+	CheckedInAt  *time.Time `json:"checked_in_at,omitempty"`
+}
+
+func (r *RegisteredConferenceResponse) PopulateFromEntity(
+	registration *entity.Registration) *RegisteredConferenceResponse {
+
+	r.ConferenceResponse.PopulateFromEntity(registration.Conference)
+	r.RegisteredAt = &registration.CreatedAt
+	r.ReconfirmBy = registration.ReconfirmBy
+	r.CheckedInAt = registration.CheckedInAt
+	return r
+}
+
+type CheckInResponse struct {
+	Attendee    *UserResponse `json:"attendee,omitempty"`
+	CheckedInAt *time.Time    `json:"checked_in_at,omitempty"`
+}
+
+type AttendanceResponse struct {
+	Registered int `json:"registered"`
+	CheckedIn  int `json:"checked_in"`
+}
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+)
+
+type RoomResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name,omitempty"`
+	Building  string     `json:"building,omitempty"`
+	Capacity  int        `json:"capacity,omitempty"`
+	Amenities []string   `json:"amenities,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+func (r *RoomResponse) PopulateFromEntity(room *entity.Room) *RoomResponse {
+	r.ID = room.ID
+	r.Name = room.Name
+	r.Building = room.Building
+	r.Capacity = room.Capacity
+	r.Amenities = room.Amenities
+	r.CreatedAt = &room.CreatedAt
+	r.UpdatedAt = &room.UpdatedAt
+	return r
+}
+
+func (r *RoomResponse) PopulateMinimalFromEntity(room *entity.Room) *RoomResponse {
+	r.ID = room.ID
+	r.Name = room.Name
+	r.Building = room.Building
+	return r
+}
+
+type CreateRoomRequest struct {
+	Name      string   `json:"name" validate:"required,min=3,max=100"`
+	Building  string   `json:"building" validate:"required,min=3,max=100"`
+	Capacity  int      `json:"capacity" validate:"required,min=1"`
+	Amenities []string `json:"amenities" validate:"omitempty,max=20,dive,min=2,max=50"`
+}
+
+type UpdateRoomRequest struct {
+	Name      *string  `json:"name" validate:"omitempty,min=3,max=100"`
+	Building  *string  `json:"building" validate:"omitempty,min=3,max=100"`
+	Capacity  *int     `json:"capacity" validate:"omitempty,min=1"`
+	Amenities []string `json:"amenities" validate:"omitempty,max=20,dive,min=2,max=50"`
+}
+
+func (p *UpdateRoomRequest) GenerateUpdateEntity(original *entity.Room) *entity.Room {
+	if p.Name != nil {
+		original.Name = *p.Name
+	}
+	if p.Building != nil {
+		original.Building = *p.Building
+	}
+	if p.Capacity != nil {
+		original.Capacity = *p.Capacity
+	}
+	if p.Amenities != nil {
+		original.Amenities = p.Amenities
+	}
+
+	return original
+}
@@ -0,0 +1,101 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+)
+
+type SurveyTemplateResponse struct {
+	ID          uuid.UUID                `json:"id"`
+	Title       string                   `json:"title,omitempty"`
+	Description *string                  `json:"description,omitempty"`
+	CreatedAt   *time.Time               `json:"created_at,omitempty"`
+	Questions   []SurveyQuestionResponse `json:"questions,omitempty"`
+}
+
+func (t *SurveyTemplateResponse) PopulateFromEntity(template *entity.SurveyTemplate) *SurveyTemplateResponse {
+	t.ID = template.ID
+	t.Title = template.Title
+	t.Description = template.Description
+	t.CreatedAt = &template.CreatedAt
+	if template.Questions != nil {
+		t.Questions = make([]SurveyQuestionResponse, len(template.Questions))
+		for i := range template.Questions {
+			t.Questions[i].PopulateFromEntity(&template.Questions[i])
+		}
+	}
+	return t
+}
+
+type SurveyQuestionResponse struct {
+	ID       uuid.UUID               `json:"id"`
+	Type     enum.SurveyQuestionType `json:"type,omitempty"`
+	Prompt   string                  `json:"prompt,omitempty"`
+	Required bool                    `json:"required"`
+	Options  []string                `json:"options,omitempty"`
+	ScaleMax *int                    `json:"scale_max,omitempty"`
+}
+
+func (q *SurveyQuestionResponse) PopulateFromEntity(question *entity.SurveyQuestion) *SurveyQuestionResponse {
+	q.ID = question.ID
+	q.Type = question.Type
+	q.Prompt = question.Prompt
+	q.Required = question.Required
+	q.Options = question.Options
+	q.ScaleMax = question.ScaleMax
+	return q
+}
+
+type CreateSurveyQuestionRequest struct {
+	Type     enum.SurveyQuestionType `json:"type" validate:"required,oneof=rating single_choice multiple_choice text"`
+	Prompt   string                  `json:"prompt" validate:"required,min=3,max=255"`
+	Required bool                    `json:"required"`
+	// Options is required for choice questions and not allowed for the others
+	Options []string `json:"options" validate:"omitempty,min=2,max=20,unique,dive,required,max=100"`
+	// ScaleMax only applies to rating questions and defaults to 5
+	ScaleMax *int `json:"scale_max" validate:"omitempty,min=2,max=10"`
+}
+
+type CreateSurveyTemplateRequest struct {
+	Title       string                        `json:"title" validate:"required,min=3,max=100"`
+	Description *string                       `json:"description" validate:"omitempty,max=1000"`
+	Questions   []CreateSurveyQuestionRequest `json:"questions" validate:"required,min=1,max=30,dive"`
+}
+
+// SurveyAnswerRequest answers one question. Only the field matching the question type may be set:
+// Rating for rating questions, Choices for choice questions and Text for text questions.
+type SurveyAnswerRequest struct {
+	QuestionID uuid.UUID `json:"question_id" validate:"required"`
+	Rating     *int      `json:"rating"`
+	Choices    []string  `json:"choices"`
+	Text       *string   `json:"text"`
+}
+
+type SurveyQuestionResultResponse struct {
+	QuestionID uuid.UUID               `json:"question_id"`
+	Type       enum.SurveyQuestionType `json:"type"`
+	Prompt     string                  `json:"prompt"`
+	Responses  int                     `json:"responses"`
+	// Average is only set for rating questions
+	Average *float64 `json:"average,omitempty"`
+	// Distribution counts each rating value or choice. Text answers are only available in the CSV export.
+	Distribution map[string]int `json:"distribution,omitempty"`
+}
+
+type SurveyResultsResponse struct {
+	ConferenceID uuid.UUID                      `json:"conference_id"`
+	Template     SurveyTemplateResponse         `json:"template"`
+	Respondents  int                            `json:"respondents"`
+	Questions    []SurveyQuestionResultResponse `json:"questions"`
+}
+
+// SurveyAnswerCountRow counts the answers of a question with the given value. A nil Value counts the
+// feedbacks that answered the question at all.
+type SurveyAnswerCountRow struct {
+	QuestionID uuid.UUID `db:"question_id"`
+	Value      *string   `db:"value"`
+	Count      int       `db:"count"`
+}
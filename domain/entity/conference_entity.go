@@ -18,11 +18,18 @@ type Conference struct {
 	StartsAt       time.Time             `json:"starts_at" db:"starts_at"`
 	EndsAt         time.Time             `json:"ends_at" db:"ends_at"`
 	HostID         uuid.UUID             `json:"host_id" db:"host_id"`
+	RoomID         uuid.UUID             `json:"room_id" db:"room_id"`
+	SeriesID       *uuid.UUID            `json:"series_id" db:"series_id"`
 	Status         enum.ConferenceStatus `json:"status" db:"status"`
 	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time             `json:"updated_at" db:"updated_at"`
 	DeletedAt      *time.Time            `json:"deleted_at" db:"deleted_at"`
 
+	CancellationReason *string    `json:"cancellation_reason" db:"cancellation_reason"`
+	CancelledAt        *time.Time `json:"cancelled_at" db:"cancelled_at"`
+	CancelledBy        *uuid.UUID `json:"cancelled_by" db:"cancelled_by"`
+
 	Host              User `json:"-" db:"-"`
+	Room              Room `json:"-" db:"-"`
 	RegistrationCount int  `json:"-" db:"-"`
 }
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+)
+
+type ConferenceHost struct {
+	ConferenceID uuid.UUID     `json:"conference_id" db:"conference_id"`
+	UserID       uuid.UUID     `json:"user_id" db:"user_id"`
+	Role         enum.HostRole `json:"role" db:"role"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+
+	User User `json:"-" db:"-"`
+}
+
+type OwnershipTransfer struct {
+	ID           uuid.UUID                    `json:"id" db:"id"`
+	ConferenceID uuid.UUID                    `json:"conference_id" db:"conference_id"`
+	FromUserID   uuid.UUID                    `json:"from_user_id" db:"from_user_id"`
+	ToUserID     uuid.UUID                    `json:"to_user_id" db:"to_user_id"`
+	Status       enum.OwnershipTransferStatus `json:"status" db:"status"`
+	CreatedAt    time.Time                    `json:"created_at" db:"created_at"`
+	RespondedAt  *time.Time                   `json:"responded_at" db:"responded_at"`
+}
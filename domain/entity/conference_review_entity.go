@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+)
+
+type ConferenceReview struct {
+	ID           uuid.UUID             `json:"id" db:"id"`
+	ConferenceID uuid.UUID             `json:"conference_id" db:"conference_id"`
+	ReviewerID   uuid.UUID             `json:"reviewer_id" db:"reviewer_id"`
+	Status       enum.ConferenceStatus `json:"status" db:"status"`
+	Comment      *string               `json:"comment" db:"comment"`
+	CreatedAt    time.Time             `json:"created_at" db:"created_at"`
+
+	Reviewer User `json:"-" db:"-"`
+}
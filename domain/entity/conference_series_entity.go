@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+)
+
+type ConferenceSeries struct {
+	ID          uuid.UUID                `json:"id" db:"id"`
+	HostID      uuid.UUID                `json:"host_id" db:"host_id"`
+	Frequency   enum.RecurrenceFrequency `json:"frequency" db:"frequency"`
+	RepeatUntil *time.Time               `json:"repeat_until" db:"repeat_until"`
+	RepeatCount *int                     `json:"repeat_count" db:"repeat_count"`
+	CreatedAt   time.Time                `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at" db:"updated_at"`
+	DeletedAt   *time.Time               `json:"deleted_at" db:"deleted_at"`
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
 )
 
 type Feedback struct {
@@ -11,9 +12,68 @@ type Feedback struct {
 	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
 	ConferenceID uuid.UUID  `json:"conference_id" db:"conference_id"`
 	Comment      string     `json:"comment" db:"comment"`
+	Rating       *int       `json:"rating" db:"rating"`
+	ContentScore *int       `json:"content_score" db:"content_score"`
+	SpeakerScore *int       `json:"speaker_score" db:"speaker_score"`
+	VenueScore   *int       `json:"venue_score" db:"venue_score"`
+	IsAnonymous  bool       `json:"is_anonymous" db:"is_anonymous"`
 	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	HiddenAt     *time.Time `json:"hidden_at" db:"hidden_at"`
 	DeletedAt    *time.Time `json:"deleted_at" db:"deleted_at"`
 
-	User       *User       `json:"-" db:"-"`
-	Conference *Conference `json:"-" db:"-"`
+	User       *User          `json:"-" db:"-"`
+	Conference *Conference    `json:"-" db:"-"`
+	Reply      *FeedbackReply `json:"-" db:"-"`
+
+	SurveyAnswers []SurveyAnswer `json:"-" db:"-"`
+}
+
+type FeedbackReply struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	FeedbackID uuid.UUID `json:"feedback_id" db:"feedback_id"`
+	UserID     uuid.UUID `json:"user_id" db:"user_id"`
+	Body       string    `json:"body" db:"body"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+
+	User *User `json:"-" db:"-"`
+}
+
+// ConferenceRatingStats holds the running rating totals of a conference, maintained by a trigger on feedbacks
+type ConferenceRatingStats struct {
+	ConferenceID uuid.UUID `json:"conference_id" db:"conference_id"`
+	RatingCount  int       `json:"rating_count" db:"rating_count"`
+	RatingSum    int       `json:"rating_sum" db:"rating_sum"`
+	Rating1      int       `json:"rating_1" db:"rating_1"`
+	Rating2      int       `json:"rating_2" db:"rating_2"`
+	Rating3      int       `json:"rating_3" db:"rating_3"`
+	Rating4      int       `json:"rating_4" db:"rating_4"`
+	Rating5      int       `json:"rating_5" db:"rating_5"`
+	ContentCount int       `json:"content_count" db:"content_count"`
+	ContentSum   int       `json:"content_sum" db:"content_sum"`
+	SpeakerCount int       `json:"speaker_count" db:"speaker_count"`
+	SpeakerSum   int       `json:"speaker_sum" db:"speaker_sum"`
+	VenueCount   int       `json:"venue_count" db:"venue_count"`
+	VenueSum     int       `json:"venue_sum" db:"venue_sum"`
+}
+
+type FeedbackFlag struct {
+	ID         uuid.UUID               `json:"id" db:"id"`
+	FeedbackID uuid.UUID               `json:"feedback_id" db:"feedback_id"`
+	UserID     uuid.UUID               `json:"user_id" db:"user_id"`
+	Reason     enum.FeedbackFlagReason `json:"reason" db:"reason"`
+	Details    *string                 `json:"details" db:"details"`
+	CreatedAt  time.Time               `json:"created_at" db:"created_at"`
+	ResolvedAt *time.Time              `json:"resolved_at" db:"resolved_at"`
+}
+
+type FeedbackModerationAction struct {
+	ID          uuid.UUID             `json:"id" db:"id"`
+	FeedbackID  uuid.UUID             `json:"feedback_id" db:"feedback_id"`
+	ModeratorID *uuid.UUID            `json:"moderator_id" db:"moderator_id"`
+	Action      enum.ModerationAction `json:"action" db:"action"`
+	Note        *string               `json:"note" db:"note"`
+	CreatedAt   time.Time             `json:"created_at" db:"created_at"`
+
+	Moderator *User `json:"-" db:"-"`
 }
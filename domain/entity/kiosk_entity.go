@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+)
+
+type KioskDevice struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	ConferenceID uuid.UUID  `json:"conference_id" db:"conference_id"`
+	Name         string     `json:"name" db:"name"`
+	CreatedBy    *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	LastSyncedAt *time.Time `json:"last_synced_at" db:"last_synced_at"`
+	RevokedAt    *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+type KioskScan struct {
+	DeviceID  uuid.UUID            `json:"device_id" db:"device_id"`
+	ScanID    uuid.UUID            `json:"scan_id" db:"scan_id"`
+	TicketID  *uuid.UUID           `json:"ticket_id" db:"ticket_id"`
+	UserID    *uuid.UUID           `json:"user_id" db:"user_id"`
+	ScannedAt time.Time            `json:"scanned_at" db:"scanned_at"`
+	Result    enum.KioskScanResult `json:"result" db:"result"`
+	SyncedAt  time.Time            `json:"synced_at" db:"synced_at"`
+}
@@ -7,9 +7,17 @@ import (
 )
 
 type Registration struct {
-	UserID       uuid.UUID `json:"user_id" db:"user_id"`
-	ConferenceID uuid.UUID `json:"conference_id" db:"conference_id"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UserID       uuid.UUID  `json:"user_id" db:"user_id"`
+	ConferenceID uuid.UUID  `json:"conference_id" db:"conference_id"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	CancelledAt  *time.Time `json:"cancelled_at" db:"cancelled_at"`
+	CancelledBy  *uuid.UUID `json:"cancelled_by" db:"cancelled_by"`
+	ReconfirmBy  *time.Time `json:"reconfirm_by" db:"reconfirm_by"`
+	TicketID     uuid.UUID  `json:"ticket_id" db:"ticket_id"`
+	CheckedInAt  *time.Time `json:"checked_in_at" db:"checked_in_at"`
+	CheckedInBy  *uuid.UUID `json:"checked_in_by" db:"checked_in_by"`
+
+	CheckedInDeviceID *uuid.UUID `json:"checked_in_device_id" db:"checked_in_device_id"`
 
 	User       *User       `json:"-" db:"-"`
 	Conference *Conference `json:"-" db:"-"`
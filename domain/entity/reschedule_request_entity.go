@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+)
+
+type RescheduleRequest struct {
+	ID            uuid.UUID             `json:"id" db:"id"`
+	ConferenceID  uuid.UUID             `json:"conference_id" db:"conference_id"`
+	RequestedBy   uuid.UUID             `json:"requested_by" db:"requested_by"`
+	StartsAt      time.Time             `json:"starts_at" db:"starts_at"`
+	EndsAt        time.Time             `json:"ends_at" db:"ends_at"`
+	Reason        *string               `json:"reason" db:"reason"`
+	Status        enum.RescheduleStatus `json:"status" db:"status"`
+	ReviewedBy    *uuid.UUID            `json:"reviewed_by" db:"reviewed_by"`
+	ReviewComment *string               `json:"review_comment" db:"review_comment"`
+	ReviewedAt    *time.Time            `json:"reviewed_at" db:"reviewed_at"`
+	CreatedAt     time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at" db:"updated_at"`
+}
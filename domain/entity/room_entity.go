@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Room struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	Name      string     `json:"name" db:"name"`
+	Building  string     `json:"building" db:"building"`
+	Capacity  int        `json:"capacity" db:"capacity"`
+	Amenities Amenities  `json:"amenities" db:"amenities"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at" db:"deleted_at"`
+}
+
+// Amenities is stored as a JSONB array of strings.
+type Amenities []string
+
+func (a Amenities) Value() (driver.Value, error) {
+	if a == nil {
+		return "[]", nil
+	}
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+func (a *Amenities) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = Amenities{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return fmt.Errorf("cannot scan %T into Amenities", src)
+	}
+}
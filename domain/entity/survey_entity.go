@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+)
+
+type SurveyTemplate struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	Title       string     `json:"title" db:"title"`
+	Description *string    `json:"description" db:"description"`
+	CreatedBy   *uuid.UUID `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at" db:"deleted_at"`
+
+	Questions []SurveyQuestion `json:"-" db:"-"`
+}
+
+type SurveyQuestion struct {
+	ID         uuid.UUID               `json:"id" db:"id"`
+	TemplateID uuid.UUID               `json:"template_id" db:"template_id"`
+	Position   int                     `json:"position" db:"position"`
+	Type       enum.SurveyQuestionType `json:"type" db:"type"`
+	Prompt     string                  `json:"prompt" db:"prompt"`
+	Required   bool                    `json:"required" db:"required"`
+	Options    []string                `json:"options" db:"-"`
+	ScaleMax   *int                    `json:"scale_max" db:"scale_max"`
+}
+
+type ConferenceSurvey struct {
+	ConferenceID uuid.UUID  `json:"conference_id" db:"conference_id"`
+	TemplateID   uuid.UUID  `json:"template_id" db:"template_id"`
+	AttachedBy   *uuid.UUID `json:"attached_by" db:"attached_by"`
+	AttachedAt   time.Time  `json:"attached_at" db:"attached_at"`
+}
+
+// SurveyAnswer is a single answered value. Multiple choice answers have one per selected choice.
+type SurveyAnswer struct {
+	FeedbackID  uuid.UUID `json:"feedback_id" db:"feedback_id"`
+	QuestionID  uuid.UUID `json:"question_id" db:"question_id"`
+	AnswerIndex int       `json:"answer_index" db:"answer_index"`
+	RatingValue *int      `json:"rating_value" db:"rating_value"`
+	ChoiceValue *string   `json:"choice_value" db:"choice_value"`
+	TextValue   *string   `json:"text_value" db:"text_value"`
+}
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+)
+
+type WaitlistEntry struct {
+	ID             uuid.UUID           `json:"id" db:"id"`
+	ConferenceID   uuid.UUID           `json:"conference_id" db:"conference_id"`
+	UserID         uuid.UUID           `json:"user_id" db:"user_id"`
+	Status         enum.WaitlistStatus `json:"status" db:"status"`
+	OfferedAt      *time.Time          `json:"offered_at" db:"offered_at"`
+	OfferExpiresAt *time.Time          `json:"offer_expires_at" db:"offer_expires_at"`
+	CreatedAt      time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at" db:"updated_at"`
+}
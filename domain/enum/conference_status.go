@@ -3,9 +3,11 @@ package enum
 type ConferenceStatus string
 
 const (
-	ConferencePending  ConferenceStatus = "pending"
-	ConferenceApproved ConferenceStatus = "approved"
-	ConferenceRejected ConferenceStatus = "rejected"
+	ConferencePending       ConferenceStatus = "pending"
+	ConferenceApproved      ConferenceStatus = "approved"
+	ConferenceRejected      ConferenceStatus = "rejected"
+	ConferenceNeedsRevision ConferenceStatus = "needs_revision"
+	ConferenceCancelled     ConferenceStatus = "cancelled"
 )
 
 func (s ConferenceStatus) String() string {
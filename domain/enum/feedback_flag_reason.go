@@ -0,0 +1,14 @@
+package enum
+
+type FeedbackFlagReason string
+
+const (
+	FlagSpam       FeedbackFlagReason = "spam"
+	FlagHarassment FeedbackFlagReason = "harassment"
+	FlagOffTopic   FeedbackFlagReason = "off_topic"
+	FlagOther      FeedbackFlagReason = "other"
+)
+
+func (r FeedbackFlagReason) String() string {
+	return string(r)
+}
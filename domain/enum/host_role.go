@@ -0,0 +1,12 @@
+package enum
+
+type HostRole string
+
+const (
+	HostOwner  HostRole = "owner"
+	HostCoHost HostRole = "co_host"
+)
+
+func (r HostRole) String() string {
+	return string(r)
+}
@@ -0,0 +1,15 @@
+package enum
+
+type KioskScanResult string
+
+const (
+	KioskScanCheckedIn KioskScanResult = "checked_in"
+	KioskScanDuplicate KioskScanResult = "duplicate"
+	KioskScanInvalid   KioskScanResult = "invalid"
+	KioskScanForeign   KioskScanResult = "foreign"
+	KioskScanRevoked   KioskScanResult = "revoked"
+)
+
+func (r KioskScanResult) String() string {
+	return string(r)
+}
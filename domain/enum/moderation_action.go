@@ -0,0 +1,15 @@
+package enum
+
+type ModerationAction string
+
+const (
+	ModerationHide     ModerationAction = "hide"
+	ModerationRestore  ModerationAction = "restore"
+	ModerationDismiss  ModerationAction = "dismiss"
+	ModerationDelete   ModerationAction = "delete"
+	ModerationAutoHide ModerationAction = "auto_hide"
+)
+
+func (a ModerationAction) String() string {
+	return string(a)
+}
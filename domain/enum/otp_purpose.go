@@ -0,0 +1,13 @@
+package enum
+
+type OTPPurpose string
+
+const (
+	OTPRegisterUser  OTPPurpose = "register"
+	OTPResetPassword OTPPurpose = "reset_password"
+	OTPMagicLink     OTPPurpose = "magic_link"
+)
+
+func (p OTPPurpose) String() string {
+	return string(p)
+}
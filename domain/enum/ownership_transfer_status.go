@@ -0,0 +1,14 @@
+package enum
+
+type OwnershipTransferStatus string
+
+const (
+	OwnershipTransferPending   OwnershipTransferStatus = "pending"
+	OwnershipTransferAccepted  OwnershipTransferStatus = "accepted"
+	OwnershipTransferDeclined  OwnershipTransferStatus = "declined"
+	OwnershipTransferCancelled OwnershipTransferStatus = "cancelled"
+)
+
+func (s OwnershipTransferStatus) String() string {
+	return string(s)
+}
@@ -0,0 +1,13 @@
+package enum
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceWeekly   RecurrenceFrequency = "weekly"
+	RecurrenceBiweekly RecurrenceFrequency = "biweekly"
+	RecurrenceMonthly  RecurrenceFrequency = "monthly"
+)
+
+func (f RecurrenceFrequency) String() string {
+	return string(f)
+}
@@ -0,0 +1,14 @@
+package enum
+
+type SurveyQuestionType string
+
+const (
+	SurveyRating         SurveyQuestionType = "rating"
+	SurveySingleChoice   SurveyQuestionType = "single_choice"
+	SurveyMultipleChoice SurveyQuestionType = "multiple_choice"
+	SurveyText           SurveyQuestionType = "text"
+)
+
+func (t SurveyQuestionType) String() string {
+	return string(t)
+}
@@ -11,3 +11,9 @@ const (
 func (r UserRole) String() string {
 	return string(r)
 }
+
+// RequiresMFA reports whether users of the role must log in with two-factor authentication.
+// These roles can approve, reject and delete anyone's content.
+func (r UserRole) RequiresMFA() bool {
+	return r == RoleAdmin || r == RoleEventCoordinator
+}
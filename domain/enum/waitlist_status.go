@@ -0,0 +1,15 @@
+package enum
+
+type WaitlistStatus string
+
+const (
+	WaitlistWaiting  WaitlistStatus = "waiting"
+	WaitlistOffered  WaitlistStatus = "offered"
+	WaitlistAccepted WaitlistStatus = "accepted"
+	WaitlistLeft     WaitlistStatus = "left"
+	WaitlistExpired  WaitlistStatus = "expired"
+)
+
+func (s WaitlistStatus) String() string {
+	return string(s)
+}
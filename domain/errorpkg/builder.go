@@ -1,6 +1,10 @@
 package errorpkg
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type ErrorResponse struct {
 	HttpStatusCode int        `json:"-"`
@@ -8,6 +12,8 @@ type ErrorResponse struct {
 	Detail         any        `json:"detail,omitempty"`
 	ErrorCode      string     `json:"error_code,omitempty"`
 	TraceID        *uuid.UUID `json:"trace_id,omitempty"`
+	// RetryAfter is sent as the Retry-After header when set
+	RetryAfter time.Duration `json:"-"`
 }
 
 func (e *ErrorResponse) Error() string {
@@ -39,3 +45,8 @@ func (e *ErrorResponse) WithTraceID(traceID uuid.UUID) *ErrorResponse {
 	e.TraceID = &traceID
 	return e
 }
+
+func (e *ErrorResponse) WithRetryAfter(retryAfter time.Duration) *ErrorResponse {
+	e.RetryAfter = retryAfter
+	return e
+}
@@ -9,6 +9,34 @@ var (
 		WithErrorCode("INTERNAL_SERVER_ERROR").
 		WithMessage("Something went wrong in our server. Please try again later.")
 
+	ErrAlreadyConferenceHost = NewError(http.StatusConflict).
+		WithErrorCode("ALREADY_CONFERENCE_HOST").
+		WithMessage("This user is already a host of the conference.")
+
+	ErrAlreadyOnWaitlist = NewError(http.StatusConflict).
+		WithErrorCode("ALREADY_ON_WAITLIST").
+		WithMessage("You're already on the waitlist for this conference.")
+
+	ErrCancellationCutoffPassed = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("CANCELLATION_CUTOFF_PASSED").
+		WithMessage("It's too close to the conference start. You're not allowed to cancel this registration anymore.")
+
+	ErrCancelNotApprovedConference = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("CANCEL_NOT_APPROVED_CONFERENCE").
+		WithMessage("Only approved conferences can be cancelled. Please reject or delete the proposal instead.")
+
+	ErrCannotFlagOwnFeedback = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("CANNOT_FLAG_OWN_FEEDBACK").
+		WithMessage("You cannot flag your own feedback.")
+
+	ErrCannotRemoveOwner = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("CANNOT_REMOVE_OWNER").
+		WithMessage("The owner can't be removed from the conference. Transfer the ownership first.")
+
+	ErrConferenceCancelled = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("CONFERENCE_CANCELLED").
+		WithMessage("Conference has been cancelled.")
+
 	ErrConferenceEnded = NewError(http.StatusUnprocessableEntity).
 		WithErrorCode("CONFERENCE_ENDED").
 		WithMessage("Conference has ended. You're not allowed to register anymore.")
@@ -21,6 +49,14 @@ var (
 		WithErrorCode("CONFERENCE_NOT_ENDED").
 		WithMessage("Conference has not ended yet. You're not allowed to give feedback.")
 
+	ErrConferenceNotFull = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("CONFERENCE_NOT_FULL").
+		WithMessage("Conference still has available seats. Please register directly.")
+
+	ErrConferenceNotInRevision = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("CONFERENCE_NOT_IN_REVISION").
+		WithMessage("Conference is not waiting for revision. Only proposals sent back for revision can be resubmitted.")
+
 	ErrConflictingRegistrations = NewError(http.StatusConflict).
 		WithErrorCode("CONFLICTING_REGISTRATIONS").
 		WithMessage("You have conflicting registrations. Please check your schedule.")
@@ -41,10 +77,26 @@ var (
 		WithErrorCode("FAIL_PARSE_REQUEST").
 		WithMessage("Failed to parse request. Please check your request format.")
 
+	ErrFeedbackAlreadyFlagged = NewError(http.StatusConflict).
+		WithErrorCode("FEEDBACK_ALREADY_FLAGGED").
+		WithMessage("You already flagged this feedback.")
+
 	ErrFeedbackAlreadyGiven = NewError(http.StatusConflict).
 		WithErrorCode("FEEDBACK_ALREADY_GIVEN").
 		WithMessage("You already gave feedback to this conference.")
 
+	ErrFeedbackHidden = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("FEEDBACK_HIDDEN").
+		WithMessage("Feedback is hidden. Restore it instead of dismissing the flags.")
+
+	ErrFeedbackNotHidden = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("FEEDBACK_NOT_HIDDEN").
+		WithMessage("Feedback is not hidden.")
+
+	ErrFeedbackReplyAlreadyExists = NewError(http.StatusConflict).
+		WithErrorCode("FEEDBACK_REPLY_ALREADY_EXISTS").
+		WithMessage("This feedback already has a reply. Please edit it instead.")
+
 	ErrForbiddenRole = NewError(http.StatusForbidden).
 		WithErrorCode("FORBIDDEN_ROLE").
 		WithMessage("You're not allowed to access this resource.")
@@ -53,6 +105,10 @@ var (
 		WithErrorCode("FORBIDDEN_USER").
 		WithMessage("You're not allowed to access this resource.")
 
+	ErrForeignTicket = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("FOREIGN_TICKET").
+		WithMessage("This ticket belongs to another conference.")
+
 	ErrHostCannotGiveFeedback = NewError(http.StatusForbidden).
 		WithErrorCode("HOST_CANNOT_GIVE_FEEDBACK").
 		WithMessage("Host is not allowed to give feedback to their own conference.")
@@ -65,26 +121,174 @@ var (
 		WithErrorCode("INVALID_BEARER_TOKEN").
 		WithMessage("Your auth session is invalid. Please renew your auth session.")
 
+	ErrInvalidMFACode = NewError(http.StatusUnauthorized).
+		WithErrorCode("INVALID_MFA_CODE").
+		WithMessage("Invalid authentication code. Please try again.")
+
+	ErrInvalidMFAToken = NewError(http.StatusUnauthorized).
+		WithErrorCode("INVALID_MFA_TOKEN").
+		WithMessage("Your login attempt has expired. Please login again.")
+
+	ErrInvalidOIDCState = NewError(http.StatusUnauthorized).
+		WithErrorCode("INVALID_OIDC_STATE").
+		WithMessage("Your sign-in attempt has expired. Please try again.")
+
 	ErrInvalidOTP = NewError(http.StatusUnauthorized).
 		WithErrorCode("INVALID_OTP").
 		WithMessage("Invalid OTP. Please try again or request a new OTP.")
 
+	ErrInvalidOwnershipTransfer = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("INVALID_OWNERSHIP_TRANSFER").
+		WithMessage("Ownership can only be transferred to a co-host of the conference.")
+
 	ErrInvalidPagination = NewError(http.StatusUnprocessableEntity).
 		WithErrorCode("INVALID_PAGINATION").
 		WithMessage("Cannot use after_id and before_id at the same time.")
 
+	ErrInvalidRatingRange = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("INVALID_RATING_RANGE").
+		WithMessage("min_rating cannot be greater than max_rating.")
+
+	ErrInvalidRecurrence = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("INVALID_RECURRENCE").
+		WithMessage("Recurrence rule is invalid. Please use either an until date or a count that yields at least two non-overlapping occurrences.")
+
 	ErrInvalidRefreshToken = NewError(http.StatusUnauthorized).
 		WithErrorCode("INVALID_REFRESH_TOKEN").
 		WithMessage("Auth session is invalid. Please login again.")
 
+	ErrInvalidSurveyAnswer = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("INVALID_SURVEY_ANSWER").
+		WithMessage("One of the survey answers is invalid. Please check the details.")
+
+	ErrInvalidSurveyQuestion = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("INVALID_SURVEY_QUESTION").
+		WithMessage("One of the survey questions is invalid. Please check the details.")
+
+	ErrInvalidTicket = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("INVALID_TICKET").
+		WithMessage("Ticket is invalid or has been revoked.")
+
+	ErrKioskDeviceRevoked = NewError(http.StatusUnauthorized).
+		WithErrorCode("KIOSK_DEVICE_REVOKED").
+		WithMessage("This kiosk device has been revoked. Please register it again.")
+
+	ErrKioskNotApprovedConference = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("KIOSK_NOT_APPROVED_CONFERENCE").
+		WithMessage("Kiosk devices can only be registered for approved conferences.")
+
+	ErrLoginLocked = NewError(http.StatusTooManyRequests).
+		WithErrorCode("LOGIN_LOCKED").
+		WithMessage("Too many failed logins. Please try again later or reset your password.")
+
+	ErrMFAAlreadyEnabled = NewError(http.StatusConflict).
+		WithErrorCode("MFA_ALREADY_ENABLED").
+		WithMessage("Two-factor authentication is already enabled.")
+
+	ErrMFAMandatory = NewError(http.StatusForbidden).
+		WithErrorCode("MFA_MANDATORY").
+		WithMessage("Two-factor authentication is mandatory for your role and can't be disabled.")
+
+	ErrMFANotEnabled = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("MFA_NOT_ENABLED").
+		WithMessage("Two-factor authentication is not enabled.")
+
 	ErrNoBearerToken = NewError(http.StatusUnauthorized).
 		WithErrorCode("NO_BEARER_TOKEN").
 		WithMessage("You're not logged in. Please login first.")
 
+	ErrNoPendingReconfirmation = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("NO_PENDING_RECONFIRMATION").
+		WithMessage("Your registration for this conference doesn't need to be reconfirmed.")
+
+	ErrNoSurveyAttached = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("NO_SURVEY_ATTACHED").
+		WithMessage("This conference doesn't have a survey.")
+
+	ErrNoWaitlistOffer = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("NO_WAITLIST_OFFER").
+		WithMessage("You don't have a seat offer for this conference. Please wait for your turn.")
+
 	ErrNotFound = NewError(http.StatusNotFound).
 		WithErrorCode("NOT_FOUND").
 		WithMessage("Data not found.")
 
+	ErrNotOnWaitlist = NewError(http.StatusNotFound).
+		WithErrorCode("NOT_ON_WAITLIST").
+		WithMessage("You're not on the waitlist for this conference.")
+
+	ErrOIDCEmailNotVerified = NewError(http.StatusForbidden).
+		WithErrorCode("OIDC_EMAIL_NOT_VERIFIED").
+		WithMessage("Your campus account has no verified email address.")
+
+	ErrOIDCLoginFailed = NewError(http.StatusUnauthorized).
+		WithErrorCode("OIDC_LOGIN_FAILED").
+		WithMessage("Sign-in with your campus account failed. Please try again.")
+
+	ErrOIDCNotConfigured = NewError(http.StatusNotFound).
+		WithErrorCode("OIDC_NOT_CONFIGURED").
+		WithMessage("Sign-in with a campus account is not available.")
+
+	ErrOTPCooldown = NewError(http.StatusTooManyRequests).
+		WithErrorCode("OTP_COOLDOWN").
+		WithMessage("An OTP was sent recently. Please wait before requesting a new one.")
+
+	ErrOTPLocked = NewError(http.StatusTooManyRequests).
+		WithErrorCode("OTP_LOCKED").
+		WithMessage("Too many wrong OTPs. Please try again later.")
+
+	ErrOwnerOnly = NewError(http.StatusForbidden).
+		WithErrorCode("OWNER_ONLY").
+		WithMessage("Only the owner of the conference can do this. Co-hosts are not allowed.")
+
+	ErrOwnershipTransferAlreadyPending = NewError(http.StatusConflict).
+		WithErrorCode("OWNERSHIP_TRANSFER_ALREADY_PENDING").
+		WithMessage("This conference already has a pending ownership transfer.")
+
+	ErrOwnershipTransferNotPending = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("OWNERSHIP_TRANSFER_NOT_PENDING").
+		WithMessage("This ownership transfer is no longer pending.")
+
+	ErrReconfirmationExpired = NewError(http.StatusGone).
+		WithErrorCode("RECONFIRMATION_EXPIRED").
+		WithMessage("The reconfirmation deadline has passed. Your seat has been released.")
+
+	ErrRescheduleAlreadyRequested = NewError(http.StatusConflict).
+		WithErrorCode("RESCHEDULE_ALREADY_REQUESTED").
+		WithMessage("This conference already has a pending reschedule request.")
+
+	ErrRescheduleNotApprovedConference = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("RESCHEDULE_NOT_APPROVED_CONFERENCE").
+		WithMessage("Only approved conferences that have not started can be rescheduled.")
+
+	ErrRescheduleNotPending = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("RESCHEDULE_NOT_PENDING").
+		WithMessage("This reschedule request has already been reviewed.")
+
+	ErrReviewCommentRequired = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("REVIEW_COMMENT_REQUIRED").
+		WithMessage("A comment is required when rejecting a proposal or sending it back for revision.")
+
+	ErrRoomAlreadyExists = NewError(http.StatusConflict).
+		WithErrorCode("ROOM_ALREADY_EXISTS").
+		WithMessage("A room with the same name already exists in this building.")
+
+	ErrRoomInUse = NewError(http.StatusConflict).
+		WithErrorCode("ROOM_IN_USE").
+		WithMessage("Room is still used by upcoming conferences. Please move or remove them first.")
+
+	ErrSeatsExceedRoomCapacity = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("SEATS_EXCEED_ROOM_CAPACITY").
+		WithMessage("Number of seats exceeds the room capacity. Please reduce the seats or choose a bigger room.")
+
+	ErrSurveyAlreadyAnswered = NewError(http.StatusConflict).
+		WithErrorCode("SURVEY_ALREADY_ANSWERED").
+		WithMessage("The survey of this conference already has answers and can no longer be changed.")
+
+	ErrTicketAlreadyUsed = NewError(http.StatusConflict).
+		WithErrorCode("TICKET_ALREADY_USED").
+		WithMessage("This ticket has already been checked in.")
+
 	ErrTimeAlreadyPassed = NewError(http.StatusUnprocessableEntity).
 		WithErrorCode("TIME_ALREADY_PASSED").
 		WithMessage("Time has already passed. Please use future time.")
@@ -93,6 +297,10 @@ var (
 		WithErrorCode("TIME_WINDOW_CONFLICT").
 		WithMessage("There's already a conference in the same time window. Please choose another time window.")
 
+	ErrTooManyOccurrences = NewError(http.StatusUnprocessableEntity).
+		WithErrorCode("TOO_MANY_OCCURRENCES").
+		WithMessage("Series has too many occurrences. Please use an earlier until date or a smaller count.")
+
 	ErrUpdatePastConference = NewError(http.StatusUnprocessableEntity).
 		WithErrorCode("UPDATE_PAST_CONFERENCE").
 		WithMessage("You're not allowed to update a past conference.")
@@ -116,4 +324,12 @@ var (
 	ErrUserNotRegisteredToConference = NewError(http.StatusForbidden).
 		WithErrorCode("USER_NOT_REGISTERED_TO_CONFERENCE").
 		WithMessage("You're not registered to this conference.")
+
+	ErrUserRegisteredAsAttendee = NewError(http.StatusConflict).
+		WithErrorCode("USER_REGISTERED_AS_ATTENDEE").
+		WithMessage("This user is registered as an attendee of the conference and can't be a host.")
+
+	ErrWaitlistOfferExpired = NewError(http.StatusGone).
+		WithErrorCode("WAITLIST_OFFER_EXPIRED").
+		WithMessage("Your seat offer has expired. The seat has been offered to the next person on the waitlist.")
 )
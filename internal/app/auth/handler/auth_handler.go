@@ -2,8 +2,10 @@ package handler
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
 	"github.com/nathakusuma/auditorium-reservation-backend/internal/middleware"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/validator"
@@ -28,14 +30,30 @@ func InitAuthHandler(
 	}
 
 	authGroup := router.Group("/auth")
+	authGroup.Use(middlewareInstance.ClientInfo())
 	authGroup.Post("/register/otp", handler.requestOTPRegisterUser())
 	authGroup.Post("/register/otp/check", handler.checkOTPRegisterUser())
 	authGroup.Post("/register", handler.registerUser())
 	authGroup.Post("/login", handler.loginUser())
+	authGroup.Post("/login/mfa", handler.loginMFA())
+	authGroup.Post("/login/mfa/enroll", handler.startMFALoginEnrollment())
+	authGroup.Post("/oidc/authorize", handler.startOIDCLogin())
+	authGroup.Post("/oidc/callback", handler.loginOIDC())
+	authGroup.Post("/magic-link", handler.requestMagicLink())
+	authGroup.Post("/magic-link/verify", handler.loginMagicLink())
+	authGroup.Delete("/users/:id/login-lock",
+		middlewareInstance.RequireAuthenticated(),
+		middlewareInstance.RequireOneOfRoles(enum.RoleAdmin),
+		handler.unlockLogin(),
+	)
 	authGroup.Post("/refresh", handler.refreshToken())
 	authGroup.Post("/logout", middlewareInstance.RequireAuthenticated(), handler.logout())
 	authGroup.Post("/reset-password/otp", handler.requestOTPResetPassword())
 	authGroup.Post("/reset-password", handler.resetPassword())
+
+	authGroup.Get("/sessions", middlewareInstance.RequireAuthenticated(), handler.getSessions())
+	authGroup.Delete("/sessions", middlewareInstance.RequireAuthenticated(), handler.revokeAllSessions())
+	authGroup.Delete("/sessions/:id", middlewareInstance.RequireAuthenticated(), handler.revokeSession())
 }
 
 func (c *authHandler) requestOTPRegisterUser() fiber.Handler {
@@ -118,6 +136,136 @@ func (c *authHandler) loginUser() fiber.Handler {
 	}
 }
 
+func (c *authHandler) loginMFA() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var req dto.MFALoginRequest
+		if err := ctx.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := c.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		resp, err := c.svc.LoginMFA(ctx.Context(), req)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(http.StatusOK).JSON(resp)
+	}
+}
+
+func (c *authHandler) startMFALoginEnrollment() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var req dto.MFAEnrollLoginRequest
+		if err := ctx.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := c.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		enrollment, err := c.svc.StartMFALoginEnrollment(ctx.Context(), req.MFAToken)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(http.StatusCreated).JSON(map[string]interface{}{
+			"enrollment": enrollment,
+		})
+	}
+}
+
+func (c *authHandler) startOIDCLogin() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		authURL, err := c.svc.StartOIDCLogin(ctx.Context())
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(http.StatusOK).JSON(map[string]interface{}{
+			"authorization_url": authURL,
+		})
+	}
+}
+
+func (c *authHandler) loginOIDC() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var req dto.OIDCCallbackRequest
+		if err := ctx.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := c.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		resp, err := c.svc.LoginOIDC(ctx.Context(), req)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(http.StatusOK).JSON(resp)
+	}
+}
+
+func (c *authHandler) requestMagicLink() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var req dto.RequestMagicLinkRequest
+		if err := ctx.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := c.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		err := c.svc.RequestMagicLink(ctx.Context(), req.Email)
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(http.StatusNoContent)
+	}
+}
+
+func (c *authHandler) loginMagicLink() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var req dto.MagicLinkLoginRequest
+		if err := ctx.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := c.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		resp, err := c.svc.LoginMagicLink(ctx.Context(), req)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(http.StatusOK).JSON(resp)
+	}
+}
+
+func (c *authHandler) unlockLogin() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		userID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = c.svc.UnlockLogin(ctx.Context(), userID); err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(http.StatusNoContent)
+	}
+}
+
 func (c *authHandler) refreshToken() fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		var req dto.RefreshTokenRequest
@@ -149,6 +297,44 @@ func (c *authHandler) logout() fiber.Handler {
 	}
 }
 
+func (c *authHandler) getSessions() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		sessions, err := c.svc.GetSessions(ctx.Context())
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(http.StatusOK).JSON(map[string]interface{}{
+			"sessions": sessions,
+		})
+	}
+}
+
+func (c *authHandler) revokeSession() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		id, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = c.svc.RevokeSession(ctx.Context(), id); err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(http.StatusNoContent)
+	}
+}
+
+func (c *authHandler) revokeAllSessions() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if err := c.svc.RevokeAllSessions(ctx.Context()); err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(http.StatusNoContent)
+	}
+}
+
 func (c *authHandler) requestOTPResetPassword() fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		var req dto.RequestOTPResetPasswordRequest
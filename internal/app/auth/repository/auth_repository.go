@@ -3,10 +3,13 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
 	"github.com/redis/go-redis/v9"
 	"time"
 )
@@ -23,16 +26,322 @@ func NewAuthRepository(db *sqlx.DB, rds *redis.Client) contract.IAuthRepository
 	}
 }
 
-func (r *authRepository) SetOTPRegisterUser(ctx context.Context, email string, otp string) error {
-	return r.rds.Set(ctx, "auth:"+email+":register_otp", otp, 10*time.Minute).Err()
+func otpKey(purpose enum.OTPPurpose, email string) string {
+	return "auth:" + email + ":" + purpose.String() + "_otp"
 }
 
-func (r *authRepository) GetOTPRegisterUser(ctx context.Context, email string) (string, error) {
-	return r.rds.Get(ctx, "auth:"+email+":register_otp").Result()
+func (r *authRepository) SetOTP(ctx context.Context, purpose enum.OTPPurpose, email, otpHash string,
+	ttl time.Duration) error {
+
+	return r.rds.Set(ctx, otpKey(purpose, email), otpHash, ttl).Err()
+}
+
+// checkOTPScript compares the OTP and counts failures in one step, so parallel guesses can't get past the limit.
+// KEYS: otp, attempts. ARGV: otp hash, max attempts, lock seconds, consume.
+var checkOTPScript = redis.NewScript(`
+local attempts = tonumber(redis.call('GET', KEYS[2]) or '0')
+if attempts >= tonumber(ARGV[2]) then
+	return -2
+end
+
+local stored = redis.call('GET', KEYS[1])
+if not stored then
+	return -1
+end
+
+if stored == ARGV[1] then
+	if ARGV[4] == '1' then
+		redis.call('DEL', KEYS[1], KEYS[2])
+	end
+	return 1
+end
+
+attempts = redis.call('INCR', KEYS[2])
+if attempts == 1 then
+	redis.call('EXPIRE', KEYS[2], ARGV[3])
+end
+if attempts >= tonumber(ARGV[2]) then
+	-- The lock has to run its course before a new OTP can be requested
+	redis.call('DEL', KEYS[1])
+	redis.call('EXPIRE', KEYS[2], ARGV[3])
+end
+return 0
+`)
+
+func (r *authRepository) CheckOTP(ctx context.Context, purpose enum.OTPPurpose, email, otpHash string,
+	maxAttempts int, lockDuration time.Duration, consume bool) (contract.OTPCheckResult, error) {
+
+	key := otpKey(purpose, email)
+	consumeArg := "0"
+	if consume {
+		consumeArg = "1"
+	}
+
+	result, err := checkOTPScript.Run(ctx, r.rds, []string{key, key + "_attempts"},
+		otpHash, maxAttempts, int(lockDuration.Seconds()), consumeArg).Int()
+	if err != nil {
+		return 0, err
+	}
+
+	return contract.OTPCheckResult(result), nil
+}
+
+func (r *authRepository) GetOTPLockRemaining(ctx context.Context, purpose enum.OTPPurpose, email string,
+	maxAttempts int) (time.Duration, error) {
+
+	key := otpKey(purpose, email) + "_attempts"
+
+	attempts, err := r.rds.Get(ctx, key).Int()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	if attempts < maxAttempts {
+		return 0, nil
+	}
+
+	ttl, err := r.rds.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	// Still counts as locked if the key expires between the two calls
+	return max(ttl, time.Second), nil
+}
+
+func (r *authRepository) AcquireOTPCooldown(ctx context.Context, purpose enum.OTPPurpose, email string,
+	cooldown time.Duration) (time.Duration, error) {
+
+	key := otpKey(purpose, email) + "_cooldown"
+
+	ok, err := r.rds.SetNX(ctx, key, 1, cooldown).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if ok {
+		return 0, nil
+	}
+
+	ttl, err := r.rds.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return max(ttl, time.Second), nil
+}
+
+func loginThrottleKey(subject string) string {
+	return "auth:login:" + subject
+}
+
+// recordLoginFailureScript counts a failure and, past the limit, locks the subject with a doubling duration.
+// KEYS: failures, lock. ARGV: window seconds, max failures, lock seconds, max lock seconds.
+var recordLoginFailureScript = redis.NewScript(`
+local failures = redis.call('INCR', KEYS[1])
+if failures == 1 then
+	redis.call('EXPIRE', KEYS[1], ARGV[1])
+end
+
+local excess = failures - tonumber(ARGV[2])
+if excess < 0 then
+	return 0
+end
+
+local lock = math.floor(math.min(tonumber(ARGV[3]) * 2 ^ math.min(excess, 30), tonumber(ARGV[4])))
+redis.call('SET', KEYS[2], 1, 'EX', lock)
+
+-- The count has to outlive the lock, or the next failure would start over from the shortest lock
+if redis.call('TTL', KEYS[1]) < lock + tonumber(ARGV[1]) then
+	redis.call('EXPIRE', KEYS[1], lock + tonumber(ARGV[1]))
+end
+return lock
+`)
+
+func (r *authRepository) RecordLoginFailure(ctx context.Context, subject string, maxFailures int,
+	window, lockDuration, maxLockDuration time.Duration) (time.Duration, error) {
+
+	key := loginThrottleKey(subject)
+
+	lockSeconds, err := recordLoginFailureScript.Run(ctx, r.rds, []string{key + "_failures", key + "_lock"},
+		int(window.Seconds()), maxFailures, int(lockDuration.Seconds()), int(maxLockDuration.Seconds())).Int()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(lockSeconds) * time.Second, nil
+}
+
+func (r *authRepository) GetLoginLockRemaining(ctx context.Context, subject string) (time.Duration, error) {
+	ttl, err := r.rds.TTL(ctx, loginThrottleKey(subject)+"_lock").Result()
+	if err != nil {
+		return 0, err
+	}
+
+	// Negative TTLs mean there is no lock
+	return max(ttl, 0), nil
+}
+
+func (r *authRepository) ClearLoginFailures(ctx context.Context, subject string) error {
+	key := loginThrottleKey(subject)
+	return r.rds.Del(ctx, key+"_failures", key+"_lock").Err()
+}
+
+func mfaChallengeKey(tokenHash string) string {
+	return "auth:mfa_challenge:" + tokenHash
+}
+
+func (r *authRepository) SetMFAChallenge(ctx context.Context, tokenHash string, userID uuid.UUID,
+	ttl time.Duration) error {
+
+	return r.rds.Set(ctx, mfaChallengeKey(tokenHash), userID.String(), ttl).Err()
+}
+
+func (r *authRepository) GetMFAChallenge(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	userID, err := r.rds.Get(ctx, mfaChallengeKey(tokenHash)).Result()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return uuid.Parse(userID)
+}
+
+// useMFAChallengeAttemptScript counts an attempt before the code is checked, so parallel guesses can't get past
+// the limit. The challenge is deleted once the attempts run out.
+// KEYS: challenge, attempts. ARGV: max attempts.
+var useMFAChallengeAttemptScript = redis.NewScript(`
+local userID = redis.call('GET', KEYS[1])
+if not userID then
+	return false
+end
+
+local attempts = redis.call('INCR', KEYS[2])
+if attempts == 1 then
+	redis.call('PEXPIRE', KEYS[2], redis.call('PTTL', KEYS[1]))
+end
+if attempts > tonumber(ARGV[1]) then
+	redis.call('DEL', KEYS[1], KEYS[2])
+	return false
+end
+return userID
+`)
+
+func (r *authRepository) UseMFAChallengeAttempt(ctx context.Context, tokenHash string,
+	maxAttempts int) (uuid.UUID, error) {
+
+	key := mfaChallengeKey(tokenHash)
+
+	userID, err := useMFAChallengeAttemptScript.Run(ctx, r.rds, []string{key, key + "_attempts"},
+		maxAttempts).Text()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return uuid.Parse(userID)
+}
+
+func (r *authRepository) DeleteMFAChallenge(ctx context.Context, tokenHash string) error {
+	key := mfaChallengeKey(tokenHash)
+
+	deleted, err := r.rds.Del(ctx, key, key+"_attempts").Result()
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return redis.Nil
+	}
+
+	return nil
+}
+
+func oidcStateKey(stateHash string) string {
+	return "auth:oidc_state:" + stateHash
+}
+
+func (r *authRepository) SetOIDCState(ctx context.Context, stateHash, nonce, codeVerifier string,
+	ttl time.Duration) error {
+
+	key := oidcStateKey(stateHash)
+
+	_, err := r.rds.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "nonce", nonce, "code_verifier", codeVerifier)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	return err
+}
+
+func (r *authRepository) ConsumeOIDCState(ctx context.Context, stateHash string) (string, string, error) {
+	key := oidcStateKey(stateHash)
+
+	var get *redis.MapStringStringCmd
+	_, err := r.rds.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.HGetAll(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	state := get.Val()
+	if len(state) == 0 {
+		return "", "", redis.Nil
+	}
+
+	return state["nonce"], state["code_verifier"], nil
+}
+
+func (r *authRepository) GetUserIdentity(ctx context.Context, issuer, subject string) (*entity.UserIdentity,
+	error) {
+
+	var identity entity.UserIdentity
+
+	err := r.db.GetContext(ctx, &identity, `
+		SELECT id, user_id, issuer, subject, email, created_at, last_login_at
+		FROM user_identities
+		WHERE issuer = $1
+		AND subject = $2`, issuer, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &identity, nil
 }
 
-func (r *authRepository) DeleteOTPRegisterUser(ctx context.Context, email string) error {
-	return r.rds.Del(ctx, "auth:"+email+":register_otp").Err()
+func (r *authRepository) CreateUserIdentity(ctx context.Context, identity *entity.UserIdentity) error {
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO user_identities (id, user_id, issuer, subject, email, created_at, last_login_at)
+		VALUES (:id, :user_id, :issuer, :subject, :email, :created_at, :last_login_at)`, identity)
+	return err
+}
+
+func (r *authRepository) UpdateUserIdentityLogin(ctx context.Context, id uuid.UUID, email string,
+	lastLoginAt time.Time) error {
+
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE user_identities
+		SET email = $2,
+			last_login_at = $3
+		WHERE id = $1`, id, email, lastLoginAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
 }
 
 func (r *authRepository) CreateAuthSession(ctx context.Context, session *entity.AuthSession) error {
@@ -40,9 +349,8 @@ func (r *authRepository) CreateAuthSession(ctx context.Context, session *entity.
 }
 
 func (r *authRepository) createAuthSession(ctx context.Context, tx sqlx.ExtContext, authSession *entity.AuthSession) error {
-	query := `INSERT INTO auth_sessions (token, user_id, expires_at)
-				VALUES (:token, :user_id, :expires_at)
-				ON CONFLICT (user_id) DO UPDATE SET token = :token, expires_at = :expires_at`
+	query := `INSERT INTO auth_sessions (id, token_hash, user_id, user_agent, ip_address, last_used_at, expires_at)
+				VALUES (:id, :token_hash, :user_id, :user_agent, :ip_address, :last_used_at, :expires_at)`
 
 	_, err := sqlx.NamedExecContext(ctx, tx, query, authSession)
 	if err != nil {
@@ -52,29 +360,96 @@ func (r *authRepository) createAuthSession(ctx context.Context, tx sqlx.ExtConte
 	return nil
 }
 
-func (r *authRepository) GetAuthSessionByToken(ctx context.Context, token string) (*entity.AuthSession, error) {
+const authSessionColumns = `s.id,
+			s.token_hash,
+			s.user_id,
+			s.user_agent,
+			s.ip_address,
+			s.created_at,
+			s.last_used_at,
+			s.expires_at`
+
+func (r *authRepository) GetAuthSessionByTokenHash(ctx context.Context, tokenHash string) (*entity.AuthSession, error) {
 	var authSession entity.AuthSession
 
+	statement := `SELECT ` + authSessionColumns + `
+		FROM auth_sessions s
+		WHERE s.token_hash = $1
+		`
+
+	err := r.db.GetContext(ctx, &authSession, statement, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authSession, nil
+}
+
+func (r *authRepository) GetAuthSessionByRotatedTokenHash(ctx context.Context,
+	tokenHash string) (*entity.AuthSession, error) {
+
+	var authSession entity.AuthSession
+
+	statement := `SELECT ` + authSessionColumns + `
+		FROM auth_session_rotated_tokens rt
+		JOIN auth_sessions s ON rt.session_id = s.id
+		WHERE rt.token_hash = $1
+		`
+
+	err := r.db.GetContext(ctx, &authSession, statement, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authSession, nil
+}
+
+func (r *authRepository) GetAuthSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]entity.AuthSession, error) {
+	var authSessions []entity.AuthSession
+
 	statement := `SELECT
-    		token,
+			id,
 			user_id,
+			user_agent,
+			ip_address,
+			created_at,
+			last_used_at,
 			expires_at
 		FROM auth_sessions
-		WHERE token = $1
+		WHERE user_id = $1
+		AND expires_at > now()
+		ORDER BY last_used_at DESC
 		`
 
-	err := r.db.GetContext(ctx, &authSession, statement, token)
+	err := r.db.SelectContext(ctx, &authSessions, statement, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &authSession, nil
+	return authSessions, nil
 }
 
-func (r *authRepository) deleteAuthSession(ctx context.Context, tx sqlx.ExtContext, userID uuid.UUID) error {
-	query := `DELETE FROM auth_sessions WHERE user_id = $1`
+func (r *authRepository) RotateAuthSession(ctx context.Context, authSession *entity.AuthSession,
+	oldTokenHash string) error {
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	res, err := tx.ExecContext(ctx, query, userID)
+	// Only succeeds for the token the session currently holds, so two refreshes with the same token
+	// can't both rotate it
+	res, err := tx.ExecContext(ctx, `UPDATE auth_sessions
+		SET token_hash = $3,
+			user_agent = $4,
+			ip_address = $5,
+			last_used_at = $6,
+			expires_at = $7
+		WHERE id = $1
+		AND token_hash = $2`,
+		authSession.ID, oldTokenHash, authSession.TokenHash, authSession.UserAgent, authSession.IPAddress,
+		authSession.LastUsedAt, authSession.ExpiresAt)
 	if err != nil {
 		return err
 	}
@@ -88,21 +463,63 @@ func (r *authRepository) deleteAuthSession(ctx context.Context, tx sqlx.ExtConte
 		return sql.ErrNoRows
 	}
 
-	return nil
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO auth_session_rotated_tokens (token_hash, session_id, rotated_at)
+		VALUES ($1, $2, $3)`, oldTokenHash, authSession.ID, authSession.LastUsedAt); err != nil {
+		return fmt.Errorf("failed to record rotated token: %w", err)
+	}
+
+	return tx.Commit()
 }
 
-func (r *authRepository) DeleteAuthSession(ctx context.Context, userID uuid.UUID) error {
-	return r.deleteAuthSession(ctx, r.db, userID)
+func (r *authRepository) deleteAuthSession(ctx context.Context, tx sqlx.ExtContext, userID, id uuid.UUID) error {
+	query := `DELETE FROM auth_sessions WHERE user_id = $1 AND id = $2`
+
+	res, err := tx.ExecContext(ctx, query, userID, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
 }
 
-func (r *authRepository) SetOTPResetPassword(ctx context.Context, email, otp string) error {
-	return r.rds.Set(ctx, "auth:"+email+":reset_password_otp", otp, 10*time.Minute).Err()
+func (r *authRepository) DeleteAuthSession(ctx context.Context, userID, id uuid.UUID) error {
+	return r.deleteAuthSession(ctx, r.db, userID, id)
 }
 
-func (r *authRepository) GetOTPResetPassword(ctx context.Context, email string) (string, error) {
-	return r.rds.Get(ctx, "auth:"+email+":reset_password_otp").Result()
+func (r *authRepository) DeleteAuthSessionsByUserID(ctx context.Context, userID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM auth_sessions WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
 }
 
-func (r *authRepository) DeleteOTPResetPassword(ctx context.Context, email string) error {
-	return r.rds.Del(ctx, "auth:"+email+":reset_password_otp").Err()
+func (r *authRepository) DeleteExpiredAuthSessions(ctx context.Context) (int64, error) {
+	// Rotated tokens of these sessions go with them
+	res, err := r.db.ExecContext(ctx, `DELETE FROM auth_sessions WHERE expires_at <= now()`)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
 }
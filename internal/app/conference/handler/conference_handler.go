@@ -36,6 +36,37 @@ func InitConferenceHandler(
 		midw.RequireOneOfRoles(enum.RoleUser),
 		handler.createConferenceProposal(),
 	)
+	conferenceGroup.Post("/series",
+		midw.RequireOneOfRoles(enum.RoleUser),
+		handler.createConferenceSeries(),
+	)
+	conferenceGroup.Get("/series/:id",
+		handler.getConferenceSeriesByID(),
+	)
+	conferenceGroup.Patch("/series/:id/status",
+		midw.RequireOneOfRoles(enum.RoleEventCoordinator),
+		handler.updateConferenceSeriesStatus(),
+	)
+	conferenceGroup.Patch("/reschedule-requests/:id",
+		midw.RequireOneOfRoles(enum.RoleEventCoordinator),
+		handler.reviewRescheduleRequest(),
+	)
+	conferenceGroup.Get("/ownership-transfers",
+		midw.RequireOneOfRoles(enum.RoleUser),
+		handler.getPendingOwnershipTransfers(),
+	)
+	conferenceGroup.Post("/ownership-transfers/:id/accept",
+		midw.RequireOneOfRoles(enum.RoleUser),
+		handler.respondOwnershipTransfer(true),
+	)
+	conferenceGroup.Post("/ownership-transfers/:id/decline",
+		midw.RequireOneOfRoles(enum.RoleUser),
+		handler.respondOwnershipTransfer(false),
+	)
+	conferenceGroup.Delete("/ownership-transfers/:id",
+		midw.RequireOneOfRoles(enum.RoleUser),
+		handler.cancelOwnershipTransfer(),
+	)
 	conferenceGroup.Get("/:id",
 		handler.getConferenceByID(),
 	)
@@ -54,6 +85,36 @@ func InitConferenceHandler(
 		midw.RequireOneOfRoles(enum.RoleEventCoordinator),
 		handler.updateConferenceStatus(),
 	)
+	conferenceGroup.Post("/:id/resubmit",
+		midw.RequireOneOfRoles(enum.RoleUser),
+		handler.resubmitConference(),
+	)
+	conferenceGroup.Post("/:id/cancel",
+		midw.RequireOneOfRoles(enum.RoleUser, enum.RoleEventCoordinator),
+		handler.cancelConference(),
+	)
+	conferenceGroup.Post("/:id/reschedule",
+		midw.RequireOneOfRoles(enum.RoleUser),
+		handler.requestReschedule(),
+	)
+	conferenceGroup.Get("/:id/reschedule-requests",
+		handler.getRescheduleRequests(),
+	)
+	conferenceGroup.Get("/:id/hosts",
+		handler.getConferenceHosts(),
+	)
+	conferenceGroup.Post("/:id/hosts",
+		midw.RequireOneOfRoles(enum.RoleUser),
+		handler.addCoHost(),
+	)
+	conferenceGroup.Delete("/:id/hosts/:userId",
+		midw.RequireOneOfRoles(enum.RoleUser),
+		handler.removeCoHost(),
+	)
+	conferenceGroup.Post("/:id/ownership-transfers",
+		midw.RequireOneOfRoles(enum.RoleUser),
+		handler.transferOwnership(),
+	)
 }
 
 func (c *conferenceHandler) createConferenceProposal() fiber.Handler {
@@ -68,6 +129,7 @@ func (c *conferenceHandler) createConferenceProposal() fiber.Handler {
 			Seats          int     `json:"seats" validate:"required,min=1"`
 			StartsAt       string  `json:"starts_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
 			EndsAt         string  `json:"ends_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+			RoomID         string  `json:"room_id" validate:"required,uuid"`
 		}
 
 		var req request
@@ -81,7 +143,8 @@ func (c *conferenceHandler) createConferenceProposal() fiber.Handler {
 
 		startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
 		endsAt, err2 := time.Parse(time.RFC3339, req.EndsAt)
-		if err != nil || err2 != nil {
+		roomID, err3 := uuid.Parse(req.RoomID)
+		if err != nil || err2 != nil || err3 != nil {
 			return errorpkg.ErrFailParseRequest
 		}
 
@@ -95,6 +158,7 @@ func (c *conferenceHandler) createConferenceProposal() fiber.Handler {
 			Seats:          req.Seats,
 			StartsAt:       startsAt,
 			EndsAt:         endsAt,
+			RoomID:         roomID,
 		}
 
 		conferenceID, err := c.svc.CreateConferenceProposal(ctx.Context(), &proposal)
@@ -133,7 +197,8 @@ func (c *conferenceHandler) getConferences() fiber.Handler {
 			BeforeID     *uuid.UUID            `query:"before_id" validate:"omitempty,uuid"`
 			Limit        int                   `query:"limit" validate:"required,min=1,max=20"`
 			HostID       *uuid.UUID            `query:"host_id" validate:"omitempty,uuid"`
-			Status       enum.ConferenceStatus `query:"status" validate:"required,oneof=pending approved rejected"`
+			RoomID       *uuid.UUID            `query:"room_id" validate:"omitempty,uuid"`
+			Status       enum.ConferenceStatus `query:"status" validate:"required,oneof=pending approved rejected needs_revision cancelled"`
 			StartsBefore *string               `query:"starts_before" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
 			StartsAfter  *string               `query:"starts_after" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
 			IncludePast  bool                  `query:"include_past" validate:"omitempty"`
@@ -173,6 +238,7 @@ func (c *conferenceHandler) getConferences() fiber.Handler {
 			BeforeID:     req.BeforeID,
 			Limit:        req.Limit,
 			HostID:       req.HostID,
+			RoomID:       req.RoomID,
 			Status:       req.Status,
 			StartsBefore: startsBefore,
 			StartsAfter:  startsAfter,
@@ -205,6 +271,7 @@ func (c *conferenceHandler) updateConference() fiber.Handler {
 			Prerequisites  *string `json:"prerequisites" validate:"omitempty,max=255"`
 			StartsAt       *string `json:"starts_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
 			EndsAt         *string `json:"ends_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+			RoomID         *string `json:"room_id" validate:"omitempty,uuid"`
 		}
 
 		conferenceID, err := uuid.Parse(ctx.Params("id"))
@@ -218,7 +285,7 @@ func (c *conferenceHandler) updateConference() fiber.Handler {
 		}
 
 		if err2 := c.val.ValidateStruct(req); err2 != nil {
-			return err
+			return err2
 		}
 
 		var startsAt, endsAt *time.Time
@@ -238,6 +305,15 @@ func (c *conferenceHandler) updateConference() fiber.Handler {
 			endsAt = &endsAtValue
 		}
 
+		var roomID *uuid.UUID
+		if req.RoomID != nil {
+			roomIDValue, err2 := uuid.Parse(*req.RoomID)
+			if err2 != nil {
+				return errorpkg.ErrFailParseRequest
+			}
+			roomID = &roomIDValue
+		}
+
 		conference := dto.UpdateConferenceRequest{
 			Title:          req.Title,
 			Description:    req.Description,
@@ -247,6 +323,7 @@ func (c *conferenceHandler) updateConference() fiber.Handler {
 			Prerequisites:  req.Prerequisites,
 			StartsAt:       startsAt,
 			EndsAt:         endsAt,
+			RoomID:         roomID,
 		}
 
 		if err = c.svc.UpdateConference(ctx.Context(), conferenceID, conference); err != nil {
@@ -275,7 +352,215 @@ func (c *conferenceHandler) deleteConference() fiber.Handler {
 func (c *conferenceHandler) updateConferenceStatus() fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		type request struct {
-			Status enum.ConferenceStatus `json:"status" validate:"required,oneof=pending approved rejected"`
+			Status  enum.ConferenceStatus `json:"status" validate:"required,oneof=approved rejected needs_revision"`
+			Comment *string               `json:"comment" validate:"omitempty,min=3,max=1000"`
+		}
+
+		conferenceID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		var req request
+		if err = ctx.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = c.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		if err = c.svc.UpdateConferenceStatus(ctx.Context(), conferenceID, req.Status, req.Comment); err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *conferenceHandler) resubmitConference() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		type request struct {
+			Comment *string `json:"comment" validate:"omitempty,min=3,max=1000"`
+		}
+
+		conferenceID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		var req request
+		if len(ctx.Body()) > 0 {
+			if err = ctx.BodyParser(&req); err != nil {
+				return errorpkg.ErrFailParseRequest
+			}
+		}
+
+		if err = c.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		if err = c.svc.ResubmitConference(ctx.Context(), conferenceID, req.Comment); err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *conferenceHandler) cancelConference() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		type request struct {
+			Reason string `json:"reason" validate:"required,min=3,max=1000"`
+		}
+
+		conferenceID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		var req request
+		if err = ctx.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = c.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		if err = c.svc.CancelConference(ctx.Context(), conferenceID, req.Reason); err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *conferenceHandler) createConferenceSeries() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		type request struct {
+			Title          string  `json:"title" validate:"required,min=3,max=100"`
+			Description    string  `json:"description" validate:"required,min=3,max=1000"`
+			SpeakerName    string  `json:"speaker_name" validate:"required,min=3,max=100"`
+			SpeakerTitle   string  `json:"speaker_title" validate:"required,min=3,max=100"`
+			TargetAudience string  `json:"target_audience" validate:"required,min=3,max=255"`
+			Prerequisites  *string `json:"prerequisites" validate:"omitempty,max=255"`
+			Seats          int     `json:"seats" validate:"required,min=1"`
+			StartsAt       string  `json:"starts_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+			EndsAt         string  `json:"ends_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+			RoomID         string  `json:"room_id" validate:"required,uuid"`
+			Frequency      string  `json:"frequency" validate:"required,oneof=weekly biweekly monthly"`
+			RepeatUntil    *string `json:"repeat_until" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+			RepeatCount    *int    `json:"repeat_count" validate:"omitempty,min=2,max=52"`
+		}
+
+		var req request
+		if err := ctx.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := c.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+		endsAt, err2 := time.Parse(time.RFC3339, req.EndsAt)
+		roomID, err3 := uuid.Parse(req.RoomID)
+		if err != nil || err2 != nil || err3 != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		var repeatUntil *time.Time
+		if req.RepeatUntil != nil {
+			repeatUntilValue, err4 := time.Parse(time.RFC3339, *req.RepeatUntil)
+			if err4 != nil {
+				return errorpkg.ErrFailParseRequest
+			}
+			repeatUntil = &repeatUntilValue
+		}
+
+		series := dto.CreateConferenceSeriesRequest{
+			CreateConferenceProposalRequest: dto.CreateConferenceProposalRequest{
+				Title:          req.Title,
+				Description:    req.Description,
+				SpeakerName:    req.SpeakerName,
+				SpeakerTitle:   req.SpeakerTitle,
+				TargetAudience: req.TargetAudience,
+				Prerequisites:  req.Prerequisites,
+				Seats:          req.Seats,
+				StartsAt:       startsAt,
+				EndsAt:         endsAt,
+				RoomID:         roomID,
+			},
+			Frequency:   enum.RecurrenceFrequency(req.Frequency),
+			RepeatUntil: repeatUntil,
+			RepeatCount: req.RepeatCount,
+		}
+
+		seriesID, err := c.svc.CreateConferenceSeries(ctx.Context(), &series)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusCreated).JSON(map[string]interface{}{
+			"series": dto.ConferenceSeriesResponse{ID: seriesID},
+		})
+	}
+}
+
+func (c *conferenceHandler) getConferenceSeriesByID() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		seriesID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		series, err := c.svc.GetConferenceSeriesByID(ctx.Context(), seriesID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.JSON(map[string]interface{}{
+			"series": series,
+		})
+	}
+}
+
+func (c *conferenceHandler) updateConferenceSeriesStatus() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		type request struct {
+			Status  enum.ConferenceStatus `json:"status" validate:"required,oneof=approved rejected"`
+			Comment *string               `json:"comment" validate:"omitempty,min=3,max=1000"`
+		}
+
+		seriesID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		var req request
+		if err = ctx.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = c.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		if err = c.svc.UpdateConferenceSeriesStatus(ctx.Context(), seriesID, req.Status, req.Comment); err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *conferenceHandler) requestReschedule() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		type request struct {
+			StartsAt string  `json:"starts_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+			EndsAt   string  `json:"ends_at" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+			Reason   *string `json:"reason" validate:"omitempty,min=3,max=1000"`
 		}
 
 		conferenceID, err := uuid.Parse(ctx.Params("id"))
@@ -292,7 +577,210 @@ func (c *conferenceHandler) updateConferenceStatus() fiber.Handler {
 			return err
 		}
 
-		if err = c.svc.UpdateConferenceStatus(ctx.Context(), conferenceID, req.Status); err != nil {
+		startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+		endsAt, err2 := time.Parse(time.RFC3339, req.EndsAt)
+		if err != nil || err2 != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		requestID, err := c.svc.RequestReschedule(ctx.Context(), conferenceID, &dto.CreateRescheduleRequest{
+			StartsAt: startsAt,
+			EndsAt:   endsAt,
+			Reason:   req.Reason,
+		})
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusCreated).JSON(map[string]interface{}{
+			"reschedule_request": dto.RescheduleRequestResponse{ID: requestID},
+		})
+	}
+}
+
+func (c *conferenceHandler) getRescheduleRequests() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		requests, err := c.svc.GetRescheduleRequests(ctx.Context(), conferenceID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.JSON(map[string]interface{}{
+			"reschedule_requests": requests,
+		})
+	}
+}
+
+func (c *conferenceHandler) reviewRescheduleRequest() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		type request struct {
+			Status  enum.RescheduleStatus `json:"status" validate:"required,oneof=approved rejected"`
+			Comment *string               `json:"comment" validate:"omitempty,min=3,max=1000"`
+		}
+
+		requestID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		var req request
+		if err = ctx.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = c.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		conflicted, err := c.svc.ReviewRescheduleRequest(ctx.Context(), requestID, req.Status, req.Comment)
+		if err != nil {
+			return err
+		}
+
+		return ctx.JSON(map[string]interface{}{
+			"conflicting_attendees": conflicted,
+		})
+	}
+}
+
+func (c *conferenceHandler) getConferenceHosts() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		hosts, err := c.svc.GetConferenceHosts(ctx.Context(), conferenceID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.JSON(map[string]interface{}{
+			"hosts": hosts,
+		})
+	}
+}
+
+func (c *conferenceHandler) addCoHost() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		type request struct {
+			UserID string `json:"user_id" validate:"required,uuid"`
+		}
+
+		conferenceID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		var req request
+		if err = ctx.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = c.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		if err = c.svc.AddCoHost(ctx.Context(), conferenceID, uuid.MustParse(req.UserID)); err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusCreated)
+	}
+}
+
+func (c *conferenceHandler) removeCoHost() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		userID, err := uuid.Parse(ctx.Params("userId"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = c.svc.RemoveCoHost(ctx.Context(), conferenceID, userID); err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *conferenceHandler) transferOwnership() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		type request struct {
+			UserID string `json:"user_id" validate:"required,uuid"`
+		}
+
+		conferenceID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		var req request
+		if err = ctx.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = c.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		transferID, err := c.svc.TransferOwnership(ctx.Context(), conferenceID, uuid.MustParse(req.UserID))
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusCreated).JSON(map[string]interface{}{
+			"ownership_transfer": dto.OwnershipTransferResponse{ID: transferID},
+		})
+	}
+}
+
+func (c *conferenceHandler) getPendingOwnershipTransfers() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		transfers, err := c.svc.GetPendingOwnershipTransfers(ctx.Context())
+		if err != nil {
+			return err
+		}
+
+		return ctx.JSON(map[string]interface{}{
+			"ownership_transfers": transfers,
+		})
+	}
+}
+
+func (c *conferenceHandler) respondOwnershipTransfer(accept bool) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		transferID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = c.svc.RespondOwnershipTransfer(ctx.Context(), transferID, accept); err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (c *conferenceHandler) cancelOwnershipTransfer() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		transferID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = c.svc.CancelOwnershipTransfer(ctx.Context(), transferID); err != nil {
 			return err
 		}
 
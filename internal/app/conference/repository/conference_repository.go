@@ -12,6 +12,7 @@ import (
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
 )
 
 type conferenceRepository struct {
@@ -33,22 +34,39 @@ func (r *conferenceRepository) createConference(ctx context.Context, tx sqlx.Ext
 		`INSERT INTO conferences (
                          id, title, description, speaker_name, speaker_title,
                          target_audience, prerequisites, seats, starts_at, ends_at,
-                         host_id, status
+                         host_id, room_id, series_id, status
 					) VALUES (
 					          :id, :title, :description, :speaker_name, :speaker_title,
 					          :target_audience, :prerequisites, :seats, :starts_at, :ends_at,
-					          :host_id, :status)`,
+					          :host_id, :room_id, :series_id, :status)`,
 		conference,
 	)
 	if err != nil {
 		return err
 	}
 
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO conference_hosts (conference_id, user_id, role)
+		VALUES ($1, $2, 'owner')`, conference.ID, conference.HostID)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (r *conferenceRepository) CreateConference(ctx context.Context, conference *entity.Conference) error {
-	return r.createConference(ctx, r.db, conference)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = r.createConference(ctx, tx, conference); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *conferenceRepository) GetConferenceByID(ctx context.Context, id uuid.UUID) (*entity.Conference, error) {
@@ -57,17 +75,22 @@ func (r *conferenceRepository) GetConferenceByID(ctx context.Context, id uuid.UU
 	statement := `SELECT
 						c.id, c.title, c.description, c.speaker_name, c.speaker_title,
 						c.target_audience, c.prerequisites, c.seats, c.starts_at, c.ends_at,
-						c.host_id, c.status, c.created_at, c.updated_at, u.name AS host_name,
+						c.host_id, c.room_id, c.series_id, c.status, c.created_at, c.updated_at, u.name AS host_name,
+						c.cancellation_reason, c.cancelled_at,
+						rm.name AS room_name, rm.building AS room_building,
 						COUNT(r.user_id) AS registration_count
 					FROM conferences c
 					JOIN users u ON c.host_id = u.id
-					LEFT JOIN registrations r ON c.id = r.conference_id
+					JOIN rooms rm ON c.room_id = rm.id
+					LEFT JOIN registrations r ON c.id = r.conference_id AND r.cancelled_at IS NULL
 					WHERE c.id = $1
 					AND c.deleted_at IS NULL
 					GROUP BY
 						c.id, c.title, c.description, c.speaker_name, c.speaker_title,
 						c.target_audience, c.prerequisites, c.seats, c.starts_at, c.ends_at,
-						c.host_id, c.status, c.created_at, c.updated_at, u.name
+						c.host_id, c.room_id, c.series_id, c.status, c.created_at, c.updated_at, u.name,
+						c.cancellation_reason, c.cancelled_at,
+						rm.name, rm.building
 		`
 
 	err := r.db.GetContext(ctx, &row, statement, id)
@@ -87,11 +110,14 @@ func (r *conferenceRepository) GetConferences(ctx context.Context,
         SELECT
             c.id, c.title, c.description, c.speaker_name, c.speaker_title,
             c.target_audience, c.prerequisites, c.seats, c.starts_at, c.ends_at,
-            c.host_id, c.status, c.created_at, c.updated_at, u.name AS host_name,
+            c.host_id, c.room_id, c.series_id, c.status, c.created_at, c.updated_at, u.name AS host_name,
+            c.cancellation_reason, c.cancelled_at,
+            rm.name AS room_name, rm.building AS room_building,
             COUNT(r.user_id) AS registration_count
         FROM conferences c
         JOIN users u ON c.host_id = u.id
-        LEFT JOIN registrations r ON c.id = r.conference_id
+        JOIN rooms rm ON c.room_id = rm.id
+        LEFT JOIN registrations r ON c.id = r.conference_id AND r.cancelled_at IS NULL
         WHERE c.deleted_at IS NULL`
 
 	// Initialize query arguments
@@ -112,7 +138,14 @@ func (r *conferenceRepository) GetConferences(ctx context.Context,
 
 	if query.HostID != nil {
 		args = append(args, query.HostID)
-		conditions = append(conditions, fmt.Sprintf("c.host_id = $%d", len(args)))
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM conference_hosts ch WHERE ch.conference_id = c.id AND ch.user_id = $%d)",
+			len(args)))
+	}
+
+	if query.RoomID != nil {
+		args = append(args, query.RoomID)
+		conditions = append(conditions, fmt.Sprintf("c.room_id = $%d", len(args)))
 	}
 
 	args = append(args, query.Status)
@@ -193,7 +226,9 @@ func (r *conferenceRepository) GetConferences(ctx context.Context,
         GROUP BY
             c.id, c.title, c.description, c.speaker_name, c.speaker_title,
             c.target_audience, c.prerequisites, c.seats, c.starts_at, c.ends_at,
-            c.host_id, c.status, c.created_at, c.updated_at, u.name`
+            c.host_id, c.room_id, c.series_id, c.status, c.created_at, c.updated_at, u.name,
+            c.cancellation_reason, c.cancelled_at,
+            rm.name, rm.building`
 
 	// Add ORDER BY clause
 	if query.OrderBy == "c.created_at" {
@@ -275,6 +310,7 @@ func (r *conferenceRepository) updateConference(ctx context.Context, tx sqlx.Ext
 			starts_at = :starts_at,
 			ends_at = :ends_at,
 			host_id = :host_id,
+			room_id = :room_id,
 			status = :status,
 			updated_at = now()
 		WHERE id = :id`,
@@ -322,8 +358,8 @@ func (r *conferenceRepository) DeleteConference(ctx context.Context, id uuid.UUI
 	return r.deleteConference(ctx, r.db, id)
 }
 
-func (r *conferenceRepository) GetConferencesConflictingWithTime(ctx context.Context, startsAt,
-	endsAt time.Time, excludeID uuid.UUID) ([]entity.Conference, error) {
+func (r *conferenceRepository) GetConferencesConflictingWithTime(ctx context.Context, roomID uuid.UUID,
+	startsAt, endsAt time.Time, excludeID uuid.UUID) ([]entity.Conference, error) {
 
 	var conferences []entity.Conference
 
@@ -331,19 +367,631 @@ func (r *conferenceRepository) GetConferencesConflictingWithTime(ctx context.Con
 		SELECT
 			c.id, c.title, c.description, c.speaker_name, c.speaker_title,
 			c.target_audience, c.prerequisites, c.seats, c.starts_at, c.ends_at,
-			c.host_id, c.status, c.created_at, c.updated_at
+			c.host_id, c.room_id, c.status, c.created_at, c.updated_at
 		FROM conferences c
 		WHERE c.deleted_at IS NULL
 		AND c.id != $1
+		AND c.room_id = $2
 		AND c.status = 'approved'
-		AND c.starts_at < $2
-		AND c.ends_at > $3
+		AND c.starts_at < $3
+		AND c.ends_at > $4
 		ORDER BY c.starts_at
 		LIMIT 10
-		`, excludeID, endsAt, startsAt)
+		`, excludeID, roomID, endsAt, startsAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return conferences, nil
+}
+
+func (r *conferenceRepository) CreateConferenceSeries(ctx context.Context, series *entity.ConferenceSeries,
+	conferences []entity.Conference) error {
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = sqlx.NamedExecContext(
+		ctx,
+		tx,
+		`INSERT INTO conference_series (id, host_id, frequency, repeat_until, repeat_count)
+		VALUES (:id, :host_id, :frequency, :repeat_until, :repeat_count)`,
+		series,
+	)
+	if err != nil {
+		return err
+	}
+
+	for i := range conferences {
+		if err = r.createConference(ctx, tx, &conferences[i]); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *conferenceRepository) GetConferenceSeriesByID(ctx context.Context,
+	id uuid.UUID) (*entity.ConferenceSeries, error) {
+
+	var series entity.ConferenceSeries
+
+	err := r.db.GetContext(ctx, &series, `
+		SELECT id, host_id, frequency, repeat_until, repeat_count, created_at, updated_at, deleted_at
+		FROM conference_series
+		WHERE id = $1
+		AND deleted_at IS NULL`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+func (r *conferenceRepository) GetConferencesBySeriesID(ctx context.Context,
+	seriesID uuid.UUID) ([]entity.Conference, error) {
+
+	var rows []dto.ConferenceJoinUserRow
+
+	err := r.db.SelectContext(ctx, &rows, `
+		SELECT
+			c.id, c.title, c.description, c.speaker_name, c.speaker_title,
+			c.target_audience, c.prerequisites, c.seats, c.starts_at, c.ends_at,
+			c.host_id, c.room_id, c.series_id, c.status, c.created_at, c.updated_at, u.name AS host_name,
+			c.cancellation_reason, c.cancelled_at,
+			rm.name AS room_name, rm.building AS room_building,
+			COUNT(r.user_id) AS registration_count
+		FROM conferences c
+		JOIN users u ON c.host_id = u.id
+		JOIN rooms rm ON c.room_id = rm.id
+		LEFT JOIN registrations r ON c.id = r.conference_id AND r.cancelled_at IS NULL
+		WHERE c.series_id = $1
+		AND c.deleted_at IS NULL
+		GROUP BY
+			c.id, c.title, c.description, c.speaker_name, c.speaker_title,
+			c.target_audience, c.prerequisites, c.seats, c.starts_at, c.ends_at,
+			c.host_id, c.room_id, c.series_id, c.status, c.created_at, c.updated_at, u.name,
+			c.cancellation_reason, c.cancelled_at,
+			rm.name, rm.building
+		ORDER BY c.starts_at`, seriesID)
 	if err != nil {
 		return nil, err
 	}
 
+	conferences := make([]entity.Conference, len(rows))
+	for i := range rows {
+		conferences[i] = rows[i].ToEntity()
+	}
+
 	return conferences, nil
 }
+
+func (r *conferenceRepository) createConferenceReview(ctx context.Context, tx sqlx.ExtContext,
+	review *entity.ConferenceReview) error {
+
+	_, err := sqlx.NamedExecContext(
+		ctx,
+		tx,
+		`INSERT INTO conference_reviews (id, conference_id, reviewer_id, status, comment)
+		VALUES (:id, :conference_id, :reviewer_id, :status, :comment)`,
+		review,
+	)
+	return err
+}
+
+func (r *conferenceRepository) UpdateConferencesStatus(ctx context.Context, from enum.ConferenceStatus,
+	reviews []entity.ConferenceReview) error {
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i := range reviews {
+		res, err2 := tx.ExecContext(ctx, `
+			UPDATE conferences
+			SET status = $1,
+				updated_at = now()
+			WHERE id = $2
+			AND status = $3
+			AND deleted_at IS NULL`, reviews[i].Status, reviews[i].ConferenceID, from)
+		if err2 != nil {
+			return err2
+		}
+
+		rowsAffected, err2 := res.RowsAffected()
+		if err2 != nil {
+			return err2
+		}
+
+		if rowsAffected == 0 {
+			return sql.ErrNoRows
+		}
+
+		if err2 = r.createConferenceReview(ctx, tx, &reviews[i]); err2 != nil {
+			return err2
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *conferenceRepository) GetConferenceReviews(ctx context.Context,
+	conferenceID uuid.UUID) ([]entity.ConferenceReview, error) {
+
+	rows, err := r.db.QueryxContext(ctx, `
+		SELECT cr.id, cr.conference_id, cr.reviewer_id, cr.status, cr.comment, cr.created_at,
+			u.name, u.role
+		FROM conference_reviews cr
+		JOIN users u ON cr.reviewer_id = u.id
+		WHERE cr.conference_id = $1
+		ORDER BY cr.created_at, cr.id`, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []entity.ConferenceReview
+	for rows.Next() {
+		var review entity.ConferenceReview
+		if err = rows.Scan(&review.ID, &review.ConferenceID, &review.ReviewerID, &review.Status,
+			&review.Comment, &review.CreatedAt, &review.Reviewer.Name, &review.Reviewer.Role); err != nil {
+			return nil, err
+		}
+		review.Reviewer.ID = review.ReviewerID
+		reviews = append(reviews, review)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// GetConferenceRatingStats returns the rating totals of the given conferences. Conferences nobody rated are omitted.
+func (r *conferenceRepository) GetConferenceRatingStats(ctx context.Context,
+	conferenceIDs []uuid.UUID) ([]entity.ConferenceRatingStats, error) {
+
+	if len(conferenceIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT conference_id, rating_count, rating_sum, rating_1, rating_2, rating_3, rating_4, rating_5,
+			content_count, content_sum, speaker_count, speaker_sum, venue_count, venue_sum
+		FROM conference_rating_stats
+		WHERE conference_id IN (?)
+		AND rating_count > 0`, conferenceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []entity.ConferenceRatingStats
+	if err = r.db.SelectContext(ctx, &stats, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (r *conferenceRepository) CancelConference(ctx context.Context, id uuid.UUID, reason string,
+	cancelledBy uuid.UUID) error {
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE conferences
+		SET status = 'cancelled',
+			cancellation_reason = $1,
+			cancelled_at = now(),
+			cancelled_by = $2,
+			updated_at = now()
+		WHERE id = $3
+		AND status = 'approved'
+		AND deleted_at IS NULL`, reason, cancelledBy, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	// Nobody can take a seat anymore, so close the waitlist as well
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE waitlist_entries
+		SET status = 'expired',
+			updated_at = now()
+		WHERE conference_id = $1
+		AND status IN ('waiting', 'offered')`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *conferenceRepository) GetRegisteredUsersByConference(ctx context.Context,
+	conferenceID uuid.UUID) ([]entity.User, error) {
+
+	var users []entity.User
+
+	err := r.db.SelectContext(ctx, &users, `
+		SELECT u.id, u.name, u.email
+		FROM registrations r
+		JOIN users u ON r.user_id = u.id
+		WHERE r.conference_id = $1
+		AND r.cancelled_at IS NULL
+		AND u.deleted_at IS NULL`, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *conferenceRepository) CreateRescheduleRequest(ctx context.Context,
+	request *entity.RescheduleRequest) error {
+
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO conference_reschedule_requests (id, conference_id, requested_by, starts_at, ends_at, reason)
+		VALUES (:id, :conference_id, :requested_by, :starts_at, :ends_at, :reason)`, request)
+	return err
+}
+
+func (r *conferenceRepository) GetRescheduleRequestByID(ctx context.Context,
+	id uuid.UUID) (*entity.RescheduleRequest, error) {
+
+	var request entity.RescheduleRequest
+
+	err := r.db.GetContext(ctx, &request, `
+		SELECT id, conference_id, requested_by, starts_at, ends_at, reason, status,
+			reviewed_by, review_comment, reviewed_at, created_at, updated_at
+		FROM conference_reschedule_requests
+		WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+func (r *conferenceRepository) GetRescheduleRequestsByConference(ctx context.Context,
+	conferenceID uuid.UUID) ([]entity.RescheduleRequest, error) {
+
+	var requests []entity.RescheduleRequest
+
+	err := r.db.SelectContext(ctx, &requests, `
+		SELECT id, conference_id, requested_by, starts_at, ends_at, reason, status,
+			reviewed_by, review_comment, reviewed_at, created_at, updated_at
+		FROM conference_reschedule_requests
+		WHERE conference_id = $1
+		ORDER BY created_at DESC`, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+func (r *conferenceRepository) reviewRescheduleRequest(ctx context.Context, tx sqlx.ExtContext,
+	request *entity.RescheduleRequest) error {
+
+	res, err := sqlx.NamedExecContext(ctx, tx, `
+		UPDATE conference_reschedule_requests
+		SET status = :status,
+			reviewed_by = :reviewed_by,
+			review_comment = :review_comment,
+			reviewed_at = now(),
+			updated_at = now()
+		WHERE id = :id
+		AND status = 'pending'`, request)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *conferenceRepository) RejectRescheduleRequest(ctx context.Context,
+	request *entity.RescheduleRequest) error {
+
+	return r.reviewRescheduleRequest(ctx, r.db, request)
+}
+
+func (r *conferenceRepository) ApproveRescheduleRequest(ctx context.Context, request *entity.RescheduleRequest,
+	reconfirmBy time.Time) error {
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = r.reviewRescheduleRequest(ctx, tx, request); err != nil {
+		return err
+	}
+
+	// The conference may have been cancelled or started while the request was waiting for review
+	res, err := tx.ExecContext(ctx, `
+		UPDATE conferences
+		SET starts_at = $1,
+			ends_at = $2,
+			updated_at = now()
+		WHERE id = $3
+		AND status = 'approved'
+		AND starts_at > now()
+		AND deleted_at IS NULL`, request.StartsAt, request.EndsAt, request.ConferenceID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	// Attendees keep their seats only if they confirm the new schedule in time
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE registrations
+		SET reconfirm_by = $1
+		WHERE conference_id = $2
+		AND cancelled_at IS NULL`, reconfirmBy, request.ConferenceID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *conferenceRepository) GetConferenceHosts(ctx context.Context,
+	conferenceID uuid.UUID) ([]entity.ConferenceHost, error) {
+
+	rows, err := r.db.QueryxContext(ctx, `
+		SELECT ch.conference_id, ch.user_id, ch.role, ch.created_at, u.name, u.role, u.bio
+		FROM conference_hosts ch
+		JOIN users u ON ch.user_id = u.id
+		WHERE ch.conference_id = $1
+		ORDER BY ch.role DESC, ch.created_at`, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hosts []entity.ConferenceHost
+	for rows.Next() {
+		var host entity.ConferenceHost
+		if err = rows.Scan(&host.ConferenceID, &host.UserID, &host.Role, &host.CreatedAt,
+			&host.User.Name, &host.User.Role, &host.User.Bio); err != nil {
+			return nil, err
+		}
+		host.User.ID = host.UserID
+		hosts = append(hosts, host)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return hosts, nil
+}
+
+func (r *conferenceRepository) GetConferenceHost(ctx context.Context, conferenceID,
+	userID uuid.UUID) (*entity.ConferenceHost, error) {
+
+	var host entity.ConferenceHost
+
+	err := r.db.GetContext(ctx, &host, `
+		SELECT conference_id, user_id, role, created_at
+		FROM conference_hosts
+		WHERE conference_id = $1
+		AND user_id = $2`, conferenceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &host, nil
+}
+
+func (r *conferenceRepository) AddConferenceHost(ctx context.Context, host *entity.ConferenceHost) error {
+	// Only regular users can host, so staff and deleted accounts are treated as not found
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO conference_hosts (conference_id, user_id, role)
+		SELECT $1, id, $3
+		FROM users
+		WHERE id = $2
+		AND role = 'user'
+		AND deleted_at IS NULL`, host.ConferenceID, host.UserID, host.Role)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *conferenceRepository) RemoveConferenceHost(ctx context.Context, conferenceID, userID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `
+		DELETE FROM conference_hosts
+		WHERE conference_id = $1
+		AND user_id = $2
+		AND role = 'co_host'`, conferenceID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *conferenceRepository) CreateOwnershipTransfer(ctx context.Context,
+	transfer *entity.OwnershipTransfer) error {
+
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO conference_ownership_transfers (id, conference_id, from_user_id, to_user_id)
+		VALUES (:id, :conference_id, :from_user_id, :to_user_id)`, transfer)
+	return err
+}
+
+func (r *conferenceRepository) GetOwnershipTransferByID(ctx context.Context,
+	id uuid.UUID) (*entity.OwnershipTransfer, error) {
+
+	var transfer entity.OwnershipTransfer
+
+	err := r.db.GetContext(ctx, &transfer, `
+		SELECT id, conference_id, from_user_id, to_user_id, status, created_at, responded_at
+		FROM conference_ownership_transfers
+		WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+func (r *conferenceRepository) GetPendingOwnershipTransfersByUser(ctx context.Context,
+	userID uuid.UUID) ([]entity.OwnershipTransfer, error) {
+
+	var transfers []entity.OwnershipTransfer
+
+	err := r.db.SelectContext(ctx, &transfers, `
+		SELECT id, conference_id, from_user_id, to_user_id, status, created_at, responded_at
+		FROM conference_ownership_transfers
+		WHERE to_user_id = $1
+		AND status = 'pending'
+		ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return transfers, nil
+}
+
+func (r *conferenceRepository) updateOwnershipTransferStatus(ctx context.Context, tx sqlx.ExtContext, id uuid.UUID,
+	status enum.OwnershipTransferStatus) error {
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE conference_ownership_transfers
+		SET status = $1,
+			responded_at = now()
+		WHERE id = $2
+		AND status = 'pending'`, status, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *conferenceRepository) UpdateOwnershipTransferStatus(ctx context.Context, id uuid.UUID,
+	status enum.OwnershipTransferStatus) error {
+
+	return r.updateOwnershipTransferStatus(ctx, r.db, id, status)
+}
+
+func (r *conferenceRepository) AcceptOwnershipTransfer(ctx context.Context, transfer *entity.OwnershipTransfer) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = r.updateOwnershipTransferStatus(ctx, tx, transfer.ID, enum.OwnershipTransferAccepted); err != nil {
+		return err
+	}
+
+	// The previous owner must still own the conference, and the new owner must still be a co-host
+	res, err := tx.ExecContext(ctx, `
+		UPDATE conferences
+		SET host_id = $1,
+			updated_at = now()
+		WHERE id = $2
+		AND host_id = $3
+		AND deleted_at IS NULL
+		AND EXISTS (
+			SELECT 1 FROM conference_hosts
+			WHERE conference_id = $2
+			AND user_id = $1
+		)`, transfer.ToUserID, transfer.ConferenceID, transfer.FromUserID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	// Demote first, the unique owner index would reject two owners otherwise
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE conference_hosts
+		SET role = 'co_host'
+		WHERE conference_id = $1
+		AND user_id = $2`, transfer.ConferenceID, transfer.FromUserID); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE conference_hosts
+		SET role = 'owner'
+		WHERE conference_id = $1
+		AND user_id = $2`, transfer.ConferenceID, transfer.ToUserID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -8,24 +8,37 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
+	"github.com/nathakusuma/auditorium-reservation-backend/internal/infra/env"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/log"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/mail"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/uuidpkg"
 )
 
 type conferenceService struct {
-	r    contract.IConferenceRepository
-	uuid uuidpkg.IUUID
+	r                contract.IConferenceRepository
+	registrationRepo contract.IRegistrationRepository
+	roomSvc          contract.IRoomService
+	mailer           mail.IMailer
+	uuid             uuidpkg.IUUID
 }
 
 func NewConferenceService(conferenceRepo contract.IConferenceRepository,
+	registrationRepo contract.IRegistrationRepository, roomSvc contract.IRoomService, mailer mail.IMailer,
 	uuid uuidpkg.IUUID) contract.IConferenceService {
 
-	return &conferenceService{r: conferenceRepo, uuid: uuid}
+	return &conferenceService{
+		r:                conferenceRepo,
+		registrationRepo: registrationRepo,
+		roomSvc:          roomSvc,
+		mailer:           mailer,
+		uuid:             uuid,
+	}
 }
 
 func (s *conferenceService) CreateConferenceProposal(ctx context.Context,
@@ -42,54 +55,18 @@ func (s *conferenceService) CreateConferenceProposal(ctx context.Context,
 	}
 
 	// Check if user has active proposal
-	userConferences, _, err := s.GetConferences(ctx, &dto.GetConferenceQuery{
-		Limit:       1,
-		HostID:      &requesterID,
-		Status:      enum.ConferencePending,
-		IncludePast: false,
-		OrderBy:     "created_at",
-		Order:       "desc",
-	})
-	if err != nil {
+	if err := s.checkActiveProposal(ctx, requesterID); err != nil {
 		return uuid.Nil, err
 	}
 
-	if len(userConferences) > 0 {
-		conflict := userConferences[0]
-		return uuid.Nil, errorpkg.ErrUserHasActiveProposal.WithDetail(map[string]interface{}{
-			"conference": dto.ConferenceResponse{
-				ID:        conflict.ID,
-				Title:     conflict.Title,
-				Status:    conflict.Status,
-				CreatedAt: conflict.CreatedAt,
-			}})
-	}
-
-	// Check if there is a conference in the same time window
-	conflicts, err := s.r.GetConferencesConflictingWithTime(ctx, req.StartsAt, req.EndsAt, uuid.Nil)
-	if err != nil {
-		traceID := log.ErrorWithTraceID(map[string]interface{}{
-			"error":        err,
-			"request":      req,
-			"requester.id": requesterID,
-		}, "[ConferenceService][CreateConferenceProposal] Failed to get conflicting conferences")
-		return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	// Check if the room can hold the requested seats
+	if err := s.checkRoomCapacity(ctx, req.RoomID, req.Seats); err != nil {
+		return uuid.Nil, err
 	}
 
-	if len(conflicts) > 0 {
-		resp := make([]dto.ConferenceResponse, len(conflicts))
-		for i, conflict := range conflicts {
-			resp[i] = dto.ConferenceResponse{
-				ID:       conflict.ID,
-				Title:    conflict.Description,
-				StartsAt: &conflict.StartsAt,
-				EndsAt:   &conflict.EndsAt,
-			}
-		}
-
-		return uuid.Nil, errorpkg.ErrTimeWindowConflict.WithDetail(map[string]interface{}{
-			"conferences": resp,
-		})
+	// Check if there is a conference in the same room and time window
+	if err := s.checkRoomConflicts(ctx, req.RoomID, req.StartsAt, req.EndsAt, uuid.Nil); err != nil {
+		return uuid.Nil, err
 	}
 
 	// Create conference
@@ -123,6 +100,7 @@ func (s *conferenceService) CreateConferenceProposal(ctx context.Context,
 		StartsAt:       req.StartsAt,
 		EndsAt:         req.EndsAt,
 		HostID:         requesterID,
+		RoomID:         req.RoomID,
 		Status:         enum.ConferencePending,
 	}
 
@@ -143,6 +121,36 @@ func (s *conferenceService) CreateConferenceProposal(ctx context.Context,
 	return conferenceID, nil
 }
 
+func (s *conferenceService) checkActiveProposal(ctx context.Context, requesterID uuid.UUID) error {
+	// A proposal sent back for revision is still active until it's resubmitted and decided
+	for _, status := range []enum.ConferenceStatus{enum.ConferencePending, enum.ConferenceNeedsRevision} {
+		userConferences, _, err := s.GetConferences(ctx, &dto.GetConferenceQuery{
+			Limit:       1,
+			HostID:      &requesterID,
+			Status:      status,
+			IncludePast: false,
+			OrderBy:     "created_at",
+			Order:       "desc",
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(userConferences) > 0 {
+			conflict := userConferences[0]
+			return errorpkg.ErrUserHasActiveProposal.WithDetail(map[string]interface{}{
+				"conference": dto.ConferenceResponse{
+					ID:        conflict.ID,
+					Title:     conflict.Title,
+					Status:    conflict.Status,
+					CreatedAt: conflict.CreatedAt,
+				}})
+		}
+	}
+
+	return nil
+}
+
 func (s *conferenceService) GetConferenceByID(ctx context.Context, id uuid.UUID) (*dto.ConferenceResponse, error) {
 	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
 	requesterRole, _ := ctx.Value("user.role").(enum.UserRole)
@@ -160,15 +168,58 @@ func (s *conferenceService) GetConferenceByID(ctx context.Context, id uuid.UUID)
 		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
 	}
 
-	isRestrictedUser := requesterRole == enum.RoleUser && conference.HostID != requesterID
+	isHost, err := s.isConferenceHost(ctx, conference, requesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	isRestrictedUser := requesterRole == enum.RoleUser && !isHost
 
-	if conference.Status != enum.ConferenceApproved && isRestrictedUser {
+	// Cancelled conferences stay visible so attendees can see what happened
+	isPublic := conference.Status == enum.ConferenceApproved || conference.Status == enum.ConferenceCancelled
+
+	if !isPublic && isRestrictedUser {
 		return nil, errorpkg.ErrForbiddenUser
 	}
 
 	var resp dto.ConferenceResponse
 	resp.PopulateFromEntity(conference)
 
+	hosts, err := s.r.GetConferenceHosts(ctx, id)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"requester.id": requesterID,
+		}, "[ConferenceService][GetConferenceByID] Failed to get conference hosts")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	resp.Hosts = make([]dto.ConferenceHostResponse, len(hosts))
+	for i := range hosts {
+		resp.Hosts[i].PopulateFromEntity(&hosts[i])
+	}
+
+	if err = s.populateRatings(ctx, []*dto.ConferenceResponse{&resp}); err != nil {
+		return nil, err
+	}
+
+	// The review thread is only visible to the host and staff
+	if !isRestrictedUser {
+		reviews, err2 := s.r.GetConferenceReviews(ctx, id)
+		if err2 != nil {
+			traceID := log.ErrorWithTraceID(map[string]interface{}{
+				"error":        err2.Error(),
+				"requester.id": requesterID,
+			}, "[ConferenceService][GetConferenceByID] Failed to get conference reviews")
+			return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+		}
+
+		resp.Reviews = make([]dto.ConferenceReviewResponse, len(reviews))
+		for i := range reviews {
+			resp.Reviews[i].PopulateFromEntity(&reviews[i])
+		}
+	}
+
 	return &resp, nil
 }
 
@@ -182,8 +233,10 @@ func (s *conferenceService) GetConferences(ctx context.Context,
 	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
 	requesterRole, _ := ctx.Value("user.role").(enum.UserRole)
 
+	isPublic := query.Status == enum.ConferenceApproved || query.Status == enum.ConferenceCancelled
+
 	// If requester is system, it will not enter this block because requesterRole is empty
-	if query.Status != enum.ConferenceApproved && requesterRole == enum.RoleUser {
+	if !isPublic && requesterRole == enum.RoleUser {
 		if query.HostID == nil {
 			query.HostID = &requesterID
 		} else if *query.HostID != requesterID {
@@ -201,13 +254,49 @@ func (s *conferenceService) GetConferences(ctx context.Context,
 	}
 
 	resp := make([]dto.ConferenceResponse, len(conferences))
+	refs := make([]*dto.ConferenceResponse, len(conferences))
 	for i, conference := range conferences {
 		resp[i].PopulateFromEntity(&conference)
+		refs[i] = &resp[i]
+	}
+
+	if err = s.populateRatings(ctx, refs); err != nil {
+		return nil, dto.LazyLoadResponse{}, err
 	}
 
 	return resp, lazy, nil
 }
 
+// populateRatings fills the rating summary of the conferences from the precomputed totals, in a single query
+func (s *conferenceService) populateRatings(ctx context.Context, conferences []*dto.ConferenceResponse) error {
+	ids := make([]uuid.UUID, len(conferences))
+	for i, conference := range conferences {
+		ids[i] = conference.ID
+	}
+
+	stats, err := s.r.GetConferenceRatingStats(ctx, ids)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":          err.Error(),
+			"conference.ids": ids,
+		}, "[ConferenceService][populateRatings] Failed to get conference rating stats")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	byConference := make(map[uuid.UUID]*entity.ConferenceRatingStats, len(stats))
+	for i := range stats {
+		byConference[stats[i].ConferenceID] = &stats[i]
+	}
+
+	for _, conference := range conferences {
+		if stat, ok := byConference[conference.ID]; ok {
+			conference.Rating = new(dto.ConferenceRatingResponse).PopulateFromEntity(stat)
+		}
+	}
+
+	return nil
+}
+
 func (s *conferenceService) UpdateConference(ctx context.Context, id uuid.UUID, req dto.UpdateConferenceRequest) error {
 	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
 	requesterRole, _ := ctx.Value("user.role").(enum.UserRole)
@@ -228,20 +317,33 @@ func (s *conferenceService) UpdateConference(ctx context.Context, id uuid.UUID,
 	conference := *original
 	req.GenerateUpdateEntity(&conference)
 
-	// Check if user is the host
-	if requesterRole == enum.RoleUser && conference.HostID != requesterID {
-		return errorpkg.ErrForbiddenUser
+	// Check if user is the host or a co-host
+	if requesterRole == enum.RoleUser {
+		isHost, err := s.isConferenceHost(ctx, original, requesterID)
+		if err != nil {
+			return err
+		}
+
+		if !isHost {
+			return errorpkg.ErrForbiddenUser
+		}
 	}
 
 	if original.EndsAt.Before(time.Now()) {
 		return errorpkg.ErrUpdatePastConference
 	}
 
-	if original.Status != enum.ConferencePending {
+	if original.Status != enum.ConferencePending && original.Status != enum.ConferenceNeedsRevision {
 		return errorpkg.ErrUpdateNotPendingConference
 	}
 
-	// Check if there is a conference in the same time window
+	// Check if the new room can hold the conference seats
+	if req.RoomID != nil {
+		if err = s.checkRoomCapacity(ctx, *req.RoomID, conference.Seats); err != nil {
+			return err
+		}
+	}
+
 	if req.StartsAt != nil || req.EndsAt != nil {
 		if conference.StartsAt.Before(time.Now()) {
 			return errorpkg.ErrTimeAlreadyPassed
@@ -250,31 +352,13 @@ func (s *conferenceService) UpdateConference(ctx context.Context, id uuid.UUID,
 		if conference.EndsAt.Before(conference.StartsAt) {
 			return errorpkg.ErrEndTimeBeforeStart
 		}
+	}
 
-		// Check if there is a conference in the same time window
-		conflicts, err := s.r.GetConferencesConflictingWithTime(ctx, *req.StartsAt, *req.EndsAt, id)
-		if err != nil {
-			traceID := log.ErrorWithTraceID(map[string]interface{}{
-				"error":        err,
-				"requester.id": requesterID,
-			}, "[ConferenceService][UpdateConference] Failed to get conflicting conferences")
-			return errorpkg.ErrInternalServer.WithTraceID(traceID)
-		}
-
-		if len(conflicts) > 0 {
-			resp := make([]dto.ConferenceResponse, len(conflicts))
-			for i, conflict := range conflicts {
-				resp[i] = dto.ConferenceResponse{
-					ID:       conflict.ID,
-					Title:    conflict.Description,
-					StartsAt: &conflict.StartsAt,
-					EndsAt:   &conflict.EndsAt,
-				}
-			}
-
-			return errorpkg.ErrTimeWindowConflict.WithDetail(map[string]interface{}{
-				"conferences": resp,
-			})
+	if req.StartsAt != nil || req.EndsAt != nil || req.RoomID != nil {
+		// Check if there is a conference in the same room and time window
+		if err = s.checkRoomConflicts(ctx, conference.RoomID, conference.StartsAt, conference.EndsAt,
+			id); err != nil {
+			return err
 		}
 	}
 
@@ -312,8 +396,9 @@ func (s *conferenceService) DeleteConference(ctx context.Context, id uuid.UUID)
 		return errorpkg.ErrInternalServer.WithTraceID(traceID)
 	}
 
+	// Deleting is owner-only on purpose, co-hosts can edit but not throw the conference away
 	if requesterRole == enum.RoleUser && conference.HostID != requesterID {
-		return errorpkg.ErrForbiddenUser
+		return s.ownerOnlyError(ctx, conference, requesterID)
 	}
 
 	if err = s.r.DeleteConference(ctx, id); err != nil {
@@ -333,7 +418,11 @@ func (s *conferenceService) DeleteConference(ctx context.Context, id uuid.UUID)
 }
 
 func (s *conferenceService) UpdateConferenceStatus(ctx context.Context, id uuid.UUID,
-	status enum.ConferenceStatus) error {
+	status enum.ConferenceStatus, comment *string) error {
+
+	if isReviewCommentRequired(status) && comment == nil {
+		return errorpkg.ErrReviewCommentRequired
+	}
 
 	conference, err := s.r.GetConferenceByID(ctx, id)
 	if err != nil {
@@ -358,37 +447,30 @@ func (s *conferenceService) UpdateConferenceStatus(ctx context.Context, id uuid.
 	}
 
 	if status == enum.ConferenceApproved {
-		// Check for time conflicts only when approving
-		conflicts, err2 := s.r.GetConferencesConflictingWithTime(ctx, conference.StartsAt, conference.EndsAt, id)
-		if err2 != nil {
-			traceID := log.ErrorWithTraceID(map[string]interface{}{
-				"error":        err2,
-				"requester.id": ctx.Value("user.id"),
-			}, "[ConferenceService][UpdateConferenceStatus] Failed to get conflicting conferences")
-			return errorpkg.ErrInternalServer.WithTraceID(traceID)
+		// The room may have been shrunk or removed since the proposal was made
+		if err = s.checkRoomCapacity(ctx, conference.RoomID, conference.Seats); err != nil {
+			return err
 		}
 
-		if len(conflicts) > 0 {
-			resp := make([]dto.ConferenceResponse, len(conflicts))
-			for i, conflict := range conflicts {
-				resp[i] = dto.ConferenceResponse{
-					ID:       conflict.ID,
-					Title:    conflict.Description,
-					StartsAt: &conflict.StartsAt,
-					EndsAt:   &conflict.EndsAt,
-				}
-			}
-
-			return errorpkg.ErrTimeWindowConflict.WithDetail(map[string]interface{}{
-				"conferences": resp,
-			})
+		// Check for time conflicts only when approving
+		if err = s.checkRoomConflicts(ctx, conference.RoomID, conference.StartsAt, conference.EndsAt,
+			id); err != nil {
+			return err
 		}
 	}
 
-	// update conference status
-	conference.Status = status
+	// update conference status and record the review
+	review, err := s.newConferenceReview(ctx, id, status, comment)
+	if err != nil {
+		return err
+	}
+
+	if err = s.r.UpdateConferencesStatus(ctx, enum.ConferencePending,
+		[]entity.ConferenceReview{*review}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrUpdateNotPendingConference
+		}
 
-	if err = s.r.UpdateConference(ctx, conference); err != nil {
 		traceID := log.ErrorWithTraceID(map[string]interface{}{
 			"error":        err,
 			"requester.id": ctx.Value("user.id"),
@@ -398,8 +480,1271 @@ func (s *conferenceService) UpdateConferenceStatus(ctx context.Context, id uuid.
 
 	log.Info(map[string]interface{}{
 		"conference":   conference,
+		"review":       review,
 		"requester.id": ctx.Value("user.id"),
 	}, fmt.Sprintf("[ConferenceService][UpdateConferenceStatus] Conference status updated to %s", status))
 
 	return nil
 }
+
+func (s *conferenceService) ResubmitConference(ctx context.Context, id uuid.UUID, comment *string) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	conference, err := s.r.GetConferenceByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"requester.id": requesterID,
+		}, "[ConferenceService][ResubmitConference] Failed to get conference")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	isHost, err := s.isConferenceHost(ctx, conference, requesterID)
+	if err != nil {
+		return err
+	}
+
+	if !isHost {
+		return errorpkg.ErrForbiddenUser
+	}
+
+	if conference.Status != enum.ConferenceNeedsRevision {
+		return errorpkg.ErrConferenceNotInRevision
+	}
+
+	if conference.StartsAt.Before(time.Now()) {
+		return errorpkg.ErrUpdatePastConference
+	}
+
+	// The room may have been shrunk or removed while the proposal was out for revision
+	if err = s.checkRoomCapacity(ctx, conference.RoomID, conference.Seats); err != nil {
+		return err
+	}
+
+	// move the proposal back to pending and record the resubmission in the review thread
+	review, err := s.newConferenceReview(ctx, id, enum.ConferencePending, comment)
+	if err != nil {
+		return err
+	}
+
+	if err = s.r.UpdateConferencesStatus(ctx, enum.ConferenceNeedsRevision,
+		[]entity.ConferenceReview{*review}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrConferenceNotInRevision
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"requester.id": requesterID,
+		}, "[ConferenceService][ResubmitConference] Failed to resubmit conference")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"conference.id": id,
+		"review":        review,
+		"requester.id":  requesterID,
+	}, "[ConferenceService][ResubmitConference] Conference resubmitted for review")
+
+	return nil
+}
+
+func (s *conferenceService) CancelConference(ctx context.Context, id uuid.UUID, reason string) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+	requesterRole, _ := ctx.Value("user.role").(enum.UserRole)
+
+	conference, err := s.r.GetConferenceByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"requester.id": requesterID,
+		}, "[ConferenceService][CancelConference] Failed to get conference")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	// Cancelling is owner-only on purpose, since it notifies every attendee and can't be undone
+	if requesterRole == enum.RoleUser && conference.HostID != requesterID {
+		return s.ownerOnlyError(ctx, conference, requesterID)
+	}
+
+	if conference.Status == enum.ConferenceCancelled {
+		return errorpkg.ErrConferenceCancelled
+	}
+
+	if conference.Status != enum.ConferenceApproved {
+		return errorpkg.ErrCancelNotApprovedConference
+	}
+
+	if conference.EndsAt.Before(time.Now()) {
+		return errorpkg.ErrUpdatePastConference
+	}
+
+	// Get attendees before cancelling, so they can be notified afterwards
+	attendees, err := s.r.GetRegisteredUsersByConference(ctx, id)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"requester.id": requesterID,
+		}, "[ConferenceService][CancelConference] Failed to get registered users")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if err = s.r.CancelConference(ctx, id, reason, requesterID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrCancelNotApprovedConference
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"requester.id": requesterID,
+		}, "[ConferenceService][CancelConference] Failed to cancel conference")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"conference.id": id,
+		"reason":        reason,
+		"attendees":     len(attendees),
+		"requester.id":  requesterID,
+	}, "[ConferenceService][CancelConference] Conference cancelled")
+
+	for _, attendee := range attendees {
+		go func(email, name string) {
+			err := s.mailer.Send(
+				email,
+				"[Auditorium Reservation] Conference Cancelled",
+				"conference_cancelled.html",
+				map[string]interface{}{
+					"name":       name,
+					"conference": conference.Title,
+					"starts_at":  conference.StartsAt.Format(time.RFC1123),
+					"reason":     reason,
+					"href":       env.GetEnv().FrontendURL + "/conferences",
+				})
+
+			if err != nil {
+				log.Error(map[string]interface{}{
+					"error":         err,
+					"conference.id": id,
+					"email":         email,
+				}, "[ConferenceService][CancelConference] Failed to send cancellation email")
+			}
+		}(attendee.Email, attendee.Name)
+	}
+
+	return nil
+}
+
+// findRoomConflicts returns the approved conferences in the room that overlap the time window
+func (s *conferenceService) findRoomConflicts(ctx context.Context, roomID uuid.UUID, startsAt, endsAt time.Time,
+	excludeID uuid.UUID) ([]dto.ConferenceResponse, error) {
+
+	conflicts, err := s.r.GetConferencesConflictingWithTime(ctx, roomID, startsAt, endsAt, excludeID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"roomID":       roomID,
+			"startsAt":     startsAt,
+			"endsAt":       endsAt,
+			"excludeID":    excludeID,
+			"requester.id": ctx.Value("user.id"),
+		}, "[ConferenceService][findRoomConflicts] Failed to get conflicting conferences")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	resp := make([]dto.ConferenceResponse, len(conflicts))
+	for i, conflict := range conflicts {
+		resp[i] = dto.ConferenceResponse{
+			ID:       conflict.ID,
+			Title:    conflict.Title,
+			StartsAt: &conflict.StartsAt,
+			EndsAt:   &conflict.EndsAt,
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *conferenceService) checkRoomConflicts(ctx context.Context, roomID uuid.UUID, startsAt, endsAt time.Time,
+	excludeID uuid.UUID) error {
+
+	conflicts, err := s.findRoomConflicts(ctx, roomID, startsAt, endsAt, excludeID)
+	if err != nil {
+		return err
+	}
+
+	if len(conflicts) > 0 {
+		return errorpkg.ErrTimeWindowConflict.WithDetail(map[string]interface{}{
+			"conferences": conflicts,
+		})
+	}
+
+	return nil
+}
+
+// checkRoomCapacity makes sure the room still exists and can hold the conference seats
+func (s *conferenceService) checkRoomCapacity(ctx context.Context, roomID uuid.UUID, seats int) error {
+	room, err := s.roomSvc.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return err
+	}
+
+	if seats > room.Capacity {
+		return errorpkg.ErrSeatsExceedRoomCapacity.WithDetail(map[string]interface{}{
+			"room": room,
+		})
+	}
+
+	return nil
+}
+
+func (s *conferenceService) RequestReschedule(ctx context.Context, id uuid.UUID,
+	req *dto.CreateRescheduleRequest) (uuid.UUID, error) {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	conference, err := s.r.GetConferenceByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"requester.id": requesterID,
+		}, "[ConferenceService][RequestReschedule] Failed to get conference")
+		return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	isHost, err := s.isConferenceHost(ctx, conference, requesterID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if !isHost {
+		return uuid.Nil, errorpkg.ErrForbiddenUser
+	}
+
+	if conference.Status != enum.ConferenceApproved || conference.StartsAt.Before(time.Now()) {
+		return uuid.Nil, errorpkg.ErrRescheduleNotApprovedConference
+	}
+
+	if req.StartsAt.Before(time.Now()) {
+		return uuid.Nil, errorpkg.ErrTimeAlreadyPassed
+	}
+
+	if req.EndsAt.Before(req.StartsAt) {
+		return uuid.Nil, errorpkg.ErrEndTimeBeforeStart
+	}
+
+	// Checked again on review, but there's no point in asking for a slot that's already taken
+	if err = s.checkRoomConflicts(ctx, conference.RoomID, req.StartsAt, req.EndsAt, id); err != nil {
+		return uuid.Nil, err
+	}
+
+	requestID, err := s.uuid.NewV7()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"requester.id": requesterID,
+		}, "[ConferenceService][RequestReschedule] Failed to generate reschedule request ID")
+		return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	request := entity.RescheduleRequest{
+		ID:           requestID,
+		ConferenceID: id,
+		RequestedBy:  requesterID,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		Reason:       req.Reason,
+	}
+
+	if err = s.r.CreateRescheduleRequest(ctx, &request); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "conference_reschedule_requests_pending_key" {
+			return uuid.Nil, errorpkg.ErrRescheduleAlreadyRequested
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"request":      request,
+			"requester.id": requesterID,
+		}, "[ConferenceService][RequestReschedule] Failed to create reschedule request")
+		return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"request":      request,
+		"requester.id": requesterID,
+	}, "[ConferenceService][RequestReschedule] Reschedule requested")
+
+	return requestID, nil
+}
+
+func (s *conferenceService) GetRescheduleRequests(ctx context.Context,
+	conferenceID uuid.UUID) ([]dto.RescheduleRequestResponse, error) {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+	requesterRole, _ := ctx.Value("user.role").(enum.UserRole)
+
+	conference, err := s.r.GetConferenceByID(ctx, conferenceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"requester.id": requesterID,
+		}, "[ConferenceService][GetRescheduleRequests] Failed to get conference")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if requesterRole == enum.RoleUser {
+		isHost, err := s.isConferenceHost(ctx, conference, requesterID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isHost {
+			return nil, errorpkg.ErrForbiddenUser
+		}
+	}
+
+	requests, err := s.r.GetRescheduleRequestsByConference(ctx, conferenceID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": conferenceID,
+			"requester.id":  requesterID,
+		}, "[ConferenceService][GetRescheduleRequests] Failed to get reschedule requests")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	resp := make([]dto.RescheduleRequestResponse, len(requests))
+	for i, request := range requests {
+		resp[i].PopulateFromEntity(&request)
+	}
+
+	return resp, nil
+}
+
+func (s *conferenceService) ReviewRescheduleRequest(ctx context.Context, requestID uuid.UUID,
+	status enum.RescheduleStatus, comment *string) ([]dto.UserResponse, error) {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	request, err := s.r.GetRescheduleRequestByID(ctx, requestID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"request.id":   requestID,
+			"requester.id": requesterID,
+		}, "[ConferenceService][ReviewRescheduleRequest] Failed to get reschedule request")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if request.Status != enum.ReschedulePending {
+		return nil, errorpkg.ErrRescheduleNotPending
+	}
+
+	if status == enum.RescheduleRejected && comment == nil {
+		return nil, errorpkg.ErrReviewCommentRequired
+	}
+
+	request.Status = status
+	request.ReviewedBy = &requesterID
+	request.ReviewComment = comment
+
+	if status == enum.RescheduleRejected {
+		if err = s.r.RejectRescheduleRequest(ctx, request); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, errorpkg.ErrRescheduleNotPending
+			}
+
+			traceID := log.ErrorWithTraceID(map[string]interface{}{
+				"error":        err,
+				"request":      request,
+				"requester.id": requesterID,
+			}, "[ConferenceService][ReviewRescheduleRequest] Failed to reject reschedule request")
+			return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+		}
+
+		log.Info(map[string]interface{}{
+			"request":      request,
+			"requester.id": requesterID,
+		}, "[ConferenceService][ReviewRescheduleRequest] Reschedule request rejected")
+
+		return nil, nil
+	}
+
+	conference, err := s.r.GetConferenceByID(ctx, request.ConferenceID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"request":      request,
+			"requester.id": requesterID,
+		}, "[ConferenceService][ReviewRescheduleRequest] Failed to get conference")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if conference.Status != enum.ConferenceApproved || conference.StartsAt.Before(time.Now()) {
+		return nil, errorpkg.ErrRescheduleNotApprovedConference
+	}
+
+	if request.StartsAt.Before(time.Now()) {
+		return nil, errorpkg.ErrTimeAlreadyPassed
+	}
+
+	// Another conference may have taken the room since the request was made
+	if err = s.checkRoomConflicts(ctx, conference.RoomID, request.StartsAt, request.EndsAt,
+		conference.ID); err != nil {
+		return nil, err
+	}
+
+	attendees, err := s.r.GetRegisteredUsersByConference(ctx, conference.ID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"request":      request,
+			"requester.id": requesterID,
+		}, "[ConferenceService][ReviewRescheduleRequest] Failed to get registered users")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	// Attendee conflicts don't block the reschedule. They're reported to the coordinator, and the attendees
+	// can't reconfirm until they resolve them.
+	conflicted := make(map[uuid.UUID]bool)
+	conflictedResp := make([]dto.UserResponse, 0)
+	for _, attendee := range attendees {
+		conflicts, err2 := s.registrationRepo.GetConflictingRegistrations(ctx, attendee.ID, request.StartsAt,
+			request.EndsAt, conference.ID)
+		if err2 != nil {
+			traceID := log.ErrorWithTraceID(map[string]interface{}{
+				"error":        err2,
+				"request":      request,
+				"attendee.id":  attendee.ID,
+				"requester.id": requesterID,
+			}, "[ConferenceService][ReviewRescheduleRequest] Failed to get conflicting registrations")
+			return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+		}
+
+		if len(conflicts) > 0 {
+			conflicted[attendee.ID] = true
+			conflictedResp = append(conflictedResp, dto.UserResponse{
+				ID:    attendee.ID,
+				Name:  attendee.Name,
+				Email: attendee.Email,
+			})
+		}
+	}
+
+	// Attendees get the reconfirmation window, but never past the new start
+	reconfirmBy := time.Now().Add(env.GetEnv().RescheduleReconfirmWindow)
+	if request.StartsAt.Before(reconfirmBy) {
+		reconfirmBy = request.StartsAt
+	}
+
+	if err = s.r.ApproveRescheduleRequest(ctx, request, reconfirmBy); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorpkg.ErrRescheduleNotApprovedConference
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"request":      request,
+			"requester.id": requesterID,
+		}, "[ConferenceService][ReviewRescheduleRequest] Failed to approve reschedule request")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"request":      request,
+		"reconfirmBy":  reconfirmBy,
+		"attendees":    len(attendees),
+		"conflicted":   len(conflictedResp),
+		"requester.id": requesterID,
+	}, "[ConferenceService][ReviewRescheduleRequest] Conference rescheduled")
+
+	for _, attendee := range attendees {
+		go func(email, name string, hasConflict bool) {
+			err := s.mailer.Send(
+				email,
+				"[Auditorium Reservation] Conference Rescheduled",
+				"conference_rescheduled.html",
+				map[string]interface{}{
+					"name":         name,
+					"conference":   conference.Title,
+					"starts_at":    request.StartsAt.Format(time.RFC1123),
+					"ends_at":      request.EndsAt.Format(time.RFC1123),
+					"reconfirm_by": reconfirmBy.Format(time.RFC1123),
+					"has_conflict": hasConflict,
+					"href":         env.GetEnv().FrontendURL + "/conferences/" + conference.ID.String(),
+				})
+
+			if err != nil {
+				log.Error(map[string]interface{}{
+					"error":         err,
+					"conference.id": conference.ID,
+					"email":         email,
+				}, "[ConferenceService][ReviewRescheduleRequest] Failed to send reschedule email")
+			}
+		}(attendee.Email, attendee.Name, conflicted[attendee.ID])
+	}
+
+	return conflictedResp, nil
+}
+
+func isReviewCommentRequired(status enum.ConferenceStatus) bool {
+	return status == enum.ConferenceRejected || status == enum.ConferenceNeedsRevision
+}
+
+func (s *conferenceService) newConferenceReview(ctx context.Context, conferenceID uuid.UUID,
+	status enum.ConferenceStatus, comment *string) (*entity.ConferenceReview, error) {
+
+	reviewerID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	reviewID, err := s.uuid.NewV7()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": conferenceID,
+			"requester.id":  reviewerID,
+		}, "[ConferenceService][newConferenceReview] Failed to generate review ID")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	return &entity.ConferenceReview{
+		ID:           reviewID,
+		ConferenceID: conferenceID,
+		ReviewerID:   reviewerID,
+		Status:       status,
+		Comment:      comment,
+	}, nil
+}
+
+// maxSeriesOccurrences caps how many conferences a single series can expand into.
+const maxSeriesOccurrences = 52
+
+func (s *conferenceService) CreateConferenceSeries(ctx context.Context,
+	req *dto.CreateConferenceSeriesRequest) (uuid.UUID, error) {
+
+	requesterID, ok := ctx.Value("user.id").(uuid.UUID)
+	if !ok {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        errors.New("failed to get user id from context"),
+			"requester.id": requesterID,
+		}, "[ConferenceService][CreateConferenceSeries] Failed to get user id from context")
+		return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	// Check if user has active proposal
+	if err := s.checkActiveProposal(ctx, requesterID); err != nil {
+		return uuid.Nil, err
+	}
+
+	if req.StartsAt.Before(time.Now()) {
+		return uuid.Nil, errorpkg.ErrTimeAlreadyPassed
+	}
+
+	if req.EndsAt.Before(req.StartsAt) {
+		return uuid.Nil, errorpkg.ErrEndTimeBeforeStart
+	}
+
+	occurrences, err := expandOccurrences(req)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	// Check if the room can hold the requested seats
+	if err := s.checkRoomCapacity(ctx, req.RoomID, req.Seats); err != nil {
+		return uuid.Nil, err
+	}
+
+	// Check every occurrence for a conference in the same room and time window
+	duration := req.EndsAt.Sub(req.StartsAt)
+	var resp []dto.ConferenceResponse
+	for _, startsAt := range occurrences {
+		conflicts, err2 := s.findRoomConflicts(ctx, req.RoomID, startsAt, startsAt.Add(duration), uuid.Nil)
+		if err2 != nil {
+			return uuid.Nil, err2
+		}
+
+		resp = append(resp, conflicts...)
+	}
+
+	if len(resp) > 0 {
+		return uuid.Nil, errorpkg.ErrTimeWindowConflict.WithDetail(map[string]interface{}{
+			"conferences": resp,
+		})
+	}
+
+	// Create series with all of its occurrences
+	seriesID, err := s.uuid.NewV7()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"request":      req,
+			"requester.id": requesterID,
+		}, "[ConferenceService][CreateConferenceSeries] Failed to generate series ID")
+		return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	series := entity.ConferenceSeries{
+		ID:          seriesID,
+		HostID:      requesterID,
+		Frequency:   req.Frequency,
+		RepeatUntil: req.RepeatUntil,
+		RepeatCount: req.RepeatCount,
+	}
+
+	conferences := make([]entity.Conference, len(occurrences))
+	for i, startsAt := range occurrences {
+		conferenceID, err2 := s.uuid.NewV7()
+		if err2 != nil {
+			traceID := log.ErrorWithTraceID(map[string]interface{}{
+				"error":        err2,
+				"request":      req,
+				"requester.id": requesterID,
+			}, "[ConferenceService][CreateConferenceSeries] Failed to generate conference ID")
+			return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+		}
+
+		conferences[i] = entity.Conference{
+			ID:             conferenceID,
+			Title:          req.Title,
+			Description:    req.Description,
+			SpeakerName:    req.SpeakerName,
+			SpeakerTitle:   req.SpeakerTitle,
+			TargetAudience: req.TargetAudience,
+			Prerequisites:  req.Prerequisites,
+			Seats:          req.Seats,
+			StartsAt:       startsAt,
+			EndsAt:         startsAt.Add(duration),
+			HostID:         requesterID,
+			RoomID:         req.RoomID,
+			SeriesID:       &seriesID,
+			Status:         enum.ConferencePending,
+		}
+	}
+
+	if err = s.r.CreateConferenceSeries(ctx, &series, conferences); err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"request":      req,
+			"requester.id": requesterID,
+		}, "[ConferenceService][CreateConferenceSeries] Failed to create conference series")
+		return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"series":       series,
+		"occurrences":  len(conferences),
+		"requester.id": requesterID,
+	}, "[ConferenceService][CreateConferenceSeries] Conference series proposal created")
+
+	return seriesID, nil
+}
+
+// expandOccurrences returns the start time of every occurrence described by the recurrence rule.
+func expandOccurrences(req *dto.CreateConferenceSeriesRequest) ([]time.Time, error) {
+	if (req.RepeatUntil == nil) == (req.RepeatCount == nil) {
+		return nil, errorpkg.ErrInvalidRecurrence
+	}
+
+	var occurrences []time.Time
+	for i := 0; len(occurrences) <= maxSeriesOccurrences; i++ {
+		var next time.Time
+		switch req.Frequency {
+		case enum.RecurrenceWeekly:
+			next = req.StartsAt.AddDate(0, 0, 7*i)
+		case enum.RecurrenceBiweekly:
+			next = req.StartsAt.AddDate(0, 0, 14*i)
+		case enum.RecurrenceMonthly:
+			next = req.StartsAt.AddDate(0, i, 0)
+			// Like RRULE, skip months that don't have the starting day (e.g. the 31st)
+			if next.Day() != req.StartsAt.Day() {
+				continue
+			}
+		default:
+			return nil, errorpkg.ErrInvalidRecurrence
+		}
+
+		if req.RepeatUntil != nil && next.After(*req.RepeatUntil) {
+			break
+		}
+		if req.RepeatCount != nil && len(occurrences) == *req.RepeatCount {
+			break
+		}
+
+		occurrences = append(occurrences, next)
+	}
+
+	if len(occurrences) > maxSeriesOccurrences {
+		return nil, errorpkg.ErrTooManyOccurrences.WithDetail(map[string]interface{}{
+			"max_occurrences": maxSeriesOccurrences,
+		})
+	}
+
+	if len(occurrences) < 2 {
+		return nil, errorpkg.ErrInvalidRecurrence
+	}
+
+	// Occurrences of the same series must not overlap each other
+	duration := req.EndsAt.Sub(req.StartsAt)
+	for i := 1; i < len(occurrences); i++ {
+		if occurrences[i-1].Add(duration).After(occurrences[i]) {
+			return nil, errorpkg.ErrInvalidRecurrence
+		}
+	}
+
+	return occurrences, nil
+}
+
+func (s *conferenceService) getConferenceSeries(ctx context.Context,
+	id uuid.UUID) (*entity.ConferenceSeries, []entity.Conference, error) {
+
+	series, err := s.r.GetConferenceSeriesByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"series.id":    id,
+			"requester.id": ctx.Value("user.id"),
+		}, "[ConferenceService][getConferenceSeries] Failed to get conference series")
+		return nil, nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	conferences, err := s.r.GetConferencesBySeriesID(ctx, id)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"series.id":    id,
+			"requester.id": ctx.Value("user.id"),
+		}, "[ConferenceService][getConferenceSeries] Failed to get series conferences")
+		return nil, nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	return series, conferences, nil
+}
+
+func (s *conferenceService) GetConferenceSeriesByID(ctx context.Context,
+	id uuid.UUID) (*dto.ConferenceSeriesResponse, error) {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+	requesterRole, _ := ctx.Value("user.role").(enum.UserRole)
+
+	series, conferences, err := s.getConferenceSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Regular users only see approved occurrences of other users' series
+	if requesterRole == enum.RoleUser && series.HostID != requesterID {
+		approved := make([]entity.Conference, 0, len(conferences))
+		for _, conference := range conferences {
+			if conference.Status == enum.ConferenceApproved {
+				approved = append(approved, conference)
+			}
+		}
+
+		if len(approved) == 0 {
+			return nil, errorpkg.ErrForbiddenUser
+		}
+		conferences = approved
+	}
+
+	return new(dto.ConferenceSeriesResponse).PopulateFromEntity(series, conferences), nil
+}
+
+func (s *conferenceService) UpdateConferenceSeriesStatus(ctx context.Context, id uuid.UUID,
+	status enum.ConferenceStatus, comment *string) error {
+
+	if isReviewCommentRequired(status) && comment == nil {
+		return errorpkg.ErrReviewCommentRequired
+	}
+
+	_, conferences, err := s.getConferenceSeries(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Only pending occurrences follow the series decision. Past ones can only be rejected.
+	var targets []entity.Conference
+	for _, conference := range conferences {
+		if conference.Status != enum.ConferencePending {
+			continue
+		}
+		if conference.StartsAt.Before(time.Now()) && status != enum.ConferenceRejected {
+			continue
+		}
+		targets = append(targets, conference)
+	}
+
+	if len(targets) == 0 {
+		return errorpkg.ErrUpdateNotPendingConference
+	}
+
+	if status == enum.ConferenceApproved {
+		// Occurrences can be moved to another room one by one, so check each room
+		for _, conference := range targets {
+			if err = s.checkRoomCapacity(ctx, conference.RoomID, conference.Seats); err != nil {
+				return err
+			}
+		}
+
+		// Check for time conflicts of every occurrence only when approving
+		var resp []dto.ConferenceResponse
+		for _, conference := range targets {
+			conflicts, err2 := s.findRoomConflicts(ctx, conference.RoomID, conference.StartsAt,
+				conference.EndsAt, conference.ID)
+			if err2 != nil {
+				return err2
+			}
+
+			resp = append(resp, conflicts...)
+		}
+
+		if len(resp) > 0 {
+			return errorpkg.ErrTimeWindowConflict.WithDetail(map[string]interface{}{
+				"conferences": resp,
+			})
+		}
+	}
+
+	ids := make([]uuid.UUID, len(targets))
+	reviews := make([]entity.ConferenceReview, len(targets))
+	for i, conference := range targets {
+		review, err2 := s.newConferenceReview(ctx, conference.ID, status, comment)
+		if err2 != nil {
+			return err2
+		}
+
+		ids[i] = conference.ID
+		reviews[i] = *review
+	}
+
+	// update every targeted occurrence at once
+	if err = s.r.UpdateConferencesStatus(ctx, enum.ConferencePending, reviews); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrUpdateNotPendingConference
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"series.id":    id,
+			"requester.id": ctx.Value("user.id"),
+		}, fmt.Sprintf("[ConferenceService][UpdateConferenceSeriesStatus] Failed to update series status to %s",
+			status))
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"series.id":      id,
+		"conference.ids": ids,
+		"requester.id":   ctx.Value("user.id"),
+	}, fmt.Sprintf("[ConferenceService][UpdateConferenceSeriesStatus] Series status updated to %s", status))
+
+	return nil
+}
+
+func (s *conferenceService) getConference(ctx context.Context, id uuid.UUID) (*entity.Conference, error) {
+	conference, err := s.r.GetConferenceByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": id,
+			"requester.id":  ctx.Value("user.id"),
+		}, "[ConferenceService][getConference] Failed to get conference")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	return conference, nil
+}
+
+func (s *conferenceService) isConferenceHost(ctx context.Context, conference *entity.Conference,
+	userID uuid.UUID) (bool, error) {
+
+	// The owner is always on the conference itself, no need to look further
+	if conference.HostID == userID {
+		return true, nil
+	}
+
+	return s.IsConferenceHost(ctx, conference.ID, userID)
+}
+
+// ownerOnlyError tells co-hosts that an action is reserved for the owner, and everyone else that it's forbidden
+func (s *conferenceService) ownerOnlyError(ctx context.Context, conference *entity.Conference,
+	userID uuid.UUID) error {
+
+	isHost, err := s.isConferenceHost(ctx, conference, userID)
+	if err != nil {
+		return err
+	}
+
+	if isHost {
+		return errorpkg.ErrOwnerOnly
+	}
+
+	return errorpkg.ErrForbiddenUser
+}
+
+func (s *conferenceService) IsConferenceHost(ctx context.Context, conferenceID, userID uuid.UUID) (bool, error) {
+	if _, err := s.r.GetConferenceHost(ctx, conferenceID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": conferenceID,
+			"user.id":       userID,
+		}, "[ConferenceService][IsConferenceHost] Failed to get conference host")
+		return false, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	return true, nil
+}
+
+func (s *conferenceService) GetConferenceForStaff(ctx context.Context,
+	conferenceID uuid.UUID) (*dto.ConferenceResponse, error) {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+	requesterRole, _ := ctx.Value("user.role").(enum.UserRole)
+
+	conference, err := s.GetConferenceByID(ctx, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if requesterRole == enum.RoleUser && !conference.IsHostedBy(requesterID) {
+		return nil, errorpkg.ErrForbiddenUser
+	}
+
+	return conference, nil
+}
+
+func (s *conferenceService) GetConferenceHosts(ctx context.Context,
+	conferenceID uuid.UUID) ([]dto.ConferenceHostResponse, error) {
+
+	// Hosts come with the conference, which also applies its visibility rules
+	conference, err := s.GetConferenceByID(ctx, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return conference.Hosts, nil
+}
+
+func (s *conferenceService) AddCoHost(ctx context.Context, conferenceID, userID uuid.UUID) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	conference, err := s.getConference(ctx, conferenceID)
+	if err != nil {
+		return err
+	}
+
+	// Only the owner decides who hosts along
+	if conference.HostID != requesterID {
+		return errorpkg.ErrForbiddenUser
+	}
+
+	if conference.EndsAt.Before(time.Now()) {
+		return errorpkg.ErrUpdatePastConference
+	}
+
+	if conference.Status == enum.ConferenceCancelled {
+		return errorpkg.ErrConferenceCancelled
+	}
+
+	// Hosts can't register, so an attendee has to cancel first
+	isRegistered, err := s.registrationRepo.IsUserRegisteredToConference(ctx, conferenceID, userID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": conferenceID,
+			"user.id":       userID,
+			"requester.id":  requesterID,
+		}, "[ConferenceService][AddCoHost] Failed to check if user is registered to conference")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+	if isRegistered {
+		return errorpkg.ErrUserRegisteredAsAttendee
+	}
+
+	host := entity.ConferenceHost{
+		ConferenceID: conferenceID,
+		UserID:       userID,
+		Role:         enum.HostCoHost,
+	}
+
+	if err = s.r.AddConferenceHost(ctx, &host); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotFound
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "conference_hosts_pkey" {
+			return errorpkg.ErrAlreadyConferenceHost
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"host":         host,
+			"requester.id": requesterID,
+		}, "[ConferenceService][AddCoHost] Failed to add conference host")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"host":         host,
+		"requester.id": requesterID,
+	}, "[ConferenceService][AddCoHost] Co-host added")
+
+	return nil
+}
+
+func (s *conferenceService) RemoveCoHost(ctx context.Context, conferenceID, userID uuid.UUID) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	conference, err := s.getConference(ctx, conferenceID)
+	if err != nil {
+		return err
+	}
+
+	// The owner can remove anyone, co-hosts can only step down themselves
+	if conference.HostID != requesterID && userID != requesterID {
+		return errorpkg.ErrForbiddenUser
+	}
+
+	if conference.HostID == userID {
+		return errorpkg.ErrCannotRemoveOwner
+	}
+
+	if err = s.r.RemoveConferenceHost(ctx, conferenceID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": conferenceID,
+			"user.id":       userID,
+			"requester.id":  requesterID,
+		}, "[ConferenceService][RemoveCoHost] Failed to remove conference host")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"conference.id": conferenceID,
+		"user.id":       userID,
+		"requester.id":  requesterID,
+	}, "[ConferenceService][RemoveCoHost] Co-host removed")
+
+	return nil
+}
+
+func (s *conferenceService) TransferOwnership(ctx context.Context, conferenceID,
+	toUserID uuid.UUID) (uuid.UUID, error) {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	conference, err := s.getConference(ctx, conferenceID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if conference.HostID != requesterID {
+		return uuid.Nil, errorpkg.ErrForbiddenUser
+	}
+
+	if toUserID == requesterID {
+		return uuid.Nil, errorpkg.ErrInvalidOwnershipTransfer
+	}
+
+	isHost, err := s.IsConferenceHost(ctx, conferenceID, toUserID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if !isHost {
+		return uuid.Nil, errorpkg.ErrInvalidOwnershipTransfer
+	}
+
+	transferID, err := s.uuid.NewV7()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": conferenceID,
+			"requester.id":  requesterID,
+		}, "[ConferenceService][TransferOwnership] Failed to generate ownership transfer ID")
+		return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	transfer := entity.OwnershipTransfer{
+		ID:           transferID,
+		ConferenceID: conferenceID,
+		FromUserID:   requesterID,
+		ToUserID:     toUserID,
+	}
+
+	if err = s.r.CreateOwnershipTransfer(ctx, &transfer); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "conference_ownership_transfers_pending_key" {
+			return uuid.Nil, errorpkg.ErrOwnershipTransferAlreadyPending
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"transfer":     transfer,
+			"requester.id": requesterID,
+		}, "[ConferenceService][TransferOwnership] Failed to create ownership transfer")
+		return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"transfer":     transfer,
+		"requester.id": requesterID,
+	}, "[ConferenceService][TransferOwnership] Ownership transfer requested")
+
+	return transferID, nil
+}
+
+func (s *conferenceService) GetPendingOwnershipTransfers(ctx context.Context) ([]dto.OwnershipTransferResponse,
+	error) {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	transfers, err := s.r.GetPendingOwnershipTransfersByUser(ctx, requesterID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"requester.id": requesterID,
+		}, "[ConferenceService][GetPendingOwnershipTransfers] Failed to get ownership transfers")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	resp := make([]dto.OwnershipTransferResponse, len(transfers))
+	for i := range transfers {
+		resp[i].PopulateFromEntity(&transfers[i])
+	}
+
+	return resp, nil
+}
+
+func (s *conferenceService) getPendingOwnershipTransfer(ctx context.Context,
+	id uuid.UUID) (*entity.OwnershipTransfer, error) {
+
+	transfer, err := s.r.GetOwnershipTransferByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"transfer.id":  id,
+			"requester.id": ctx.Value("user.id"),
+		}, "[ConferenceService][getPendingOwnershipTransfer] Failed to get ownership transfer")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if transfer.Status != enum.OwnershipTransferPending {
+		return nil, errorpkg.ErrOwnershipTransferNotPending
+	}
+
+	return transfer, nil
+}
+
+func (s *conferenceService) RespondOwnershipTransfer(ctx context.Context, transferID uuid.UUID, accept bool) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	transfer, err := s.getPendingOwnershipTransfer(ctx, transferID)
+	if err != nil {
+		return err
+	}
+
+	if transfer.ToUserID != requesterID {
+		return errorpkg.ErrForbiddenUser
+	}
+
+	if accept {
+		err = s.r.AcceptOwnershipTransfer(ctx, transfer)
+	} else {
+		err = s.r.UpdateOwnershipTransferStatus(ctx, transferID, enum.OwnershipTransferDeclined)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrOwnershipTransferNotPending
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"transfer":     transfer,
+			"accept":       accept,
+			"requester.id": requesterID,
+		}, "[ConferenceService][RespondOwnershipTransfer] Failed to respond to ownership transfer")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"transfer":     transfer,
+		"accept":       accept,
+		"requester.id": requesterID,
+	}, "[ConferenceService][RespondOwnershipTransfer] Ownership transfer responded")
+
+	return nil
+}
+
+func (s *conferenceService) CancelOwnershipTransfer(ctx context.Context, transferID uuid.UUID) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	transfer, err := s.getPendingOwnershipTransfer(ctx, transferID)
+	if err != nil {
+		return err
+	}
+
+	if transfer.FromUserID != requesterID {
+		return errorpkg.ErrForbiddenUser
+	}
+
+	if err = s.r.UpdateOwnershipTransferStatus(ctx, transferID, enum.OwnershipTransferCancelled); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrOwnershipTransferNotPending
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"transfer":     transfer,
+			"requester.id": requesterID,
+		}, "[ConferenceService][CancelOwnershipTransfer] Failed to cancel ownership transfer")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"transfer":     transfer,
+		"requester.id": requesterID,
+	}, "[ConferenceService][CancelOwnershipTransfer] Ownership transfer cancelled")
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
+)
+
+func TestExpandOccurrences(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 0, 0, 0, time.UTC)
+	}
+	until := func(year int, month time.Month, day int) *time.Time {
+		u := date(year, month, day)
+		return &u
+	}
+	count := func(n int) *int {
+		return &n
+	}
+
+	tests := []struct {
+		name        string
+		startsAt    time.Time
+		duration    time.Duration
+		frequency   enum.RecurrenceFrequency
+		repeatUntil *time.Time
+		repeatCount *int
+		want        []time.Time
+		wantLen     int
+		wantErr     error
+	}{
+		{
+			name:        "weekly by count",
+			startsAt:    date(2026, time.January, 5),
+			frequency:   enum.RecurrenceWeekly,
+			repeatCount: count(3),
+			want:        []time.Time{date(2026, time.January, 5), date(2026, time.January, 12), date(2026, time.January, 19)},
+		},
+		{
+			name:        "biweekly by count",
+			startsAt:    date(2026, time.January, 5),
+			frequency:   enum.RecurrenceBiweekly,
+			repeatCount: count(2),
+			want:        []time.Time{date(2026, time.January, 5), date(2026, time.January, 19)},
+		},
+		{
+			name:        "until on an occurrence includes it",
+			startsAt:    date(2026, time.January, 5),
+			frequency:   enum.RecurrenceWeekly,
+			repeatUntil: until(2026, time.January, 19),
+			want:        []time.Time{date(2026, time.January, 5), date(2026, time.January, 12), date(2026, time.January, 19)},
+		},
+		{
+			name:        "until just before an occurrence excludes it",
+			startsAt:    date(2026, time.January, 5),
+			frequency:   enum.RecurrenceWeekly,
+			repeatUntil: until(2026, time.January, 18),
+			want:        []time.Time{date(2026, time.January, 5), date(2026, time.January, 12)},
+		},
+		{
+			name:        "monthly on the 31st skips shorter months",
+			startsAt:    date(2026, time.January, 31),
+			frequency:   enum.RecurrenceMonthly,
+			repeatCount: count(4),
+			want: []time.Time{
+				date(2026, time.January, 31), date(2026, time.March, 31),
+				date(2026, time.May, 31), date(2026, time.July, 31),
+			},
+		},
+		{
+			name:        "monthly until stops at the last matching month",
+			startsAt:    date(2026, time.January, 31),
+			frequency:   enum.RecurrenceMonthly,
+			repeatUntil: until(2026, time.June, 30),
+			want:        []time.Time{date(2026, time.January, 31), date(2026, time.March, 31), date(2026, time.May, 31)},
+		},
+		{
+			name:        "count at the cap",
+			startsAt:    date(2026, time.January, 5),
+			frequency:   enum.RecurrenceWeekly,
+			repeatCount: count(maxSeriesOccurrences),
+			wantLen:     maxSeriesOccurrences,
+		},
+		{
+			name:        "count over the cap",
+			startsAt:    date(2026, time.January, 5),
+			frequency:   enum.RecurrenceWeekly,
+			repeatCount: count(maxSeriesOccurrences + 1),
+			wantErr:     errorpkg.ErrTooManyOccurrences,
+		},
+		{
+			name:        "until over the cap",
+			startsAt:    date(2026, time.January, 5),
+			frequency:   enum.RecurrenceWeekly,
+			repeatUntil: until(2028, time.January, 5),
+			wantErr:     errorpkg.ErrTooManyOccurrences,
+		},
+		{
+			name:        "single occurrence",
+			startsAt:    date(2026, time.January, 5),
+			frequency:   enum.RecurrenceWeekly,
+			repeatCount: count(1),
+			wantErr:     errorpkg.ErrInvalidRecurrence,
+		},
+		{
+			name:      "neither until nor count",
+			startsAt:  date(2026, time.January, 5),
+			frequency: enum.RecurrenceWeekly,
+			wantErr:   errorpkg.ErrInvalidRecurrence,
+		},
+		{
+			name:        "both until and count",
+			startsAt:    date(2026, time.January, 5),
+			frequency:   enum.RecurrenceWeekly,
+			repeatUntil: until(2026, time.February, 5),
+			repeatCount: count(3),
+			wantErr:     errorpkg.ErrInvalidRecurrence,
+		},
+		{
+			name:        "unknown frequency",
+			startsAt:    date(2026, time.January, 5),
+			frequency:   enum.RecurrenceFrequency("daily"),
+			repeatCount: count(3),
+			wantErr:     errorpkg.ErrInvalidRecurrence,
+		},
+		{
+			name:        "occurrences overlap",
+			startsAt:    date(2026, time.January, 5),
+			duration:    8 * 24 * time.Hour,
+			frequency:   enum.RecurrenceWeekly,
+			repeatCount: count(2),
+			wantErr:     errorpkg.ErrInvalidRecurrence,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			duration := tt.duration
+			if duration == 0 {
+				duration = time.Hour
+			}
+
+			req := &dto.CreateConferenceSeriesRequest{
+				CreateConferenceProposalRequest: dto.CreateConferenceProposalRequest{
+					StartsAt: tt.startsAt,
+					EndsAt:   tt.startsAt.Add(duration),
+				},
+				Frequency:   tt.frequency,
+				RepeatUntil: tt.repeatUntil,
+				RepeatCount: tt.repeatCount,
+			}
+
+			got, err := expandOccurrences(req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.want == nil {
+				if len(got) != tt.wantLen {
+					t.Fatalf("expected %d occurrences, got %d", tt.wantLen, len(got))
+				}
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range tt.want {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d: expected %s, got %s", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
@@ -40,20 +40,50 @@ func InitFeedbackHandler(
 		handler.getFeedbacksByConferenceID(),
 	)
 
+	feedbackGroup.Get("/moderation-queue",
+		midw.RequireOneOfRoles(enum.RoleEventCoordinator),
+		handler.getModerationQueue(),
+	)
+
 	feedbackGroup.Delete("/:id",
 		midw.RequireOneOfRoles(enum.RoleEventCoordinator),
 		handler.deleteFeedback(),
 	)
+
+	feedbackGroup.Post("/:id/reply",
+		midw.RequireOneOfRoles(enum.RoleUser),
+		handler.createReply(),
+	)
+
+	feedbackGroup.Patch("/:id/reply",
+		midw.RequireOneOfRoles(enum.RoleUser),
+		handler.updateReply(),
+	)
+
+	feedbackGroup.Delete("/:id/reply",
+		midw.RequireOneOfRoles(enum.RoleUser, enum.RoleEventCoordinator),
+		handler.deleteReply(),
+	)
+
+	feedbackGroup.Post("/:id/flags",
+		midw.RequireOneOfRoles(enum.RoleUser),
+		handler.flagFeedback(),
+	)
+
+	feedbackGroup.Post("/:id/moderation",
+		midw.RequireOneOfRoles(enum.RoleEventCoordinator),
+		handler.moderateFeedback(),
+	)
+
+	feedbackGroup.Get("/:id/moderation",
+		midw.RequireOneOfRoles(enum.RoleEventCoordinator),
+		handler.getModerationHistory(),
+	)
 }
 
 func (h *feedbackHandler) createFeedback() fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
-		type request struct {
-			ConferenceID uuid.UUID `json:"conference_id" validate:"required,uuid"`
-			Comment      string    `json:"comment" validate:"required,min=3,max=1000"`
-		}
-
-		var req request
+		var req dto.CreateFeedbackRequest
 		if err := ctx.BodyParser(&req); err != nil {
 			return errorpkg.ErrFailParseRequest
 		}
@@ -64,7 +94,7 @@ func (h *feedbackHandler) createFeedback() fiber.Handler {
 
 		userID, _ := ctx.Locals("user.id").(uuid.UUID)
 
-		feedbackID, err := h.svc.CreateFeedback(ctx.Context(), userID, req.ConferenceID, req.Comment)
+		feedbackID, err := h.svc.CreateFeedback(ctx.Context(), userID, req)
 		if err != nil {
 			return err
 		}
@@ -91,7 +121,16 @@ func (h *feedbackHandler) getFeedbacksByConferenceID() fiber.Handler {
 			return err
 		}
 
-		feedbacks, lazyResp, err := h.svc.GetFeedbacksByConferenceID(c.Context(), conferenceID, lazyReq)
+		var filter dto.FeedbackFilterQuery
+		if err := c.QueryParser(&filter); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := h.val.ValidateStruct(filter); err != nil {
+			return err
+		}
+
+		feedbacks, lazyResp, err := h.svc.GetFeedbacksByConferenceID(c.Context(), conferenceID, lazyReq, filter)
 		if err != nil {
 			return err
 		}
@@ -117,3 +156,162 @@ func (h *feedbackHandler) deleteFeedback() fiber.Handler {
 		return c.SendStatus(fiber.StatusNoContent)
 	}
 }
+
+type replyRequest struct {
+	Body string `json:"body" validate:"required,min=1,max=1000"`
+}
+
+func (h *feedbackHandler) createReply() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		feedbackID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		var req replyRequest
+		if err = c.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = h.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		reply, err := h.svc.CreateReply(c.Context(), feedbackID, req.Body)
+		if err != nil {
+			return err
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(map[string]interface{}{
+			"reply": reply,
+		})
+	}
+}
+
+func (h *feedbackHandler) updateReply() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		feedbackID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		var req replyRequest
+		if err = c.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = h.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		if err = h.svc.UpdateReply(c.Context(), feedbackID, req.Body); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *feedbackHandler) deleteReply() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		feedbackID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = h.svc.DeleteReply(c.Context(), feedbackID); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *feedbackHandler) flagFeedback() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		feedbackID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		var req dto.FlagFeedbackRequest
+		if err = c.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = h.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		if err = h.svc.FlagFeedback(c.Context(), feedbackID, req); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusCreated)
+	}
+}
+
+func (h *feedbackHandler) getModerationQueue() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var lazyReq dto.LazyLoadQuery
+		if err := c.QueryParser(&lazyReq); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := h.val.ValidateStruct(lazyReq); err != nil {
+			return err
+		}
+
+		feedbacks, lazyResp, err := h.svc.GetModerationQueue(c.Context(), lazyReq)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"feedbacks":  feedbacks,
+			"pagination": lazyResp,
+		})
+	}
+}
+
+func (h *feedbackHandler) moderateFeedback() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		feedbackID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		var req dto.ModerateFeedbackRequest
+		if err = c.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = h.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		if err = h.svc.ModerateFeedback(c.Context(), feedbackID, req); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *feedbackHandler) getModerationHistory() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		feedbackID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		actions, err := h.svc.GetModerationHistory(c.Context(), feedbackID)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"actions": actions,
+		})
+	}
+}
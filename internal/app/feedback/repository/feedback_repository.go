@@ -9,6 +9,7 @@ import (
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
 	"time"
 )
 
@@ -23,46 +24,121 @@ func NewFeedbackRepository(db *sqlx.DB) contract.IFeedbackRepository {
 }
 
 func (r *feedbackRepository) createFeedback(ctx context.Context, tx sqlx.ExtContext, feedback *entity.Feedback) error {
-	query := `INSERT INTO feedbacks (id, user_id, conference_id, comment, created_at)
-		VALUES (:id, :user_id, :conference_id, :comment, :created_at)`
+	query := `INSERT INTO feedbacks (id, user_id, conference_id, comment, rating, content_score, speaker_score,
+			venue_score, is_anonymous, created_at)
+		VALUES (:id, :user_id, :conference_id, :comment, :rating, :content_score, :speaker_score,
+			:venue_score, :is_anonymous, :created_at)`
 	_, err := sqlx.NamedExecContext(ctx, tx, query, feedback)
 	return err
 }
 
+func (r *feedbackRepository) createSurveyAnswers(ctx context.Context, tx sqlx.ExtContext,
+	answers []entity.SurveyAnswer) error {
+
+	if len(answers) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO survey_answers (feedback_id, question_id, answer_index, rating_value, choice_value,
+			text_value)
+		VALUES (:feedback_id, :question_id, :answer_index, :rating_value, :choice_value, :text_value)`
+	_, err := sqlx.NamedExecContext(ctx, tx, query, answers)
+	return err
+}
+
 func (r *feedbackRepository) CreateFeedback(ctx context.Context, feedback *entity.Feedback) error {
-	return r.createFeedback(ctx, r.db, feedback)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = r.createFeedback(ctx, tx, feedback); err != nil {
+		return err
+	}
+
+	if err = r.createSurveyAnswers(ctx, tx, feedback.SurveyAnswers); err != nil {
+		return fmt.Errorf("failed to create survey answers: %w", err)
+	}
+
+	return tx.Commit()
 }
 
-func (r *feedbackRepository) GetFeedbacksByConferenceID(ctx context.Context,
-	conferenceID uuid.UUID, lazy dto.LazyLoadQuery) ([]entity.Feedback, dto.LazyLoadResponse, error) {
+func (r *feedbackRepository) GetFeedbacksByConferenceID(ctx context.Context, conferenceID uuid.UUID,
+	lazy dto.LazyLoadQuery, filter dto.FeedbackFilterQuery,
+	includeHidden bool) ([]entity.Feedback, dto.LazyLoadResponse, error) {
 
 	var feedbacks []entity.Feedback
 	var args []interface{}
 	args = append(args, conferenceID)
 	argCount := 1
 
-	query := `SELECT f.id, f.user_id, f.conference_id, f.comment, f.created_at, u.name as user_name
+	query := `SELECT f.id, f.user_id, f.conference_id, f.comment, f.rating, f.content_score, f.speaker_score,
+			f.venue_score, f.is_anonymous, f.created_at, f.hidden_at, u.name as user_name,
+			fr.id AS reply_id, fr.user_id AS reply_user_id, fr.body AS reply_body, fr.created_at AS reply_created_at,
+			fr.updated_at AS reply_updated_at, ru.name AS reply_user_name
         FROM feedbacks f
         JOIN users u ON f.user_id = u.id
+        LEFT JOIN feedback_replies fr ON fr.feedback_id = f.id
+        LEFT JOIN users ru ON fr.user_id = ru.id
         WHERE f.conference_id = $1 AND f.deleted_at IS NULL`
 
-	// Add pagination filters
-	if lazy.AfterID != uuid.Nil {
-		query += fmt.Sprintf(" AND f.id > $%d", argCount+1)
-		args = append(args, lazy.AfterID)
+	if !includeHidden {
+		query += " AND f.hidden_at IS NULL"
+	}
+
+	// Add rating filters
+	if filter.MinRating != 0 {
+		query += fmt.Sprintf(" AND f.rating >= $%d", argCount+1)
+		args = append(args, filter.MinRating)
 		argCount++
 	}
-	if lazy.BeforeID != uuid.Nil {
-		query += fmt.Sprintf(" AND f.id < $%d", argCount+1)
-		args = append(args, lazy.BeforeID)
+	if filter.MaxRating != 0 {
+		query += fmt.Sprintf(" AND f.rating <= $%d", argCount+1)
+		args = append(args, filter.MaxRating)
+		argCount++
+	}
+
+	// Feedbacks are ordered by ID, or by rating then ID. Unrated feedbacks sort as the lowest rating.
+	// The cursor is still a feedback ID, its sort key is looked up so pages stay stable.
+	sortKey := "f.id"
+	cursorKey := "$%d"
+	descending := false
+	if filter.Sort != "" {
+		sortKey = "(COALESCE(f.rating, 0), f.id)"
+		cursorKey = "(SELECT COALESCE(rating, 0), id FROM feedbacks WHERE id = $%d)"
+		descending = filter.Sort == "rating_desc"
+	}
+
+	// Paginating backwards walks the order in reverse, the result is flipped back below
+	reverse := descending != (lazy.BeforeID != uuid.Nil)
+
+	// Add pagination filters
+	if lazy.AfterID != uuid.Nil || lazy.BeforeID != uuid.Nil {
+		cursor := lazy.AfterID
+		if lazy.BeforeID != uuid.Nil {
+			cursor = lazy.BeforeID
+		}
+
+		operator := ">"
+		if reverse {
+			operator = "<"
+		}
+
+		query += fmt.Sprintf(" AND %s %s "+cursorKey, sortKey, operator, argCount+1)
+		args = append(args, cursor)
 		argCount++
 	}
 
 	// Add ordering and limit
-	if lazy.BeforeID != uuid.Nil {
-		query += " ORDER BY f.id DESC"
+	direction := "ASC"
+	if reverse {
+		direction = "DESC"
+	}
+	if filter.Sort != "" {
+		query += fmt.Sprintf(" ORDER BY COALESCE(f.rating, 0) %s, f.id %s", direction, direction)
 	} else {
-		query += " ORDER BY f.id ASC"
+		query += " ORDER BY f.id " + direction
 	}
 	query += fmt.Sprintf(" LIMIT $%d", argCount+1)
 	args = append(args, lazy.Limit+1) // Request one extra record to determine if there are more results
@@ -77,16 +153,31 @@ func (r *feedbackRepository) GetFeedbacksByConferenceID(ctx context.Context,
 	// Scan results
 	for rows.Next() {
 		var row struct {
-			ID           uuid.UUID `db:"id"`
-			UserID       uuid.UUID `db:"user_id"`
-			ConferenceID uuid.UUID `db:"conference_id"`
-			Comment      string    `db:"comment"`
-			CreatedAt    time.Time `db:"created_at"`
-			UserName     string    `db:"user_name"`
+			ID           uuid.UUID  `db:"id"`
+			UserID       uuid.UUID  `db:"user_id"`
+			ConferenceID uuid.UUID  `db:"conference_id"`
+			Comment      string     `db:"comment"`
+			Rating       *int       `db:"rating"`
+			ContentScore *int       `db:"content_score"`
+			SpeakerScore *int       `db:"speaker_score"`
+			VenueScore   *int       `db:"venue_score"`
+			IsAnonymous  bool       `db:"is_anonymous"`
+			CreatedAt    time.Time  `db:"created_at"`
+			HiddenAt     *time.Time `db:"hidden_at"`
+			UserName     string     `db:"user_name"`
+
+			ReplyID        *uuid.UUID `db:"reply_id"`
+			ReplyUserID    *uuid.UUID `db:"reply_user_id"`
+			ReplyBody      *string    `db:"reply_body"`
+			ReplyCreatedAt *time.Time `db:"reply_created_at"`
+			ReplyUpdatedAt *time.Time `db:"reply_updated_at"`
+			ReplyUserName  *string    `db:"reply_user_name"`
 		}
 
-		if err2 := rows.Scan(&row.ID, &row.UserID, &row.ConferenceID, &row.Comment, &row.CreatedAt,
-			&row.UserName); err2 != nil {
+		if err2 := rows.Scan(&row.ID, &row.UserID, &row.ConferenceID, &row.Comment, &row.Rating,
+			&row.ContentScore, &row.SpeakerScore, &row.VenueScore, &row.IsAnonymous, &row.CreatedAt, &row.HiddenAt, &row.UserName,
+			&row.ReplyID, &row.ReplyUserID, &row.ReplyBody, &row.ReplyCreatedAt, &row.ReplyUpdatedAt,
+			&row.ReplyUserName); err2 != nil {
 			return nil, dto.LazyLoadResponse{}, fmt.Errorf("failed to scan feedback: %w", err2)
 		}
 
@@ -95,12 +186,32 @@ func (r *feedbackRepository) GetFeedbacksByConferenceID(ctx context.Context,
 			UserID:       row.UserID,
 			ConferenceID: row.ConferenceID,
 			Comment:      row.Comment,
+			Rating:       row.Rating,
+			ContentScore: row.ContentScore,
+			SpeakerScore: row.SpeakerScore,
+			VenueScore:   row.VenueScore,
+			IsAnonymous:  row.IsAnonymous,
 			CreatedAt:    row.CreatedAt,
+			HiddenAt:     row.HiddenAt,
 			User: &entity.User{
 				ID:   row.UserID,
 				Name: row.UserName,
 			},
 		}
+		if row.ReplyID != nil {
+			feedback.Reply = &entity.FeedbackReply{
+				ID:         *row.ReplyID,
+				FeedbackID: row.ID,
+				UserID:     *row.ReplyUserID,
+				Body:       *row.ReplyBody,
+				CreatedAt:  *row.ReplyCreatedAt,
+				UpdatedAt:  *row.ReplyUpdatedAt,
+				User: &entity.User{
+					ID:   *row.ReplyUserID,
+					Name: *row.ReplyUserName,
+				},
+			}
+		}
 		feedbacks = append(feedbacks, feedback)
 	}
 
@@ -126,7 +237,7 @@ func (r *feedbackRepository) GetFeedbacksByConferenceID(ctx context.Context,
 			}
 		}
 
-		// For BeforeID, reverse the final result set to maintain ascending order
+		// For BeforeID, reverse the final result set to maintain the requested order
 		if lazy.BeforeID != uuid.Nil {
 			for i := 0; i < len(feedbacks)/2; i++ {
 				j := len(feedbacks) - 1 - i
@@ -160,8 +271,323 @@ func (r *feedbackRepository) deleteFeedback(ctx context.Context, tx sqlx.ExtCont
 	return err
 }
 
-func (r *feedbackRepository) DeleteFeedback(ctx context.Context, id uuid.UUID) error {
-	return r.deleteFeedback(ctx, r.db, id)
+func (r *feedbackRepository) setFeedbackHidden(ctx context.Context, tx sqlx.ExtContext, id uuid.UUID,
+	hiddenAt *time.Time) error {
+
+	res, err := tx.ExecContext(ctx, `UPDATE feedbacks SET hidden_at = $2 WHERE id = $1 AND deleted_at IS NULL`,
+		id, hiddenAt)
+	if err != nil {
+		return fmt.Errorf("failed to update feedback visibility: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *feedbackRepository) createModerationAction(ctx context.Context, tx sqlx.ExtContext,
+	action *entity.FeedbackModerationAction) error {
+
+	_, err := sqlx.NamedExecContext(ctx, tx, `
+		INSERT INTO feedback_moderation_actions (id, feedback_id, moderator_id, action, note, created_at)
+		VALUES (:id, :feedback_id, :moderator_id, :action, :note, :created_at)`, action)
+	return err
+}
+
+func (r *feedbackRepository) ModerateFeedback(ctx context.Context, action *entity.FeedbackModerationAction) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	switch action.Action {
+	case enum.ModerationHide:
+		err = r.setFeedbackHidden(ctx, tx, action.FeedbackID, &action.CreatedAt)
+	case enum.ModerationRestore:
+		err = r.setFeedbackHidden(ctx, tx, action.FeedbackID, nil)
+	case enum.ModerationDelete:
+		err = r.deleteFeedback(ctx, tx, action.FeedbackID)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Whatever the decision, the flags raised so far have been dealt with
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE feedback_flags
+		SET resolved_at = $2
+		WHERE feedback_id = $1
+		AND resolved_at IS NULL`, action.FeedbackID, action.CreatedAt); err != nil {
+		return fmt.Errorf("failed to resolve feedback flags: %w", err)
+	}
+
+	if err = r.createModerationAction(ctx, tx, action); err != nil {
+		return fmt.Errorf("failed to record moderation action: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *feedbackRepository) CreateFeedbackFlag(ctx context.Context, flag *entity.FeedbackFlag, autoHideThreshold int,
+	autoHide *entity.FeedbackModerationAction) (bool, error) {
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// Lock the feedback first so concurrent flags on it run one after another. Each one then counts
+	// the flags committed before it, so the threshold is crossed exactly once.
+	if _, err = tx.ExecContext(ctx, `SELECT 1 FROM feedbacks WHERE id = $1 FOR UPDATE`, flag.FeedbackID); err != nil {
+		return false, fmt.Errorf("failed to lock feedback: %w", err)
+	}
+
+	if _, err = sqlx.NamedExecContext(ctx, tx, `
+		INSERT INTO feedback_flags (id, feedback_id, user_id, reason, details, created_at)
+		VALUES (:id, :feedback_id, :user_id, :reason, :details, :created_at)`, flag); err != nil {
+		return false, err
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE feedbacks
+		SET hidden_at = $2
+		WHERE id = $1
+		AND hidden_at IS NULL
+		AND deleted_at IS NULL
+		AND (SELECT COUNT(*) FROM feedback_flags WHERE feedback_id = $1 AND resolved_at IS NULL) >= $3`,
+		flag.FeedbackID, autoHide.CreatedAt, autoHideThreshold)
+	if err != nil {
+		return false, fmt.Errorf("failed to auto-hide feedback: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	hidden := rowsAffected > 0
+	if hidden {
+		if err = r.createModerationAction(ctx, tx, autoHide); err != nil {
+			return false, fmt.Errorf("failed to record moderation action: %w", err)
+		}
+	}
+
+	return hidden, tx.Commit()
+}
+
+func (r *feedbackRepository) GetFlaggedFeedbacks(ctx context.Context,
+	lazy dto.LazyLoadQuery) ([]entity.Feedback, dto.LazyLoadResponse, error) {
+
+	var args []interface{}
+	query := `SELECT f.id, f.user_id, f.conference_id, f.comment, f.rating, f.content_score, f.speaker_score,
+			f.venue_score, f.is_anonymous, f.created_at, f.hidden_at, u.name
+		FROM feedbacks f
+		JOIN users u ON f.user_id = u.id
+		WHERE f.deleted_at IS NULL
+		AND EXISTS (SELECT 1 FROM feedback_flags ff WHERE ff.feedback_id = f.id AND ff.resolved_at IS NULL)`
+
+	if lazy.AfterID != uuid.Nil {
+		args = append(args, lazy.AfterID)
+		query += fmt.Sprintf(" AND f.id > $%d", len(args))
+	}
+	if lazy.BeforeID != uuid.Nil {
+		args = append(args, lazy.BeforeID)
+		query += fmt.Sprintf(" AND f.id < $%d", len(args))
+	}
+
+	if lazy.BeforeID != uuid.Nil {
+		query += " ORDER BY f.id DESC"
+	} else {
+		query += " ORDER BY f.id ASC"
+	}
+	args = append(args, lazy.Limit+1) // Request one extra record to determine if there are more results
+	query += fmt.Sprintf(" LIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, dto.LazyLoadResponse{}, fmt.Errorf("failed to query flagged feedbacks: %w", err)
+	}
+	defer rows.Close()
+
+	var feedbacks []entity.Feedback
+	for rows.Next() {
+		feedback := entity.Feedback{User: &entity.User{}}
+		if err = rows.Scan(&feedback.ID, &feedback.UserID, &feedback.ConferenceID, &feedback.Comment,
+			&feedback.Rating, &feedback.ContentScore, &feedback.SpeakerScore, &feedback.VenueScore,
+			&feedback.IsAnonymous, &feedback.CreatedAt, &feedback.HiddenAt, &feedback.User.Name); err != nil {
+			return nil, dto.LazyLoadResponse{}, fmt.Errorf("failed to scan flagged feedback: %w", err)
+		}
+		feedback.User.ID = feedback.UserID
+		feedbacks = append(feedbacks, feedback)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dto.LazyLoadResponse{}, fmt.Errorf("error iterating flagged feedbacks: %w", err)
+	}
+
+	lazyResp := dto.LazyLoadResponse{}
+	if len(feedbacks) > 0 {
+		if len(feedbacks) > lazy.Limit {
+			lazyResp.HasMore = true
+			feedbacks = feedbacks[:lazy.Limit]
+		}
+
+		// For BeforeID, reverse the final result set to maintain ascending order
+		if lazy.BeforeID != uuid.Nil {
+			for i := 0; i < len(feedbacks)/2; i++ {
+				j := len(feedbacks) - 1 - i
+				feedbacks[i], feedbacks[j] = feedbacks[j], feedbacks[i]
+			}
+		}
+
+		lazyResp.FirstID = feedbacks[0].ID
+		lazyResp.LastID = feedbacks[len(feedbacks)-1].ID
+	}
+
+	return feedbacks, lazyResp, nil
+}
+
+func (r *feedbackRepository) GetOpenFlags(ctx context.Context, feedbackIDs []uuid.UUID) ([]entity.FeedbackFlag, error) {
+	if len(feedbackIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT id, feedback_id, user_id, reason, details, created_at, resolved_at
+		FROM feedback_flags
+		WHERE feedback_id IN (?)
+		AND resolved_at IS NULL
+		ORDER BY created_at, id`, feedbackIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var flags []entity.FeedbackFlag
+	if err = r.db.SelectContext(ctx, &flags, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	return flags, nil
+}
+
+func (r *feedbackRepository) GetModerationActions(ctx context.Context,
+	feedbackID uuid.UUID) ([]entity.FeedbackModerationAction, error) {
+
+	rows, err := r.db.QueryxContext(ctx, `
+		SELECT a.id, a.feedback_id, a.moderator_id, a.action, a.note, a.created_at, u.name
+		FROM feedback_moderation_actions a
+		LEFT JOIN users u ON a.moderator_id = u.id
+		WHERE a.feedback_id = $1
+		ORDER BY a.created_at, a.id`, feedbackID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var actions []entity.FeedbackModerationAction
+	for rows.Next() {
+		var action entity.FeedbackModerationAction
+		var moderatorName *string
+		if err = rows.Scan(&action.ID, &action.FeedbackID, &action.ModeratorID, &action.Action, &action.Note,
+			&action.CreatedAt, &moderatorName); err != nil {
+			return nil, err
+		}
+		if action.ModeratorID != nil && moderatorName != nil {
+			action.Moderator = &entity.User{
+				ID:   *action.ModeratorID,
+				Name: *moderatorName,
+			}
+		}
+		actions = append(actions, action)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return actions, nil
+}
+
+func (r *feedbackRepository) GetFeedbackByID(ctx context.Context, id uuid.UUID) (*entity.Feedback, error) {
+	var row struct {
+		entity.Feedback
+		UserName  string `db:"user_name"`
+		UserEmail string `db:"user_email"`
+	}
+
+	if err := r.db.GetContext(ctx, &row, `
+		SELECT f.id, f.user_id, f.conference_id, f.comment, f.rating, f.content_score, f.speaker_score,
+			f.venue_score, f.is_anonymous, f.created_at, f.hidden_at, f.deleted_at, u.name AS user_name, u.email AS user_email
+		FROM feedbacks f
+		JOIN users u ON f.user_id = u.id
+		WHERE f.id = $1
+		AND f.deleted_at IS NULL`, id); err != nil {
+		return nil, err
+	}
+
+	feedback := row.Feedback
+	feedback.User = &entity.User{
+		ID:    row.UserID,
+		Name:  row.UserName,
+		Email: row.UserEmail,
+	}
+
+	return &feedback, nil
+}
+
+func (r *feedbackRepository) CreateFeedbackReply(ctx context.Context, reply *entity.FeedbackReply) error {
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO feedback_replies (id, feedback_id, user_id, body, created_at, updated_at)
+		VALUES (:id, :feedback_id, :user_id, :body, :created_at, :updated_at)`, reply)
+	return err
+}
+
+func (r *feedbackRepository) UpdateFeedbackReply(ctx context.Context, reply *entity.FeedbackReply) error {
+	res, err := r.db.NamedExecContext(ctx, `
+		UPDATE feedback_replies
+		SET body = :body, updated_at = :updated_at
+		WHERE feedback_id = :feedback_id`, reply)
+	if err != nil {
+		return fmt.Errorf("failed to update feedback reply: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *feedbackRepository) DeleteFeedbackReply(ctx context.Context, feedbackID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM feedback_replies WHERE feedback_id = $1`, feedbackID)
+	if err != nil {
+		return fmt.Errorf("failed to delete feedback reply: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
 }
 
 func (r *feedbackRepository) IsFeedbackGiven(ctx context.Context, userID, conferenceID uuid.UUID) (bool, error) {
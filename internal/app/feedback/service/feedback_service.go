@@ -7,11 +7,15 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
+	"github.com/nathakusuma/auditorium-reservation-backend/internal/infra/env"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/log"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/mail"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/uuidpkg"
 )
 
@@ -19,6 +23,8 @@ type feedbackService struct {
 	repo            contract.IFeedbackRepository
 	registrationSvc contract.IRegistrationService
 	conferenceSvc   contract.IConferenceService
+	surveySvc       contract.ISurveyService
+	mailer          mail.IMailer
 	uuid            uuidpkg.IUUID
 }
 
@@ -26,18 +32,24 @@ func NewFeedbackService(
 	feedbackRepository contract.IFeedbackRepository,
 	registrationService contract.IRegistrationService,
 	conferenceService contract.IConferenceService,
+	surveyService contract.ISurveyService,
+	mailer mail.IMailer,
 	uuid uuidpkg.IUUID,
 ) contract.IFeedbackService {
 	return &feedbackService{
 		repo:            feedbackRepository,
 		registrationSvc: registrationService,
 		conferenceSvc:   conferenceService,
+		surveySvc:       surveyService,
+		mailer:          mailer,
 		uuid:            uuid,
 	}
 }
 
-func (s *feedbackService) CreateFeedback(ctx context.Context, userID, conferenceID uuid.UUID,
-	comment string) (uuid.UUID, error) {
+func (s *feedbackService) CreateFeedback(ctx context.Context, userID uuid.UUID,
+	req dto.CreateFeedbackRequest) (uuid.UUID, error) {
+
+	conferenceID := req.ConferenceID
 
 	isRegistered, err := s.registrationSvc.IsUserRegisteredToConference(ctx, conferenceID, userID)
 	if err != nil {
@@ -65,14 +77,23 @@ func (s *feedbackService) CreateFeedback(ctx context.Context, userID, conference
 		return uuid.Nil, err
 	}
 
-	if conference.Host.ID == userID {
+	if conference.IsHostedBy(userID) {
 		return uuid.Nil, errorpkg.ErrHostCannotGiveFeedback
 	}
 
+	if conference.Status == enum.ConferenceCancelled {
+		return uuid.Nil, errorpkg.ErrConferenceCancelled
+	}
+
 	if conference.EndsAt.After(time.Now()) {
 		return uuid.Nil, errorpkg.ErrConferenceNotEnded
 	}
 
+	surveyAnswers, err := s.surveySvc.ValidateAnswers(ctx, conferenceID, req.SurveyAnswers)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
 	feedbackID, err := s.uuid.NewV7()
 	if err != nil {
 		traceID := log.ErrorWithTraceID(map[string]interface{}{
@@ -84,10 +105,20 @@ func (s *feedbackService) CreateFeedback(ctx context.Context, userID, conference
 	}
 
 	feedback := &entity.Feedback{
-		ID:           feedbackID,
-		UserID:       userID,
-		ConferenceID: conferenceID,
-		Comment:      comment,
+		ID:            feedbackID,
+		UserID:        userID,
+		ConferenceID:  conferenceID,
+		Comment:       req.Comment,
+		Rating:        &req.Rating,
+		ContentScore:  req.ContentScore,
+		SpeakerScore:  req.SpeakerScore,
+		VenueScore:    req.VenueScore,
+		IsAnonymous:   req.IsAnonymous,
+		SurveyAnswers: surveyAnswers,
+	}
+
+	for i := range feedback.SurveyAnswers {
+		feedback.SurveyAnswers[i].FeedbackID = feedbackID
 	}
 
 	if err := s.repo.CreateFeedback(ctx, feedback); err != nil {
@@ -106,9 +137,17 @@ func (s *feedbackService) CreateFeedback(ctx context.Context, userID, conference
 }
 
 func (s *feedbackService) GetFeedbacksByConferenceID(ctx context.Context, conferenceID uuid.UUID,
-	lazyReq dto.LazyLoadQuery) ([]dto.FeedbackResponse, dto.LazyLoadResponse, error) {
+	lazyReq dto.LazyLoadQuery, filter dto.FeedbackFilterQuery) ([]dto.FeedbackResponse, dto.LazyLoadResponse, error) {
+
+	if filter.MinRating != 0 && filter.MaxRating != 0 && filter.MinRating > filter.MaxRating {
+		return nil, dto.LazyLoadResponse{}, errorpkg.ErrInvalidRatingRange
+	}
 
-	feedbacks, lazyResp, err := s.repo.GetFeedbacksByConferenceID(ctx, conferenceID, lazyReq)
+	// Hidden feedback stays visible to staff so they can review it
+	requesterRole, _ := ctx.Value("user.role").(enum.UserRole)
+	includeHidden := requesterRole != enum.RoleUser
+
+	feedbacks, lazyResp, err := s.repo.GetFeedbacksByConferenceID(ctx, conferenceID, lazyReq, filter, includeHidden)
 	if err != nil {
 		traceID := log.ErrorWithTraceID(map[string]interface{}{
 			"error":        err,
@@ -119,30 +158,444 @@ func (s *feedbackService) GetFeedbacksByConferenceID(ctx context.Context, confer
 
 	resp := make([]dto.FeedbackResponse, len(feedbacks))
 	for i, feedback := range feedbacks {
-		resp[i].PopulateFromEntity(&feedback)
+		resp[i].PopulateFromEntity(&feedback).HideAnonymousAuthor(requesterRole)
 	}
 
 	return resp, lazyResp, nil
 }
 
 func (s *feedbackService) DeleteFeedback(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.DeleteFeedback(ctx, id); err != nil {
+	// Deleting is a moderation decision too, so it goes through the moderation log
+	if err := s.moderate(ctx, id, enum.ModerationDelete, nil); err != nil {
+		return err
+	}
+
+	log.Info(map[string]interface{}{
+		"feedback.id":  id,
+		"requester.id": ctx.Value("user.id"),
+	}, "[FeedbackService][DeleteFeedback] Feedback deleted successfully")
+
+	return nil
+}
+
+// getRepliableFeedback loads the feedback and its conference, and makes sure the requester hosts the conference.
+// Event coordinators pass as well when allowStaff is set.
+func (s *feedbackService) getRepliableFeedback(ctx context.Context, feedbackID uuid.UUID,
+	allowStaff bool) (*entity.Feedback, *dto.ConferenceResponse, error) {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+	requesterRole, _ := ctx.Value("user.role").(enum.UserRole)
+
+	feedback, err := s.repo.GetFeedbackByID(ctx, feedbackID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"feedback.id":  feedbackID,
+			"requester.id": requesterID,
+		}, "[FeedbackService][getRepliableFeedback] Failed to get feedback")
+		return nil, nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	conference, err := s.conferenceSvc.GetConferenceByID(ctx, feedback.ConferenceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	isStaff := allowStaff && requesterRole == enum.RoleEventCoordinator
+	if !isStaff && !conference.IsHostedBy(requesterID) {
+		return nil, nil, errorpkg.ErrForbiddenUser
+	}
+
+	return feedback, conference, nil
+}
+
+func (s *feedbackService) CreateReply(ctx context.Context, feedbackID uuid.UUID,
+	body string) (dto.FeedbackReplyResponse, error) {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	feedback, conference, err := s.getRepliableFeedback(ctx, feedbackID, false)
+	if err != nil {
+		return dto.FeedbackReplyResponse{}, err
+	}
+
+	replyID, err := s.uuid.NewV7()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"feedback.id":  feedbackID,
+			"requester.id": requesterID,
+		}, "[FeedbackService][CreateReply] Failed to generate UUID")
+		return dto.FeedbackReplyResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	now := time.Now()
+	reply := entity.FeedbackReply{
+		ID:         replyID,
+		FeedbackID: feedbackID,
+		UserID:     requesterID,
+		Body:       body,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err = s.repo.CreateFeedbackReply(ctx, &reply); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "feedback_replies_feedback_id_key" {
+			return dto.FeedbackReplyResponse{}, errorpkg.ErrFeedbackReplyAlreadyExists
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error": err,
+			"reply": reply,
+		}, "[FeedbackService][CreateReply] Failed to create reply")
+		return dto.FeedbackReplyResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"reply": reply,
+	}, "[FeedbackService][CreateReply] Feedback reply created")
+
+	var hostName string
+	for _, host := range conference.Hosts {
+		if host.User != nil && host.User.ID == requesterID {
+			hostName = host.User.Name
+		}
+	}
+
+	go func() {
+		err2 := s.mailer.Send(
+			feedback.User.Email,
+			"[Auditorium Reservation] Your Feedback Got a Reply",
+			"feedback_reply.html",
+			map[string]interface{}{
+				"name":       feedback.User.Name,
+				"conference": conference.Title,
+				"host":       hostName,
+				"reply":      body,
+				"href":       env.GetEnv().FrontendURL + "/conferences/" + conference.ID.String(),
+			})
+
+		if err2 != nil {
+			log.Error(map[string]interface{}{
+				"error":       err2,
+				"feedback.id": feedbackID,
+				"email":       feedback.User.Email,
+			}, "[FeedbackService][CreateReply] Failed to send reply email")
+		}
+	}()
+
+	var resp dto.FeedbackReplyResponse
+	resp.PopulateFromEntity(&reply)
+	return resp, nil
+}
+
+func (s *feedbackService) UpdateReply(ctx context.Context, feedbackID uuid.UUID, body string) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	if _, _, err := s.getRepliableFeedback(ctx, feedbackID, false); err != nil {
+		return err
+	}
+
+	reply := entity.FeedbackReply{
+		FeedbackID: feedbackID,
+		Body:       body,
+		UpdatedAt:  time.Now(),
+	}
+
+	if err := s.repo.UpdateFeedbackReply(ctx, &reply); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"feedback.id":  feedbackID,
+			"requester.id": requesterID,
+		}, "[FeedbackService][UpdateReply] Failed to update reply")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"feedback.id":  feedbackID,
+		"requester.id": requesterID,
+	}, "[FeedbackService][UpdateReply] Feedback reply updated")
+
+	return nil
+}
+
+func (s *feedbackService) DeleteReply(ctx context.Context, feedbackID uuid.UUID) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	// Coordinators moderate replies the same way they moderate feedback
+	if _, _, err := s.getRepliableFeedback(ctx, feedbackID, true); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteFeedbackReply(ctx, feedbackID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return errorpkg.ErrNotFound
 		}
 
 		traceID := log.ErrorWithTraceID(map[string]interface{}{
 			"error":        err,
-			"feedback.id":  id,
+			"feedback.id":  feedbackID,
+			"requester.id": requesterID,
+		}, "[FeedbackService][DeleteReply] Failed to delete reply")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"feedback.id":  feedbackID,
+		"requester.id": requesterID,
+	}, "[FeedbackService][DeleteReply] Feedback reply deleted")
+
+	return nil
+}
+
+func (s *feedbackService) FlagFeedback(ctx context.Context, feedbackID uuid.UUID, req dto.FlagFeedbackRequest) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	feedback, err := s.repo.GetFeedbackByID(ctx, feedbackID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"feedback.id":  feedbackID,
+			"requester.id": requesterID,
+		}, "[FeedbackService][FlagFeedback] Failed to get feedback")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if feedback.UserID == requesterID {
+		return errorpkg.ErrCannotFlagOwnFeedback
+	}
+
+	// Only people who attended or hosted the conference can flag its feedback
+	conference, err := s.conferenceSvc.GetConferenceByID(ctx, feedback.ConferenceID)
+	if err != nil {
+		return err
+	}
+
+	if !conference.IsHostedBy(requesterID) {
+		isRegistered, err2 := s.registrationSvc.IsUserRegisteredToConference(ctx, feedback.ConferenceID, requesterID)
+		if err2 != nil {
+			return err2
+		}
+		if !isRegistered {
+			return errorpkg.ErrUserNotRegisteredToConference
+		}
+	}
+
+	flagID, err := s.uuid.NewV7()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"feedback.id":  feedbackID,
+			"requester.id": requesterID,
+		}, "[FeedbackService][FlagFeedback] Failed to generate flag ID")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	actionID, err := s.uuid.NewV7()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"feedback.id":  feedbackID,
+			"requester.id": requesterID,
+		}, "[FeedbackService][FlagFeedback] Failed to generate moderation action ID")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	now := time.Now()
+	flag := entity.FeedbackFlag{
+		ID:         flagID,
+		FeedbackID: feedbackID,
+		UserID:     requesterID,
+		Reason:     req.Reason,
+		Details:    req.Details,
+		CreatedAt:  now,
+	}
+	autoHide := entity.FeedbackModerationAction{
+		ID:         actionID,
+		FeedbackID: feedbackID,
+		Action:     enum.ModerationAutoHide,
+		CreatedAt:  now,
+	}
+
+	hidden, err := s.repo.CreateFeedbackFlag(ctx, &flag, env.GetEnv().FeedbackAutoHideFlags, &autoHide)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "feedback_flags_feedback_id_user_id_key" {
+			return errorpkg.ErrFeedbackAlreadyFlagged
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error": err,
+			"flag":  flag,
+		}, "[FeedbackService][FlagFeedback] Failed to create flag")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"flag":        flag,
+		"auto_hidden": hidden,
+	}, "[FeedbackService][FlagFeedback] Feedback flagged")
+
+	return nil
+}
+
+func (s *feedbackService) GetModerationQueue(ctx context.Context,
+	lazyReq dto.LazyLoadQuery) ([]dto.FlaggedFeedbackResponse, dto.LazyLoadResponse, error) {
+
+	requesterRole, _ := ctx.Value("user.role").(enum.UserRole)
+
+	feedbacks, lazyResp, err := s.repo.GetFlaggedFeedbacks(ctx, lazyReq)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
 			"requester.id": ctx.Value("user.id"),
-		}, "[FeedbackService][DeleteFeedback] Failed to delete feedback")
+		}, "[FeedbackService][GetModerationQueue] Failed to get flagged feedbacks")
+		return nil, dto.LazyLoadResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	ids := make([]uuid.UUID, len(feedbacks))
+	for i := range feedbacks {
+		ids[i] = feedbacks[i].ID
+	}
+
+	flags, err := s.repo.GetOpenFlags(ctx, ids)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"requester.id": ctx.Value("user.id"),
+		}, "[FeedbackService][GetModerationQueue] Failed to get open flags")
+		return nil, dto.LazyLoadResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	flagsByFeedback := make(map[uuid.UUID][]dto.FeedbackFlagResponse, len(feedbacks))
+	for i := range flags {
+		flagsByFeedback[flags[i].FeedbackID] = append(flagsByFeedback[flags[i].FeedbackID],
+			*new(dto.FeedbackFlagResponse).PopulateFromEntity(&flags[i]))
+	}
+
+	resp := make([]dto.FlaggedFeedbackResponse, len(feedbacks))
+	for i := range feedbacks {
+		resp[i].Feedback.PopulateFromEntity(&feedbacks[i]).HideAnonymousAuthor(requesterRole)
+		resp[i].Flags = flagsByFeedback[feedbacks[i].ID]
+	}
+
+	return resp, lazyResp, nil
+}
+
+func (s *feedbackService) ModerateFeedback(ctx context.Context, feedbackID uuid.UUID,
+	req dto.ModerateFeedbackRequest) error {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	feedback, err := s.repo.GetFeedbackByID(ctx, feedbackID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"feedback.id":  feedbackID,
+			"requester.id": requesterID,
+		}, "[FeedbackService][ModerateFeedback] Failed to get feedback")
 		return errorpkg.ErrInternalServer.WithTraceID(traceID)
 	}
 
+	switch req.Action {
+	case enum.ModerationRestore:
+		if feedback.HiddenAt == nil {
+			return errorpkg.ErrFeedbackNotHidden
+		}
+	case enum.ModerationDismiss:
+		// Dismissing leaves the visibility as is, so a hidden feedback has to be restored instead
+		if feedback.HiddenAt != nil {
+			return errorpkg.ErrFeedbackHidden
+		}
+	}
+
+	if err = s.moderate(ctx, feedbackID, req.Action, req.Note); err != nil {
+		return err
+	}
+
 	log.Info(map[string]interface{}{
-		"feedback.id":  id,
-		"requester.id": ctx.Value("user.id"),
-	}, "[FeedbackService][DeleteFeedback] Feedback deleted successfully")
+		"feedback.id":  feedbackID,
+		"action":       req.Action,
+		"requester.id": requesterID,
+	}, "[FeedbackService][ModerateFeedback] Feedback moderated")
+
+	return nil
+}
+
+// moderate applies and records a moderation action taken by the requester
+func (s *feedbackService) moderate(ctx context.Context, feedbackID uuid.UUID, action enum.ModerationAction,
+	note *string) error {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	actionID, err := s.uuid.NewV7()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"feedback.id":  feedbackID,
+			"requester.id": requesterID,
+		}, "[FeedbackService][moderate] Failed to generate moderation action ID")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	moderation := entity.FeedbackModerationAction{
+		ID:          actionID,
+		FeedbackID:  feedbackID,
+		ModeratorID: &requesterID,
+		Action:      action,
+		Note:        note,
+		CreatedAt:   time.Now(),
+	}
+
+	if err = s.repo.ModerateFeedback(ctx, &moderation); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":      err,
+			"moderation": moderation,
+		}, "[FeedbackService][moderate] Failed to moderate feedback")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
 
 	return nil
 }
+
+func (s *feedbackService) GetModerationHistory(ctx context.Context,
+	feedbackID uuid.UUID) ([]dto.FeedbackModerationActionResponse, error) {
+
+	actions, err := s.repo.GetModerationActions(ctx, feedbackID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"feedback.id":  feedbackID,
+			"requester.id": ctx.Value("user.id"),
+		}, "[FeedbackService][GetModerationHistory] Failed to get moderation actions")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	resp := make([]dto.FeedbackModerationActionResponse, len(actions))
+	for i := range actions {
+		resp[i].PopulateFromEntity(&actions[i])
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
+	"github.com/nathakusuma/auditorium-reservation-backend/internal/middleware"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/validator"
+)
+
+type kioskHandler struct {
+	svc contract.IKioskService
+	val validator.IValidator
+}
+
+func InitKioskHandler(
+	router fiber.Router,
+	middleware *middleware.Middleware,
+	validator validator.IValidator,
+	kioskService contract.IKioskService) {
+
+	handler := kioskHandler{
+		svc: kioskService,
+		val: validator,
+	}
+
+	kioskGroup := router.Group("/kiosks")
+
+	// Called by the kiosk device itself
+	kioskGroup.Get("/manifest",
+		middleware.RequireKioskDevice(),
+		handler.getManifest(),
+	)
+
+	kioskGroup.Post("/sync",
+		middleware.RequireKioskDevice(),
+		handler.sync(),
+	)
+
+	kioskGroup.Post("",
+		middleware.RequireAuthenticated(),
+		middleware.RequireOneOfRoles(enum.RoleUser, enum.RoleEventCoordinator),
+		handler.createDevice(),
+	)
+
+	kioskGroup.Get("/conferences/:id",
+		middleware.RequireAuthenticated(),
+		middleware.RequireOneOfRoles(enum.RoleUser, enum.RoleEventCoordinator),
+		handler.getDevicesByConference(),
+	)
+
+	kioskGroup.Delete("/:id",
+		middleware.RequireAuthenticated(),
+		middleware.RequireOneOfRoles(enum.RoleUser, enum.RoleEventCoordinator),
+		handler.revokeDevice(),
+	)
+}
+
+func (h *kioskHandler) createDevice() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req dto.CreateKioskDeviceRequest
+		if err := c.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := h.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		resp, err := h.svc.CreateDevice(c.Context(), &req)
+		if err != nil {
+			return err
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(map[string]interface{}{
+			"kiosk": resp,
+		})
+	}
+}
+
+func (h *kioskHandler) getDevicesByConference() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		devices, err := h.svc.GetDevicesByConference(c.Context(), conferenceID)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"devices": devices,
+		})
+	}
+}
+
+func (h *kioskHandler) revokeDevice() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = h.svc.RevokeDevice(c.Context(), id); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *kioskHandler) getManifest() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		deviceID, _ := c.Locals("kiosk.id").(uuid.UUID)
+
+		manifest, err := h.svc.GetManifest(c.Context(), deviceID)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"manifest": manifest,
+		})
+	}
+}
+
+func (h *kioskHandler) sync() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req dto.KioskSyncRequest
+		if err := c.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := h.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		deviceID, _ := c.Locals("kiosk.id").(uuid.UUID)
+
+		results, err := h.svc.Sync(c.Context(), deviceID, &req)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"results": results,
+		})
+	}
+}
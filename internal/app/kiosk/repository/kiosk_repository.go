@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+)
+
+type kioskRepository struct {
+	db *sqlx.DB
+}
+
+func NewKioskRepository(db *sqlx.DB) contract.IKioskRepository {
+	return &kioskRepository{
+		db: db,
+	}
+}
+
+func (r *kioskRepository) CreateDevice(ctx context.Context, device *entity.KioskDevice) error {
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO kiosk_devices (id, conference_id, name, created_by)
+		VALUES (:id, :conference_id, :name, :created_by)`, device)
+	return err
+}
+
+func (r *kioskRepository) GetDeviceByID(ctx context.Context, id uuid.UUID) (*entity.KioskDevice, error) {
+	var device entity.KioskDevice
+
+	err := r.db.GetContext(ctx, &device, `
+		SELECT id, conference_id, name, created_by, created_at, last_synced_at, revoked_at
+		FROM kiosk_devices
+		WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &device, nil
+}
+
+func (r *kioskRepository) GetDevicesByConference(ctx context.Context,
+	conferenceID uuid.UUID) ([]entity.KioskDevice, error) {
+
+	var devices []entity.KioskDevice
+
+	err := r.db.SelectContext(ctx, &devices, `
+		SELECT id, conference_id, name, created_by, created_at, last_synced_at, revoked_at
+		FROM kiosk_devices
+		WHERE conference_id = $1
+		ORDER BY created_at`, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+func (r *kioskRepository) RevokeDevice(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE kiosk_devices
+		SET revoked_at = now()
+		WHERE id = $1
+		AND revoked_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *kioskRepository) MarkDeviceSynced(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE kiosk_devices
+		SET last_synced_at = now()
+		WHERE id = $1`, id)
+	return err
+}
+
+func (r *kioskRepository) GetManifestRegistrations(ctx context.Context,
+	conferenceID uuid.UUID) ([]entity.Registration, error) {
+
+	rows, err := r.db.QueryxContext(ctx, `
+		SELECT r.user_id, r.ticket_id, r.checked_in_at, u.name
+		FROM registrations r
+		JOIN users u ON r.user_id = u.id
+		WHERE r.conference_id = $1
+		AND r.cancelled_at IS NULL
+		AND u.deleted_at IS NULL
+		ORDER BY u.name, r.user_id`, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var registrations []entity.Registration
+	for rows.Next() {
+		registration := entity.Registration{
+			ConferenceID: conferenceID,
+			User:         &entity.User{},
+		}
+		if err = rows.Scan(&registration.UserID, &registration.TicketID, &registration.CheckedInAt,
+			&registration.User.Name); err != nil {
+			return nil, err
+		}
+		registration.User.ID = registration.UserID
+		registrations = append(registrations, registration)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return registrations, nil
+}
+
+// resolveScan checks the attendee in and returns the outcome of a scan with a verified ticket
+func (r *kioskRepository) resolveScan(ctx context.Context, tx sqlx.ExtContext, scan *entity.KioskScan,
+	conferenceID uuid.UUID) (enum.KioskScanResult, error) {
+
+	// An earlier scan takes over a later check-in. Ties go to the lower device ID, and an online check-in
+	// (no device) is never taken over on a tie.
+	res, err := tx.ExecContext(ctx, `
+		UPDATE registrations
+		SET checked_in_at = $1,
+			checked_in_by = NULL,
+			checked_in_device_id = $2
+		WHERE conference_id = $3
+		AND user_id = $4
+		AND ticket_id = $5
+		AND cancelled_at IS NULL
+		AND (
+			checked_in_at IS NULL
+			OR checked_in_at > $1
+			OR (checked_in_at = $1 AND checked_in_device_id > $2)
+		)`, scan.ScannedAt, scan.DeviceID, conferenceID, scan.UserID, scan.TicketID)
+	if err != nil {
+		return "", err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+
+	if rowsAffected > 0 {
+		return enum.KioskScanCheckedIn, nil
+	}
+
+	var exists bool
+	if err = sqlx.GetContext(ctx, tx, &exists, `
+		SELECT EXISTS (
+			SELECT 1 FROM registrations
+			WHERE conference_id = $1
+			AND user_id = $2
+			AND ticket_id = $3
+			AND cancelled_at IS NULL
+		)`, conferenceID, scan.UserID, scan.TicketID); err != nil {
+		return "", err
+	}
+
+	if exists {
+		return enum.KioskScanDuplicate, nil
+	}
+
+	return enum.KioskScanRevoked, nil
+}
+
+func (r *kioskRepository) RecordScan(ctx context.Context, scan *entity.KioskScan, conferenceID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if scan.Result == "" {
+		scan.Result, err = r.resolveScan(ctx, tx, scan, conferenceID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.QueryRowxContext(ctx, `
+		INSERT INTO kiosk_scans (device_id, scan_id, ticket_id, user_id, scanned_at, result)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (device_id, scan_id) DO NOTHING
+		RETURNING synced_at`,
+		scan.DeviceID, scan.ScanID, scan.TicketID, scan.UserID, scan.ScannedAt, scan.Result,
+	).Scan(&scan.SyncedAt)
+	if err == nil {
+		return tx.Commit()
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	// Already uploaded before, roll back whatever this attempt changed and answer as the first time
+	if err = tx.Rollback(); err != nil {
+		return err
+	}
+
+	return r.db.GetContext(ctx, scan, `
+		SELECT device_id, scan_id, ticket_id, user_id, scanned_at, result, synced_at
+		FROM kiosk_scans
+		WHERE device_id = $1
+		AND scan_id = $2`, scan.DeviceID, scan.ScanID)
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/jwt"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/log"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/uuidpkg"
+)
+
+type kioskService struct {
+	r             contract.IKioskRepository
+	conferenceSvc contract.IConferenceService
+	kiosk         jwt.IKiosk
+	ticket        jwt.ITicket
+	uuid          uuidpkg.IUUID
+}
+
+func NewKioskService(
+	kioskRepo contract.IKioskRepository,
+	conferenceSvc contract.IConferenceService,
+	kiosk jwt.IKiosk,
+	ticket jwt.ITicket,
+	uuid uuidpkg.IUUID,
+) contract.IKioskService {
+
+	return &kioskService{
+		r:             kioskRepo,
+		conferenceSvc: conferenceSvc,
+		kiosk:         kiosk,
+		ticket:        ticket,
+		uuid:          uuid,
+	}
+}
+
+func (s *kioskService) CreateDevice(ctx context.Context,
+	req *dto.CreateKioskDeviceRequest) (dto.CreateKioskDeviceResponse, error) {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	conference, err := s.conferenceSvc.GetConferenceForStaff(ctx, req.ConferenceID)
+	if err != nil {
+		return dto.CreateKioskDeviceResponse{}, err
+	}
+
+	if conference.Status != enum.ConferenceApproved {
+		return dto.CreateKioskDeviceResponse{}, errorpkg.ErrKioskNotApprovedConference
+	}
+
+	deviceID, err := s.uuid.NewV7()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"request":      req,
+			"requester.id": requesterID,
+		}, "[KioskService][CreateDevice] Failed to generate device ID")
+		return dto.CreateKioskDeviceResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	device := entity.KioskDevice{
+		ID:           deviceID,
+		ConferenceID: req.ConferenceID,
+		Name:         req.Name,
+		CreatedBy:    &requesterID,
+		CreatedAt:    time.Now(),
+	}
+
+	token, err := s.kiosk.CreateDeviceToken(deviceID, req.ConferenceID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"request":      req,
+			"requester.id": requesterID,
+		}, "[KioskService][CreateDevice] Failed to create device token")
+		return dto.CreateKioskDeviceResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if err = s.r.CreateDevice(ctx, &device); err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"device":       device,
+			"requester.id": requesterID,
+		}, "[KioskService][CreateDevice] Failed to create device")
+		return dto.CreateKioskDeviceResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"device":       device,
+		"requester.id": requesterID,
+	}, "[KioskService][CreateDevice] Kiosk device registered")
+
+	resp := dto.CreateKioskDeviceResponse{
+		Token:             token,
+		ManifestPublicKey: base64.StdEncoding.EncodeToString(s.kiosk.ManifestPublicKey()),
+	}
+	resp.Device.PopulateFromEntity(&device)
+
+	return resp, nil
+}
+
+func (s *kioskService) GetDevicesByConference(ctx context.Context,
+	conferenceID uuid.UUID) ([]dto.KioskDeviceResponse, error) {
+
+	if _, err := s.conferenceSvc.GetConferenceForStaff(ctx, conferenceID); err != nil {
+		return nil, err
+	}
+
+	devices, err := s.r.GetDevicesByConference(ctx, conferenceID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": conferenceID,
+			"requester.id":  ctx.Value("user.id"),
+		}, "[KioskService][GetDevicesByConference] Failed to get devices")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	resp := make([]dto.KioskDeviceResponse, len(devices))
+	for i := range devices {
+		resp[i].PopulateFromEntity(&devices[i])
+	}
+
+	return resp, nil
+}
+
+func (s *kioskService) RevokeDevice(ctx context.Context, id uuid.UUID) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	device, err := s.r.GetDeviceByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"device.id":    id,
+			"requester.id": requesterID,
+		}, "[KioskService][RevokeDevice] Failed to get device")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if _, err = s.conferenceSvc.GetConferenceForStaff(ctx, device.ConferenceID); err != nil {
+		return err
+	}
+
+	if err = s.r.RevokeDevice(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"device.id":    id,
+			"requester.id": requesterID,
+		}, "[KioskService][RevokeDevice] Failed to revoke device")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"device":       device,
+		"requester.id": requesterID,
+	}, "[KioskService][RevokeDevice] Kiosk device revoked")
+
+	return nil
+}
+
+func (s *kioskService) getActiveDevice(ctx context.Context, deviceID uuid.UUID) (*entity.KioskDevice, error) {
+	device, err := s.r.GetDeviceByID(ctx, deviceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorpkg.ErrKioskDeviceRevoked
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":     err,
+			"device.id": deviceID,
+		}, "[KioskService][getActiveDevice] Failed to get device")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if device.RevokedAt != nil {
+		return nil, errorpkg.ErrKioskDeviceRevoked
+	}
+
+	return device, nil
+}
+
+func hashTicketID(ticketID uuid.UUID) string {
+	sum := sha256.Sum256([]byte(ticketID.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *kioskService) GetManifest(ctx context.Context, deviceID uuid.UUID) (dto.KioskManifestResponse, error) {
+	device, err := s.getActiveDevice(ctx, deviceID)
+	if err != nil {
+		return dto.KioskManifestResponse{}, err
+	}
+
+	registrations, err := s.r.GetManifestRegistrations(ctx, device.ConferenceID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":  err,
+			"device": device,
+		}, "[KioskService][GetManifest] Failed to get registrations")
+		return dto.KioskManifestResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	claims := jwt.KioskManifestClaims{
+		ConferenceID: device.ConferenceID,
+		Attendees:    make([]jwt.KioskManifestAttendee, len(registrations)),
+	}
+	claims.Subject = device.ID.String()
+	for i, registration := range registrations {
+		claims.Attendees[i] = jwt.KioskManifestAttendee{
+			UserID:      registration.UserID,
+			Name:        registration.User.Name,
+			TicketHash:  hashTicketID(registration.TicketID),
+			CheckedInAt: registration.CheckedInAt,
+		}
+	}
+
+	manifest, err := s.kiosk.SignManifest(&claims)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":  err,
+			"device": device,
+		}, "[KioskService][GetManifest] Failed to sign manifest")
+		return dto.KioskManifestResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	return dto.KioskManifestResponse{
+		ConferenceID: device.ConferenceID,
+		Attendees:    len(registrations),
+		Manifest:     manifest,
+	}, nil
+}
+
+// newKioskScan verifies the ticket of an uploaded scan. Scans that fail are stored with their result right away.
+func (s *kioskService) newKioskScan(device *entity.KioskDevice, req *dto.KioskScanRequest) entity.KioskScan {
+	scan := entity.KioskScan{
+		DeviceID:  device.ID,
+		ScanID:    req.ScanID,
+		ScannedAt: req.ScannedAt.UTC(),
+	}
+
+	// Device clocks drift, but a check-in can't be recorded in the future
+	if now := time.Now().UTC(); scan.ScannedAt.After(now) {
+		scan.ScannedAt = now
+	}
+
+	var claims jwt.TicketClaims
+	if err := s.ticket.DecodeTicket(req.Ticket, &claims); err != nil {
+		scan.Result = enum.KioskScanInvalid
+		return scan
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	ticketID, err2 := uuid.Parse(claims.ID)
+	if err != nil || err2 != nil {
+		scan.Result = enum.KioskScanInvalid
+		return scan
+	}
+
+	scan.UserID = &userID
+	scan.TicketID = &ticketID
+
+	if claims.ConferenceID != device.ConferenceID {
+		scan.Result = enum.KioskScanForeign
+	}
+
+	return scan
+}
+
+func (s *kioskService) Sync(ctx context.Context, deviceID uuid.UUID,
+	req *dto.KioskSyncRequest) ([]dto.KioskScanResultResponse, error) {
+
+	device, err := s.getActiveDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Oldest first, so the scan that actually let someone in is the one recorded as the check-in
+	scans := make([]dto.KioskScanRequest, len(req.Scans))
+	copy(scans, req.Scans)
+	sort.SliceStable(scans, func(i, j int) bool {
+		return scans[i].ScannedAt.Before(scans[j].ScannedAt)
+	})
+
+	resp := make([]dto.KioskScanResultResponse, len(scans))
+	for i := range scans {
+		scan := s.newKioskScan(device, &scans[i])
+
+		if err = s.r.RecordScan(ctx, &scan, device.ConferenceID); err != nil {
+			traceID := log.ErrorWithTraceID(map[string]interface{}{
+				"error":  err,
+				"device": device,
+				"scan":   scan,
+			}, "[KioskService][Sync] Failed to record scan")
+			return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+		}
+
+		resp[i] = dto.KioskScanResultResponse{
+			ScanID: scan.ScanID,
+			UserID: scan.UserID,
+			Result: scan.Result,
+		}
+	}
+
+	if err = s.r.MarkDeviceSynced(ctx, device.ID); err != nil {
+		log.Error(map[string]interface{}{
+			"error":  err,
+			"device": device,
+		}, "[KioskService][Sync] Failed to mark device as synced")
+	}
+
+	log.Info(map[string]interface{}{
+		"device": device,
+		"scans":  len(scans),
+	}, "[KioskService][Sync] Kiosk scans synced")
+
+	return resp, nil
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
+	"github.com/nathakusuma/auditorium-reservation-backend/internal/middleware"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/validator"
+)
+
+type mfaHandler struct {
+	svc contract.IMFAService
+	val validator.IValidator
+}
+
+func InitMFAHandler(
+	router fiber.Router,
+	middleware *middleware.Middleware,
+	validator validator.IValidator,
+	mfaService contract.IMFAService,
+) {
+	handler := mfaHandler{
+		svc: mfaService,
+		val: validator,
+	}
+
+	mfaGroup := router.Group("/auth/mfa")
+	mfaGroup.Use(middleware.RequireAuthenticated())
+
+	mfaGroup.Get("", handler.getStatus())
+	mfaGroup.Post("/enroll", handler.startEnrollment())
+	mfaGroup.Post("/enroll/confirm", handler.confirmEnrollment())
+	mfaGroup.Post("/recovery-codes", handler.regenerateRecoveryCodes())
+	mfaGroup.Post("/disable", handler.disable())
+
+	mfaGroup.Delete("/users/:id",
+		middleware.RequireOneOfRoles(enum.RoleAdmin),
+		handler.reset(),
+	)
+}
+
+func (h *mfaHandler) getStatus() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		status, err := h.svc.GetStatus(c.Context())
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"mfa": status,
+		})
+	}
+}
+
+func (h *mfaHandler) startEnrollment() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, _ := c.Locals("user.id").(uuid.UUID)
+
+		enrollment, err := h.svc.StartEnrollment(c.Context(), userID)
+		if err != nil {
+			return err
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(map[string]interface{}{
+			"enrollment": enrollment,
+		})
+	}
+}
+
+func (h *mfaHandler) confirmEnrollment() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req dto.ConfirmMFAEnrollmentRequest
+		if err := c.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := h.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		userID, _ := c.Locals("user.id").(uuid.UUID)
+
+		codes, err := h.svc.ConfirmEnrollment(c.Context(), userID, req.Code)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"recovery_codes": codes,
+		})
+	}
+}
+
+func (h *mfaHandler) regenerateRecoveryCodes() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req dto.VerifyMFARequest
+		if err := c.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := h.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		codes, err := h.svc.RegenerateRecoveryCodes(c.Context(), req)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"recovery_codes": codes,
+		})
+	}
+}
+
+func (h *mfaHandler) disable() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req dto.VerifyMFARequest
+		if err := c.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := h.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		if err := h.svc.Disable(c.Context(), req); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *mfaHandler) reset() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = h.svc.Reset(c.Context(), userID); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
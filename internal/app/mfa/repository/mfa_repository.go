@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+)
+
+type mfaRepository struct {
+	db *sqlx.DB
+}
+
+func NewMFARepository(db *sqlx.DB) contract.IMFARepository {
+	return &mfaRepository{
+		db: db,
+	}
+}
+
+func (r *mfaRepository) GetMFA(ctx context.Context, userID uuid.UUID) (*entity.UserMFA, error) {
+	var mfa entity.UserMFA
+
+	err := r.db.GetContext(ctx, &mfa, `
+		SELECT user_id, secret_encrypted, enabled_at, last_used_step, created_at
+		FROM user_mfa
+		WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mfa, nil
+}
+
+func (r *mfaRepository) SavePendingMFA(ctx context.Context, mfa *entity.UserMFA) error {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_mfa (user_id, secret_encrypted, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret_encrypted = excluded.secret_encrypted,
+			last_used_step = NULL,
+			created_at = excluded.created_at
+		WHERE user_mfa.enabled_at IS NULL`,
+		mfa.UserID, mfa.SecretEncrypted, mfa.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *mfaRepository) EnableMFA(ctx context.Context, userID uuid.UUID, step int64,
+	recoveryCodeHashes []string) error {
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE user_mfa
+		SET enabled_at = now(),
+			last_used_step = $2
+		WHERE user_id = $1
+		AND enabled_at IS NULL`, userID, step)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err = r.replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *mfaRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE user_mfa
+		SET last_used_step = $2
+		WHERE user_id = $1
+		AND enabled_at IS NOT NULL
+		AND (last_used_step IS NULL OR last_used_step < $2)`, userID, step)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE user_mfa_recovery_codes
+		SET used_at = now()
+		WHERE user_id = $1
+		AND code_hash = $2
+		AND used_at IS NULL`, userID, codeHash)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *mfaRepository) replaceRecoveryCodes(ctx context.Context, tx sqlx.ExtContext, userID uuid.UUID,
+	codeHashes []string) error {
+
+	_, err := tx.ExecContext(ctx, `DELETE FROM user_mfa_recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, codeHash := range codeHashes {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO user_mfa_recovery_codes (user_id, code_hash)
+			VALUES ($1, $2)`, userID, codeHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = r.replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *mfaRepository) CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+
+	err := r.db.GetContext(ctx, &count, `
+		SELECT COUNT(*)
+		FROM user_mfa_recovery_codes
+		WHERE user_id = $1
+		AND used_at IS NULL`, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *mfaRepository) DeleteMFA(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM user_mfa_recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/log"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/totp"
+)
+
+const recoveryCodeCount = 10
+
+type mfaService struct {
+	repo    contract.IMFARepository
+	userSvc contract.IUserService
+	totp    totp.ITOTP
+}
+
+func NewMFAService(
+	mfaRepo contract.IMFARepository,
+	userSvc contract.IUserService,
+	totp totp.ITOTP,
+) contract.IMFAService {
+	return &mfaService{
+		repo:    mfaRepo,
+		userSvc: userSvc,
+		totp:    totp,
+	}
+}
+
+func (s *mfaService) GetStatus(ctx context.Context) (dto.MFAStatusResponse, error) {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+	requesterRole, _ := ctx.Value("user.role").(enum.UserRole)
+
+	resp := dto.MFAStatusResponse{
+		Required: requesterRole.RequiresMFA(),
+	}
+
+	mfa, err := s.repo.GetMFA(ctx, requesterID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return resp, nil
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"requester.id": requesterID,
+		}, "[MFAService][GetStatus] failed to get mfa")
+		return resp, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if mfa.EnabledAt == nil {
+		return resp, nil
+	}
+
+	resp.Enabled = true
+	resp.EnabledAt = mfa.EnabledAt
+
+	resp.RemainingRecoveryCodes, err = s.repo.CountUnusedRecoveryCodes(ctx, requesterID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"requester.id": requesterID,
+		}, "[MFAService][GetStatus] failed to count recovery codes")
+		return resp, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	return resp, nil
+}
+
+func (s *mfaService) IsEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	mfa, err := s.repo.GetMFA(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":   err.Error(),
+			"user.id": userID,
+		}, "[MFAService][IsEnabled] failed to get mfa")
+		return false, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	return mfa.EnabledAt != nil, nil
+}
+
+func (s *mfaService) StartEnrollment(ctx context.Context, userID uuid.UUID) (dto.MFAEnrollmentResponse, error) {
+	var resp dto.MFAEnrollmentResponse
+
+	user, err := s.userSvc.GetUserByID(ctx, userID)
+	if err != nil {
+		return resp, err
+	}
+
+	secret, err := s.totp.GenerateSecret()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":   err.Error(),
+			"user.id": userID,
+		}, "[MFAService][StartEnrollment] failed to generate secret")
+		return resp, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	secretEncrypted, err := s.totp.EncryptSecret(secret)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":   err.Error(),
+			"user.id": userID,
+		}, "[MFAService][StartEnrollment] failed to encrypt secret")
+		return resp, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	err = s.repo.SavePendingMFA(ctx, &entity.UserMFA{
+		UserID:          userID,
+		SecretEncrypted: secretEncrypted,
+		CreatedAt:       time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return resp, errorpkg.ErrMFAAlreadyEnabled
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":   err.Error(),
+			"user.id": userID,
+		}, "[MFAService][StartEnrollment] failed to save pending mfa")
+		return resp, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"user.id": userID,
+	}, "[MFAService][StartEnrollment] mfa enrollment started")
+
+	resp.Secret = secret
+	resp.ProvisioningURI = s.totp.ProvisioningURI(user.Email, secret)
+
+	return resp, nil
+}
+
+func (s *mfaService) ConfirmEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	mfa, err := s.repo.GetMFA(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorpkg.ErrMFANotEnabled.WithMessage("Please start the enrollment first.")
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":   err.Error(),
+			"user.id": userID,
+		}, "[MFAService][ConfirmEnrollment] failed to get mfa")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if mfa.EnabledAt != nil {
+		return nil, errorpkg.ErrMFAAlreadyEnabled
+	}
+
+	step, err := s.validateCode(mfa, code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, codeHashes, err := generateRecoveryCodes()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":   err.Error(),
+			"user.id": userID,
+		}, "[MFAService][ConfirmEnrollment] failed to generate recovery codes")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if err = s.repo.EnableMFA(ctx, userID, step, codeHashes); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// confirmed concurrently
+			return nil, errorpkg.ErrMFAAlreadyEnabled
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":   err.Error(),
+			"user.id": userID,
+		}, "[MFAService][ConfirmEnrollment] failed to enable mfa")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"user.id": userID,
+	}, "[MFAService][ConfirmEnrollment] mfa enabled")
+
+	return codes, nil
+}
+
+func (s *mfaService) Verify(ctx context.Context, userID uuid.UUID, req dto.VerifyMFARequest) error {
+	mfa, err := s.repo.GetMFA(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrMFANotEnabled
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":   err.Error(),
+			"user.id": userID,
+		}, "[MFAService][Verify] failed to get mfa")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if mfa.EnabledAt == nil {
+		return errorpkg.ErrMFANotEnabled
+	}
+
+	if req.Code == "" {
+		return s.useRecoveryCode(ctx, userID, req.RecoveryCode)
+	}
+
+	step, err := s.validateCode(mfa, req.Code)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.UseStep(ctx, userID, step); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// the code was already used, maybe by someone looking over the user's shoulder
+			return errorpkg.ErrInvalidMFACode
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":   err.Error(),
+			"user.id": userID,
+		}, "[MFAService][Verify] failed to use step")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	return nil
+}
+
+func (s *mfaService) useRecoveryCode(ctx context.Context, userID uuid.UUID, code string) error {
+	err := s.repo.UseRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrInvalidMFACode
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":   err.Error(),
+			"user.id": userID,
+		}, "[MFAService][useRecoveryCode] failed to use recovery code")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"user.id": userID,
+	}, "[MFAService][useRecoveryCode] recovery code used")
+
+	return nil
+}
+
+// validateCode checks the code against the user's secret and returns the time step it belongs to
+func (s *mfaService) validateCode(mfa *entity.UserMFA, code string) (int64, error) {
+	secret, err := s.totp.DecryptSecret(mfa.SecretEncrypted)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":   err.Error(),
+			"user.id": mfa.UserID,
+		}, "[MFAService][validateCode] failed to decrypt secret")
+		return 0, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	step, ok := s.totp.Validate(secret, code)
+	if !ok {
+		return 0, errorpkg.ErrInvalidMFACode
+	}
+
+	return step, nil
+}
+
+func (s *mfaService) RegenerateRecoveryCodes(ctx context.Context, req dto.VerifyMFARequest) ([]string, error) {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	if err := s.Verify(ctx, requesterID, req); err != nil {
+		return nil, err
+	}
+
+	codes, codeHashes, err := generateRecoveryCodes()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"requester.id": requesterID,
+		}, "[MFAService][RegenerateRecoveryCodes] failed to generate recovery codes")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if err = s.repo.ReplaceRecoveryCodes(ctx, requesterID, codeHashes); err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"requester.id": requesterID,
+		}, "[MFAService][RegenerateRecoveryCodes] failed to replace recovery codes")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"requester.id": requesterID,
+	}, "[MFAService][RegenerateRecoveryCodes] recovery codes regenerated")
+
+	return codes, nil
+}
+
+func (s *mfaService) Disable(ctx context.Context, req dto.VerifyMFARequest) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+	requesterRole, _ := ctx.Value("user.role").(enum.UserRole)
+
+	if requesterRole.RequiresMFA() {
+		return errorpkg.ErrMFAMandatory
+	}
+
+	if err := s.Verify(ctx, requesterID, req); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteMFA(ctx, requesterID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"requester.id": requesterID,
+		}, "[MFAService][Disable] failed to delete mfa")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"requester.id": requesterID,
+	}, "[MFAService][Disable] mfa disabled")
+
+	return nil
+}
+
+func (s *mfaService) Reset(ctx context.Context, userID uuid.UUID) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	if err := s.repo.DeleteMFA(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrMFANotEnabled
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"user.id":      userID,
+			"requester.id": requesterID,
+		}, "[MFAService][Reset] failed to delete mfa")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Warn(map[string]interface{}{
+		"security.event": "mfa_reset",
+		"user.id":        userID,
+		"requester.id":   requesterID,
+	}, "[MFAService][Reset] mfa reset by admin")
+
+	return nil
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateRecoveryCodes returns the codes to show the user once, and the hashes to store.
+// Each code has 80 random bits, so a plain SHA-256 is enough to store them.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	codeHashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := cryptorand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+		codes[i] = code[:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:]
+		codeHashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	return codes, codeHashes, nil
+}
+
+// hashRecoveryCode ignores case, dashes and spaces, which are easy to get wrong when typing a code
+func hashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
@@ -39,9 +39,53 @@ func InitRegistrationHandler(
 		handler.getRegisteredUsersByConference(),
 	)
 
+	registrationGroup.Delete("/conferences/:id",
+		middleware.RequireOneOfRoles(enum.RoleUser),
+		handler.cancelOwnRegistration(),
+	)
+
+	registrationGroup.Delete("/conferences/:id/users/:userId",
+		middleware.RequireOneOfRoles(enum.RoleEventCoordinator),
+		handler.cancelRegistration(),
+	)
+
 	registrationGroup.Get("/users/:id",
 		handler.getRegisteredConferencesByUser(),
 	)
+
+	registrationGroup.Post("/conferences/:id/waitlist",
+		middleware.RequireOneOfRoles(enum.RoleUser),
+		handler.joinWaitlist(),
+	)
+
+	registrationGroup.Get("/conferences/:id/waitlist",
+		middleware.RequireOneOfRoles(enum.RoleUser),
+		handler.getWaitlistEntry(),
+	)
+
+	registrationGroup.Delete("/conferences/:id/waitlist",
+		middleware.RequireOneOfRoles(enum.RoleUser),
+		handler.leaveWaitlist(),
+	)
+
+	registrationGroup.Post("/conferences/:id/waitlist/confirm",
+		middleware.RequireOneOfRoles(enum.RoleUser),
+		handler.confirmWaitlistOffer(),
+	)
+
+	registrationGroup.Post("/conferences/:id/reconfirm",
+		middleware.RequireOneOfRoles(enum.RoleUser),
+		handler.reconfirmRegistration(),
+	)
+
+	registrationGroup.Post("/conferences/:id/check-in",
+		middleware.RequireOneOfRoles(enum.RoleUser, enum.RoleEventCoordinator),
+		handler.checkIn(),
+	)
+
+	registrationGroup.Get("/conferences/:id/attendance",
+		handler.getAttendance(),
+	)
 }
 
 func (h *registrationHandler) register() fiber.Handler {
@@ -64,6 +108,43 @@ func (h *registrationHandler) register() fiber.Handler {
 	}
 }
 
+func (h *registrationHandler) cancelOwnRegistration() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		userID, _ := c.Locals("user.id").(uuid.UUID)
+
+		if err = h.svc.CancelRegistration(c.Context(), conferenceID, userID); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *registrationHandler) cancelRegistration() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		userID, err := uuid.Parse(c.Params("userId"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = h.svc.CancelRegistration(c.Context(), conferenceID, userID); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
 func (h *registrationHandler) getRegisteredUsersByConference() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		conferenceID, err := uuid.Parse(c.Params("id"))
@@ -121,3 +202,142 @@ func (h *registrationHandler) getRegisteredConferencesByUser() fiber.Handler {
 		})
 	}
 }
+
+func (h *registrationHandler) joinWaitlist() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		userID, _ := c.Locals("user.id").(uuid.UUID)
+
+		entry, err := h.svc.JoinWaitlist(c.Context(), conferenceID, userID)
+		if err != nil {
+			return err
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(map[string]interface{}{
+			"waitlist_entry": entry,
+		})
+	}
+}
+
+func (h *registrationHandler) getWaitlistEntry() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		userID, _ := c.Locals("user.id").(uuid.UUID)
+
+		entry, err := h.svc.GetWaitlistEntry(c.Context(), conferenceID, userID)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"waitlist_entry": entry,
+		})
+	}
+}
+
+func (h *registrationHandler) leaveWaitlist() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		userID, _ := c.Locals("user.id").(uuid.UUID)
+
+		if err = h.svc.LeaveWaitlist(c.Context(), conferenceID, userID); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *registrationHandler) confirmWaitlistOffer() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		userID, _ := c.Locals("user.id").(uuid.UUID)
+
+		if err = h.svc.ConfirmWaitlistOffer(c.Context(), conferenceID, userID); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *registrationHandler) reconfirmRegistration() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		userID, _ := c.Locals("user.id").(uuid.UUID)
+
+		if err = h.svc.ReconfirmRegistration(c.Context(), conferenceID, userID); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *registrationHandler) checkIn() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var request struct {
+			Ticket string `json:"ticket" validate:"required"`
+		}
+
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = c.BodyParser(&request); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = h.val.ValidateStruct(request); err != nil {
+			return err
+		}
+
+		resp, err := h.svc.CheckIn(c.Context(), conferenceID, request.Ticket)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"check_in": resp,
+		})
+	}
+}
+
+func (h *registrationHandler) getAttendance() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		attendance, err := h.svc.GetAttendance(c.Context(), conferenceID)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"attendance": attendance,
+		})
+	}
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
 )
 
 type registrationRepository struct {
@@ -25,25 +27,106 @@ func NewRegistrationRepository(db *sqlx.DB) contract.IRegistrationRepository {
 func (r *registrationRepository) createRegistration(ctx context.Context, tx sqlx.ExtContext,
 	registration *entity.Registration) error {
 
-	_, err := sqlx.NamedExecContext(
+	res, err := sqlx.NamedExecContext(
 		ctx,
 		tx,
 		`INSERT INTO registrations (
 			conference_id, user_id
 		) VALUES (
 			:conference_id, :user_id
-		)`,
+		)
+		ON CONFLICT (user_id, conference_id) DO UPDATE
+			SET created_at = now(), cancelled_at = NULL, cancelled_by = NULL, reconfirm_by = NULL,
+				ticket_id = gen_random_uuid(), checked_in_at = NULL, checked_in_by = NULL
+			WHERE registrations.cancelled_at IS NOT NULL`,
 		registration,
 	)
 	if err != nil {
 		return err
 	}
 
+	// Nothing is written when an active registration already exists
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
 	return nil
 }
 
-func (r *registrationRepository) CreateRegistration(ctx context.Context, registration *entity.Registration) error {
-	return r.createRegistration(ctx, r.db, registration)
+// lockConferenceSeats locks the conference row until tx ends, serializing every seat allocation for that conference
+func (r *registrationRepository) lockConferenceSeats(ctx context.Context, tx sqlx.ExtContext,
+	conferenceID uuid.UUID) (int, error) {
+
+	var seats int
+	if err := sqlx.GetContext(
+		ctx,
+		tx,
+		&seats,
+		`SELECT seats FROM conferences WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`,
+		conferenceID,
+	); err != nil {
+		return 0, err
+	}
+
+	return seats, nil
+}
+
+func (r *registrationRepository) CreateRegistrationWithinCapacity(ctx context.Context,
+	registration *entity.Registration) (bool, error) {
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	seats, err := r.lockConferenceSeats(ctx, tx, registration.ConferenceID)
+	if err != nil {
+		return false, err
+	}
+
+	taken, err := r.countRegistrationsByConference(ctx, tx, registration.ConferenceID)
+	if err != nil {
+		return false, err
+	}
+
+	// Seats offered to other people on the waitlist stay reserved for them
+	offered, err := r.countActiveWaitlistOffers(ctx, tx, registration.ConferenceID, registration.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	if taken+offered >= seats {
+		return false, nil
+	}
+
+	if err = r.createRegistration(ctx, tx, registration); err != nil {
+		return false, err
+	}
+
+	// The user holds a seat now, so their waitlist entry is fulfilled
+	if _, err = tx.ExecContext(
+		ctx,
+		`UPDATE waitlist_entries
+			SET status = 'accepted', updated_at = now()
+			WHERE conference_id = $1
+			AND user_id = $2
+			AND status IN ('waiting', 'offered')`,
+		registration.ConferenceID, registration.UserID,
+	); err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 func (r *registrationRepository) GetRegisteredUsersByConference(ctx context.Context,
@@ -58,6 +141,7 @@ func (r *registrationRepository) GetRegisteredUsersByConference(ctx context.Cont
         WHERE id IN (
             SELECT user_id FROM registrations
             WHERE conference_id = $1
+            AND cancelled_at IS NULL
         )`
 
 	// Add pagination filters
@@ -135,9 +219,9 @@ func (r *registrationRepository) GetRegisteredUsersByConference(ctx context.Cont
 }
 
 func (r *registrationRepository) GetRegisteredConferencesByUser(ctx context.Context, userID uuid.UUID,
-	includePast bool, lazy dto.LazyLoadQuery) ([]entity.Conference, dto.LazyLoadResponse, error) {
+	includePast bool, lazy dto.LazyLoadQuery) ([]entity.Registration, dto.LazyLoadResponse, error) {
 
-	var conferences []entity.Conference
+	var registrations []entity.Registration
 	var args []interface{}
 	args = append(args, userID)
 	argCount := 1
@@ -145,11 +229,15 @@ func (r *registrationRepository) GetRegisteredConferencesByUser(ctx context.Cont
 	query := `SELECT
         c.id, c.title, c.description, c.speaker_name, c.speaker_title,
         c.target_audience, c.prerequisites, c.seats, c.starts_at, c.ends_at,
-        c.host_id, c.status, c.created_at, c.updated_at, u.name AS host_name
+        c.host_id, c.room_id, c.status, c.created_at, c.updated_at, u.name AS host_name,
+        rm.name AS room_name, rm.building AS room_building, r.created_at, r.reconfirm_by, r.ticket_id,
+        r.checked_in_at
     FROM conferences c
     JOIN users u ON c.host_id = u.id
+    JOIN rooms rm ON c.room_id = rm.id
     JOIN registrations r ON c.id = r.conference_id
-    WHERE r.user_id = $1`
+    WHERE r.user_id = $1
+    AND r.cancelled_at IS NULL`
 
 	// Add filter for past conferences
 	if !includePast {
@@ -187,17 +275,24 @@ func (r *registrationRepository) GetRegisteredConferencesByUser(ctx context.Cont
 	// Scan results
 	for rows.Next() {
 		var conf entity.Conference
+		var registration entity.Registration
 		var hostName string
 		if err := rows.Scan(
 			&conf.ID, &conf.Title, &conf.Description, &conf.SpeakerName, &conf.SpeakerTitle,
 			&conf.TargetAudience, &conf.Prerequisites, &conf.Seats, &conf.StartsAt, &conf.EndsAt,
-			&conf.HostID, &conf.Status, &conf.CreatedAt, &conf.UpdatedAt, &hostName,
+			&conf.HostID, &conf.RoomID, &conf.Status, &conf.CreatedAt, &conf.UpdatedAt, &hostName,
+			&conf.Room.Name, &conf.Room.Building, &registration.CreatedAt, &registration.ReconfirmBy,
+			&registration.TicketID, &registration.CheckedInAt,
 		); err != nil {
 			return nil, dto.LazyLoadResponse{}, fmt.Errorf("failed to scan conference: %w", err)
 		}
 		conf.Host.ID = conf.HostID
 		conf.Host.Name = hostName
-		conferences = append(conferences, conf)
+		conf.Room.ID = conf.RoomID
+		registration.UserID = userID
+		registration.ConferenceID = conf.ID
+		registration.Conference = &conf
+		registrations = append(registrations, registration)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -211,30 +306,30 @@ func (r *registrationRepository) GetRegisteredConferencesByUser(ctx context.Cont
 		LastID:  nil,
 	}
 
-	if len(conferences) > 0 {
+	if len(registrations) > 0 {
 		// Check if we got an extra record
-		if len(conferences) > lazy.Limit {
+		if len(registrations) > lazy.Limit {
 			lazyResp.HasMore = true
 			if lazy.BeforeID != uuid.Nil {
-				conferences = conferences[1:] // Remove first record when paginating backwards
+				registrations = registrations[1:] // Remove first record when paginating backwards
 			} else {
-				conferences = conferences[:lazy.Limit] // Remove last record when paginating forwards
+				registrations = registrations[:lazy.Limit] // Remove last record when paginating forwards
 			}
 		}
 
 		// For BeforeID, reverse the final result set to maintain ascending order
 		if lazy.BeforeID != uuid.Nil {
-			for i := 0; i < len(conferences)/2; i++ {
-				j := len(conferences) - 1 - i
-				conferences[i], conferences[j] = conferences[j], conferences[i]
+			for i := 0; i < len(registrations)/2; i++ {
+				j := len(registrations) - 1 - i
+				registrations[i], registrations[j] = registrations[j], registrations[i]
 			}
 		}
 
-		lazyResp.FirstID = conferences[0].ID
-		lazyResp.LastID = conferences[len(conferences)-1].ID
+		lazyResp.FirstID = registrations[0].ConferenceID
+		lazyResp.LastID = registrations[len(registrations)-1].ConferenceID
 	}
 
-	return conferences, lazyResp, nil
+	return registrations, lazyResp, nil
 }
 
 func (r *registrationRepository) IsUserRegisteredToConference(ctx context.Context, conferenceID,
@@ -248,6 +343,7 @@ func (r *registrationRepository) IsUserRegisteredToConference(ctx context.Contex
     			SELECT 1 FROM registrations
     			WHERE conference_id = $1
     			AND user_id = $2
+    			AND cancelled_at IS NULL
     		)`,
 		conferenceID, userID,
 	); err != nil {
@@ -258,7 +354,7 @@ func (r *registrationRepository) IsUserRegisteredToConference(ctx context.Contex
 }
 
 func (r *registrationRepository) GetConflictingRegistrations(ctx context.Context, userID uuid.UUID, startsAt,
-	endsAt time.Time) ([]entity.Conference, error) {
+	endsAt time.Time, excludeConferenceID uuid.UUID) ([]entity.Conference, error) {
 
 	var conferences []entity.Conference
 
@@ -271,7 +367,11 @@ func (r *registrationRepository) GetConflictingRegistrations(ctx context.Context
         FROM registrations r
         JOIN conferences c ON r.conference_id = c.id
         WHERE r.user_id = $1
+            AND r.cancelled_at IS NULL
             AND c.deleted_at IS NULL
+            -- Registrations stay active on a cancelled conference, but it no longer takes up the user's time
+            AND c.status != 'cancelled'
+            AND c.id != $4
             AND (
                 ($2 BETWEEN c.starts_at AND c.ends_at)
                 OR
@@ -280,20 +380,22 @@ func (r *registrationRepository) GetConflictingRegistrations(ctx context.Context
                 (c.starts_at BETWEEN $2 AND $3)
             )`
 
-	if err := r.db.SelectContext(ctx, &conferences, query, userID, startsAt, endsAt); err != nil {
+	if err := r.db.SelectContext(ctx, &conferences, query, userID, startsAt, endsAt, excludeConferenceID); err != nil {
 		return nil, err
 	}
 
 	return conferences, nil
 }
 
-func (r *registrationRepository) CountRegistrationsByConference(ctx context.Context,
+func (r *registrationRepository) countRegistrationsByConference(ctx context.Context, tx sqlx.ExtContext,
 	conferenceID uuid.UUID) (int, error) {
+
 	var count int
-	if err := r.db.GetContext(
+	if err := sqlx.GetContext(
 		ctx,
+		tx,
 		&count,
-		`SELECT COUNT(*) FROM registrations WHERE conference_id = $1`,
+		`SELECT COUNT(*) FROM registrations WHERE conference_id = $1 AND cancelled_at IS NULL`,
 		conferenceID,
 	); err != nil {
 		return 0, err
@@ -301,3 +403,311 @@ func (r *registrationRepository) CountRegistrationsByConference(ctx context.Cont
 
 	return count, nil
 }
+
+func (r *registrationRepository) CountRegistrationsByConference(ctx context.Context,
+	conferenceID uuid.UUID) (int, error) {
+
+	return r.countRegistrationsByConference(ctx, r.db, conferenceID)
+}
+
+func (r *registrationRepository) CancelRegistration(ctx context.Context, conferenceID, userID,
+	cancelledBy uuid.UUID) error {
+
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE registrations
+			SET cancelled_at = now(), cancelled_by = $3
+			WHERE conference_id = $1
+			AND user_id = $2
+			AND cancelled_at IS NULL`,
+		conferenceID, userID, cancelledBy,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *registrationRepository) GetActiveRegistration(ctx context.Context, conferenceID,
+	userID uuid.UUID) (*entity.Registration, error) {
+
+	var registration entity.Registration
+
+	err := r.db.GetContext(ctx, &registration, `
+		SELECT user_id, conference_id, created_at, cancelled_at, cancelled_by, reconfirm_by,
+			ticket_id, checked_in_at, checked_in_by
+		FROM registrations
+		WHERE conference_id = $1
+		AND user_id = $2
+		AND cancelled_at IS NULL`, conferenceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &registration, nil
+}
+
+func (r *registrationRepository) ReconfirmRegistration(ctx context.Context, conferenceID, userID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE registrations
+		SET reconfirm_by = NULL
+		WHERE conference_id = $1
+		AND user_id = $2
+		AND cancelled_at IS NULL
+		AND reconfirm_by > now()`, conferenceID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *registrationRepository) ReleaseUnconfirmedRegistrations(ctx context.Context) ([]uuid.UUID, error) {
+	var conferenceIDs []uuid.UUID
+
+	err := r.db.SelectContext(ctx, &conferenceIDs, `
+		WITH released AS (
+			UPDATE registrations
+			SET cancelled_at = now(), reconfirm_by = NULL
+			WHERE cancelled_at IS NULL
+			AND reconfirm_by <= now()
+			RETURNING conference_id
+		)
+		SELECT DISTINCT conference_id FROM released`)
+	if err != nil {
+		return nil, err
+	}
+
+	return conferenceIDs, nil
+}
+
+func (r *registrationRepository) CheckInRegistration(ctx context.Context, registration *entity.Registration) error {
+	return r.db.QueryRowxContext(ctx, `
+		UPDATE registrations
+		SET checked_in_at = now(),
+			checked_in_by = $1
+		WHERE conference_id = $2
+		AND user_id = $3
+		AND ticket_id = $4
+		AND cancelled_at IS NULL
+		AND checked_in_at IS NULL
+		RETURNING checked_in_at`,
+		registration.CheckedInBy, registration.ConferenceID, registration.UserID, registration.TicketID,
+	).Scan(&registration.CheckedInAt)
+}
+
+func (r *registrationRepository) CountAttendance(ctx context.Context,
+	conferenceID uuid.UUID) (int, int, error) {
+
+	var counts struct {
+		Registered int `db:"registered"`
+		CheckedIn  int `db:"checked_in"`
+	}
+
+	err := r.db.GetContext(ctx, &counts, `
+		SELECT COUNT(*) AS registered, COUNT(checked_in_at) AS checked_in
+		FROM registrations
+		WHERE conference_id = $1
+		AND cancelled_at IS NULL`, conferenceID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return counts.Registered, counts.CheckedIn, nil
+}
+
+func (r *registrationRepository) CreateWaitlistEntry(ctx context.Context, entry *entity.WaitlistEntry) error {
+	_, err := sqlx.NamedExecContext(
+		ctx,
+		r.db,
+		`INSERT INTO waitlist_entries (
+			id, conference_id, user_id, status
+		) VALUES (
+			:id, :conference_id, :user_id, :status
+		)`,
+		entry,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *registrationRepository) GetActiveWaitlistEntry(ctx context.Context, conferenceID,
+	userID uuid.UUID) (*entity.WaitlistEntry, error) {
+
+	var entry entity.WaitlistEntry
+	if err := r.db.GetContext(
+		ctx,
+		&entry,
+		`SELECT id, conference_id, user_id, status, offered_at, offer_expires_at, created_at, updated_at
+			FROM waitlist_entries
+			WHERE conference_id = $1
+			AND user_id = $2
+			AND status IN ('waiting', 'offered')`,
+		conferenceID, userID,
+	); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (r *registrationRepository) GetWaitlistPosition(ctx context.Context, entry *entity.WaitlistEntry) (int, error) {
+	// IDs are UUIDv7, so ordering by ID is ordering by join time
+	var position int
+	if err := r.db.GetContext(
+		ctx,
+		&position,
+		`SELECT COUNT(*) FROM waitlist_entries
+			WHERE conference_id = $1
+			AND status = 'waiting'
+			AND id <= $2`,
+		entry.ConferenceID, entry.ID,
+	); err != nil {
+		return 0, err
+	}
+
+	return position, nil
+}
+
+func (r *registrationRepository) UpdateWaitlistEntryStatus(ctx context.Context, id uuid.UUID,
+	status enum.WaitlistStatus) error {
+
+	res, err := r.db.ExecContext(
+		ctx,
+		`UPDATE waitlist_entries SET status = $1, updated_at = now() WHERE id = $2`,
+		status, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *registrationRepository) countActiveWaitlistOffers(ctx context.Context, tx sqlx.ExtContext,
+	conferenceID, excludeUserID uuid.UUID) (int, error) {
+
+	var count int
+	if err := sqlx.GetContext(
+		ctx,
+		tx,
+		&count,
+		`SELECT COUNT(*) FROM waitlist_entries
+			WHERE conference_id = $1
+			AND user_id != $2
+			AND status = 'offered'
+			AND offer_expires_at > now()`,
+		conferenceID, excludeUserID,
+	); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *registrationRepository) CountActiveWaitlistOffers(ctx context.Context, conferenceID,
+	excludeUserID uuid.UUID) (int, error) {
+
+	return r.countActiveWaitlistOffers(ctx, r.db, conferenceID, excludeUserID)
+}
+
+func (r *registrationRepository) OfferWaitlistSeats(ctx context.Context, conferenceID uuid.UUID,
+	offerExpiresAt time.Time) ([]entity.WaitlistEntry, error) {
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	seats, err := r.lockConferenceSeats(ctx, tx, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	taken, err := r.countRegistrationsByConference(ctx, tx, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	offered, err := r.countActiveWaitlistOffers(ctx, tx, conferenceID, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	free := seats - taken - offered
+	if free <= 0 {
+		return nil, nil
+	}
+
+	var entries []entity.WaitlistEntry
+	if err = tx.SelectContext(ctx, &entries,
+		`UPDATE waitlist_entries
+			SET status = 'offered', offered_at = now(), offer_expires_at = $2, updated_at = now()
+			WHERE id IN (
+				SELECT id FROM waitlist_entries
+				WHERE conference_id = $1
+				AND status = 'waiting'
+				ORDER BY id
+				LIMIT $3
+			)
+			RETURNING id, conference_id, user_id, status, offered_at, offer_expires_at, created_at, updated_at`,
+		conferenceID, offerExpiresAt, free,
+	); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *registrationRepository) ExpireWaitlistOffers(ctx context.Context) ([]entity.WaitlistEntry, error) {
+	var entries []entity.WaitlistEntry
+	if err := r.db.SelectContext(
+		ctx,
+		&entries,
+		`UPDATE waitlist_entries
+			SET status = 'expired', updated_at = now()
+			WHERE status = 'offered'
+			AND offer_expires_at <= now()
+			RETURNING id, conference_id, user_id, status, offered_at, offer_expires_at, created_at, updated_at`,
+	); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -2,27 +2,49 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
+	"github.com/nathakusuma/auditorium-reservation-backend/internal/infra/env"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/jwt"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/log"
-	"time"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/mail"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/uuidpkg"
 )
 
 type registrationService struct {
 	r             contract.IRegistrationRepository
 	conferenceSvc contract.IConferenceService
+	userSvc       contract.IUserService
+	mailer        mail.IMailer
+	ticket        jwt.ITicket
+	uuid          uuidpkg.IUUID
 }
 
-func NewRegistrationService(registrationRepository contract.IRegistrationRepository,
-	conferenceService contract.IConferenceService) contract.IRegistrationService {
+func NewRegistrationService(
+	registrationRepository contract.IRegistrationRepository,
+	conferenceService contract.IConferenceService,
+	userService contract.IUserService,
+	mailer mail.IMailer,
+	ticket jwt.ITicket,
+	uuid uuidpkg.IUUID,
+) contract.IRegistrationService {
 
 	return &registrationService{
 		r:             registrationRepository,
 		conferenceSvc: conferenceService,
+		userSvc:       userService,
+		mailer:        mailer,
+		ticket:        ticket,
+		uuid:          uuid,
 	}
 }
 
@@ -32,10 +54,14 @@ func (s *registrationService) Register(ctx context.Context, conferenceID, userID
 	if err != nil {
 		return err
 	}
-	// At this point, conference must have been approved, because it's checked in the GetConferenceByID method
+	// At this point, conference must have been approved or cancelled, because it's checked in the GetConferenceByID
+	// method
+	if conference.Status == enum.ConferenceCancelled {
+		return errorpkg.ErrConferenceCancelled
+	}
 
-	// Is user host of conference?
-	if conference.Host.ID == userID {
+	// Is user host or co-host of conference?
+	if conference.IsHostedBy(userID) {
 		return errorpkg.ErrHostCannotRegister
 	}
 
@@ -58,30 +84,140 @@ func (s *registrationService) Register(ctx context.Context, conferenceID, userID
 		return errorpkg.ErrUserAlreadyRegisteredToConference
 	}
 
-	// Is it full?
-	taken, err := s.r.CountRegistrationsByConference(ctx, conferenceID)
+	// Get conflicting registrations
+	// This is unlikely to happen. There will never be conflicting approved conferences (checked in conference service)
+	// Just to be safe
+	conflictingRegistrations, err := s.r.GetConflictingRegistrations(ctx, userID, *conference.StartsAt,
+		*conference.EndsAt, conferenceID)
 	if err != nil {
 		traceID := log.ErrorWithTraceID(map[string]interface{}{
 			"error":        err,
 			"conferenceID": conferenceID,
-		}, "[RegistrationService][Register] Failed to count registrations by conference")
+			"userID":       userID,
+		}, "[RegistrationService][Register] Failed to get conflicting registrations")
 		return errorpkg.ErrInternalServer.WithTraceID(traceID)
 	}
-	if taken >= conference.Seats {
+	if len(conflictingRegistrations) > 0 {
+		return errorpkg.ErrConflictingRegistrations.WithDetail(map[string]interface{}{
+			"conferences": conflictingRegistrations,
+		})
+	}
+
+	// Create registration. Capacity is checked again under a row lock on the conference, so concurrent
+	// requests can never take more seats than available. Seats offered to others on the waitlist stay held.
+	created, err := s.r.CreateRegistrationWithinCapacity(ctx, &entity.Registration{
+		ConferenceID: conferenceID,
+		UserID:       userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrUserAlreadyRegisteredToConference
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+			"userID":       userID,
+		}, "[RegistrationService][Register] Failed to create registration")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+	if !created {
 		return errorpkg.ErrConferenceFull
 	}
 
-	// Get conflicting registrations
-	// This is unlikely to happen. There will never be conflicting approved conferences (checked in conference service)
-	// Just to be safe
+	return nil
+}
+
+func (s *registrationService) CancelRegistration(ctx context.Context, conferenceID, userID uuid.UUID) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+	requesterRole, _ := ctx.Value("user.role").(enum.UserRole)
+
+	// Regular users may only cancel their own registration
+	if requesterRole == enum.RoleUser && requesterID != userID {
+		return errorpkg.ErrForbiddenUser
+	}
+
+	conference, err := s.conferenceSvc.GetConferenceByID(ctx, conferenceID)
+	if err != nil {
+		return err
+	}
+
+	// Attendees are bound by the cutoff, coordinators may still remove someone up until the start
+	cutoff := *conference.StartsAt
+	if requesterRole == enum.RoleUser {
+		cutoff = cutoff.Add(-env.GetEnv().RegistrationCancelCutoff)
+	}
+	if time.Now().After(cutoff) {
+		return errorpkg.ErrCancellationCutoffPassed.WithDetail(map[string]interface{}{
+			"cutoff": cutoff,
+		})
+	}
+
+	if err = s.r.CancelRegistration(ctx, conferenceID, userID, requesterID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrUserNotRegisteredToConference
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+			"userID":       userID,
+			"requester.id": requesterID,
+		}, "[RegistrationService][CancelRegistration] Failed to cancel registration")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"conferenceID": conferenceID,
+		"userID":       userID,
+		"requester.id": requesterID,
+	}, "[RegistrationService][CancelRegistration] Registration cancelled")
+
+	// The freed seat goes to the next person on the waitlist
+	return s.PromoteWaitlist(ctx, conferenceID)
+}
+
+func (s *registrationService) ReconfirmRegistration(ctx context.Context, conferenceID, userID uuid.UUID) error {
+	conference, err := s.conferenceSvc.GetConferenceByID(ctx, conferenceID)
+	if err != nil {
+		return err
+	}
+
+	if conference.Status == enum.ConferenceCancelled {
+		return errorpkg.ErrConferenceCancelled
+	}
+
+	registration, err := s.r.GetActiveRegistration(ctx, conferenceID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrUserNotRegisteredToConference
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+			"userID":       userID,
+		}, "[RegistrationService][ReconfirmRegistration] Failed to get registration")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if registration.ReconfirmBy == nil {
+		return errorpkg.ErrNoPendingReconfirmation
+	}
+
+	if registration.ReconfirmBy.Before(time.Now()) {
+		return errorpkg.ErrReconfirmationExpired
+	}
+
+	// The new schedule may overlap with another conference the user registered to in the meantime
 	conflictingRegistrations, err := s.r.GetConflictingRegistrations(ctx, userID, *conference.StartsAt,
-		*conference.EndsAt)
+		*conference.EndsAt, conferenceID)
 	if err != nil {
 		traceID := log.ErrorWithTraceID(map[string]interface{}{
 			"error":        err,
 			"conferenceID": conferenceID,
 			"userID":       userID,
-		}, "[RegistrationService][Register] Failed to get conflicting registrations")
+		}, "[RegistrationService][ReconfirmRegistration] Failed to get conflicting registrations")
 		return errorpkg.ErrInternalServer.WithTraceID(traceID)
 	}
 	if len(conflictingRegistrations) > 0 {
@@ -90,19 +226,24 @@ func (s *registrationService) Register(ctx context.Context, conferenceID, userID
 		})
 	}
 
-	// Create registration
-	if err := s.r.CreateRegistration(ctx, &entity.Registration{
-		ConferenceID: conferenceID,
-		UserID:       userID,
-	}); err != nil {
+	if err = s.r.ReconfirmRegistration(ctx, conferenceID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrReconfirmationExpired
+		}
+
 		traceID := log.ErrorWithTraceID(map[string]interface{}{
 			"error":        err,
 			"conferenceID": conferenceID,
 			"userID":       userID,
-		}, "[RegistrationService][Register] Failed to create registration")
+		}, "[RegistrationService][ReconfirmRegistration] Failed to reconfirm registration")
 		return errorpkg.ErrInternalServer.WithTraceID(traceID)
 	}
 
+	log.Info(map[string]interface{}{
+		"conferenceID": conferenceID,
+		"userID":       userID,
+	}, "[RegistrationService][ReconfirmRegistration] Registration reconfirmed")
+
 	return nil
 }
 
@@ -113,17 +254,11 @@ func (s *registrationService) GetRegisteredUsersByConference(ctx context.Context
 	}
 
 	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
-	requesterRole, _ := ctx.Value("user.role").(enum.UserRole)
 
-	conference, err := s.conferenceSvc.GetConferenceByID(ctx, conferenceID)
-	if err != nil {
+	if _, err := s.conferenceSvc.GetConferenceForStaff(ctx, conferenceID); err != nil {
 		return nil, dto.LazyLoadResponse{}, err
 	}
 
-	if requesterRole == enum.RoleUser && requesterID != conference.Host.ID {
-		return nil, dto.LazyLoadResponse{}, errorpkg.ErrForbiddenUser
-	}
-
 	users, lazyResp, err := s.r.GetRegisteredUsersByConference(ctx, conferenceID, lazyReq)
 	if err != nil {
 		traceID := log.ErrorWithTraceID(map[string]interface{}{
@@ -147,7 +282,8 @@ func (s *registrationService) GetRegisteredUsersByConference(ctx context.Context
 }
 
 func (s *registrationService) GetRegisteredConferencesByUser(ctx context.Context, userID uuid.UUID,
-	includePast bool, lazyReq dto.LazyLoadQuery) ([]dto.ConferenceResponse, dto.LazyLoadResponse, error) {
+	includePast bool, lazyReq dto.LazyLoadQuery) ([]dto.RegisteredConferenceResponse, dto.LazyLoadResponse,
+	error) {
 
 	if lazyReq.AfterID != uuid.Nil && lazyReq.BeforeID != uuid.Nil {
 		return nil, dto.LazyLoadResponse{}, errorpkg.ErrInvalidPagination
@@ -160,7 +296,7 @@ func (s *registrationService) GetRegisteredConferencesByUser(ctx context.Context
 		return nil, dto.LazyLoadResponse{}, errorpkg.ErrForbiddenUser
 	}
 
-	conferences, lazyResp, err := s.r.GetRegisteredConferencesByUser(ctx, userID, includePast, lazyReq)
+	registrations, lazyResp, err := s.r.GetRegisteredConferencesByUser(ctx, userID, includePast, lazyReq)
 	if err != nil {
 		traceID := log.ErrorWithTraceID(map[string]interface{}{
 			"error":        err,
@@ -170,16 +306,149 @@ func (s *registrationService) GetRegisteredConferencesByUser(ctx context.Context
 		return nil, dto.LazyLoadResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
 	}
 
-	resp := make([]dto.ConferenceResponse, len(conferences))
-	for i, conference := range conferences {
-		var temp dto.ConferenceResponse
-		temp.PopulateFromEntity(&conference)
-		resp[i] = temp
+	resp := make([]dto.RegisteredConferenceResponse, len(registrations))
+	for i, registration := range registrations {
+		resp[i].PopulateFromEntity(&registration)
+
+		// Tickets are only handed to their holder, staff can't use them to check someone in
+		if requesterID != userID {
+			continue
+		}
+
+		ticket, err2 := s.ticket.CreateTicket(registration.TicketID, registration.ConferenceID, userID)
+		if err2 != nil {
+			traceID := log.ErrorWithTraceID(map[string]interface{}{
+				"error":        err2,
+				"userID":       userID,
+				"conferenceID": registration.ConferenceID,
+			}, "[RegistrationService][GetRegisteredConferencesByUser] Failed to create ticket")
+			return nil, dto.LazyLoadResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+		}
+		resp[i].Ticket = ticket
 	}
 
 	return resp, lazyResp, nil
 }
 
+func (s *registrationService) CheckIn(ctx context.Context, conferenceID uuid.UUID,
+	ticket string) (dto.CheckInResponse, error) {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	conference, err := s.conferenceSvc.GetConferenceForStaff(ctx, conferenceID)
+	if err != nil {
+		return dto.CheckInResponse{}, err
+	}
+
+	if conference.Status == enum.ConferenceCancelled {
+		return dto.CheckInResponse{}, errorpkg.ErrConferenceCancelled
+	}
+
+	var claims jwt.TicketClaims
+	if err = s.ticket.DecodeTicket(ticket, &claims); err != nil {
+		return dto.CheckInResponse{}, errorpkg.ErrInvalidTicket
+	}
+
+	if claims.ConferenceID != conferenceID {
+		return dto.CheckInResponse{}, errorpkg.ErrForeignTicket
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return dto.CheckInResponse{}, errorpkg.ErrInvalidTicket
+	}
+
+	ticketID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return dto.CheckInResponse{}, errorpkg.ErrInvalidTicket
+	}
+
+	registration, err := s.r.GetActiveRegistration(ctx, conferenceID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.CheckInResponse{}, errorpkg.ErrInvalidTicket
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+			"userID":       userID,
+			"requester.id": requesterID,
+		}, "[RegistrationService][CheckIn] Failed to get registration")
+		return dto.CheckInResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	// A ticket from a cancelled and renewed registration is stale
+	if registration.TicketID != ticketID {
+		return dto.CheckInResponse{}, errorpkg.ErrInvalidTicket
+	}
+
+	if registration.CheckedInAt != nil {
+		return dto.CheckInResponse{}, errorpkg.ErrTicketAlreadyUsed.WithDetail(map[string]interface{}{
+			"checked_in_at": registration.CheckedInAt,
+		})
+	}
+
+	registration.CheckedInBy = &requesterID
+	if err = s.r.CheckInRegistration(ctx, registration); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.CheckInResponse{}, errorpkg.ErrTicketAlreadyUsed
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+			"userID":       userID,
+			"requester.id": requesterID,
+		}, "[RegistrationService][CheckIn] Failed to check in registration")
+		return dto.CheckInResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"conferenceID": conferenceID,
+		"userID":       userID,
+		"requester.id": requesterID,
+	}, "[RegistrationService][CheckIn] Attendee checked in")
+
+	resp := dto.CheckInResponse{
+		Attendee:    &dto.UserResponse{ID: userID},
+		CheckedInAt: registration.CheckedInAt,
+	}
+
+	// The check-in is already recorded, the name is only a convenience for the scanner
+	user, err := s.userSvc.GetUserByID(ctx, userID)
+	if err == nil {
+		resp.Attendee.PopulateMinimalFromEntity(user)
+	}
+
+	return resp, nil
+}
+
+func (s *registrationService) GetAttendance(ctx context.Context,
+	conferenceID uuid.UUID) (dto.AttendanceResponse, error) {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	if _, err := s.conferenceSvc.GetConferenceForStaff(ctx, conferenceID); err != nil {
+		return dto.AttendanceResponse{}, err
+	}
+
+	registered, checkedIn, err := s.r.CountAttendance(ctx, conferenceID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+			"requester.id": requesterID,
+		}, "[RegistrationService][GetAttendance] Failed to count attendance")
+		return dto.AttendanceResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	return dto.AttendanceResponse{
+		Registered: registered,
+		CheckedIn:  checkedIn,
+	}, nil
+}
+
 func (s *registrationService) IsUserRegisteredToConference(ctx context.Context, conferenceID,
 	userID uuid.UUID) (bool, error) {
 
@@ -195,3 +464,318 @@ func (s *registrationService) IsUserRegisteredToConference(ctx context.Context,
 
 	return ok, nil
 }
+
+func (s *registrationService) JoinWaitlist(ctx context.Context, conferenceID,
+	userID uuid.UUID) (dto.WaitlistEntryResponse, error) {
+
+	conference, err := s.conferenceSvc.GetConferenceByID(ctx, conferenceID)
+	if err != nil {
+		return dto.WaitlistEntryResponse{}, err
+	}
+
+	if conference.Status == enum.ConferenceCancelled {
+		return dto.WaitlistEntryResponse{}, errorpkg.ErrConferenceCancelled
+	}
+
+	if conference.IsHostedBy(userID) {
+		return dto.WaitlistEntryResponse{}, errorpkg.ErrHostCannotRegister
+	}
+
+	if conference.EndsAt.Before(time.Now()) {
+		return dto.WaitlistEntryResponse{}, errorpkg.ErrConferenceEnded
+	}
+
+	isRegistered, err := s.r.IsUserRegisteredToConference(ctx, conferenceID, userID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+			"userID":       userID,
+		}, "[RegistrationService][JoinWaitlist] Failed to check if user is registered to conference")
+		return dto.WaitlistEntryResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+	if isRegistered {
+		return dto.WaitlistEntryResponse{}, errorpkg.ErrUserAlreadyRegisteredToConference
+	}
+
+	// Joining the waitlist only makes sense when there's no seat to take right now
+	taken, err := s.r.CountRegistrationsByConference(ctx, conferenceID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+		}, "[RegistrationService][JoinWaitlist] Failed to count registrations by conference")
+		return dto.WaitlistEntryResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	offered, err := s.r.CountActiveWaitlistOffers(ctx, conferenceID, uuid.Nil)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+		}, "[RegistrationService][JoinWaitlist] Failed to count active waitlist offers")
+		return dto.WaitlistEntryResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if taken+offered < conference.Seats {
+		return dto.WaitlistEntryResponse{}, errorpkg.ErrConferenceNotFull
+	}
+
+	entryID, err := s.uuid.NewV7()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+			"userID":       userID,
+		}, "[RegistrationService][JoinWaitlist] Failed to generate waitlist entry ID")
+		return dto.WaitlistEntryResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	entry := entity.WaitlistEntry{
+		ID:           entryID,
+		ConferenceID: conferenceID,
+		UserID:       userID,
+		Status:       enum.WaitlistWaiting,
+	}
+
+	if err = s.r.CreateWaitlistEntry(ctx, &entry); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "waitlist_entries_active_key" {
+			return dto.WaitlistEntryResponse{}, errorpkg.ErrAlreadyOnWaitlist
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+			"userID":       userID,
+		}, "[RegistrationService][JoinWaitlist] Failed to create waitlist entry")
+		return dto.WaitlistEntryResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"entry": entry,
+	}, "[RegistrationService][JoinWaitlist] User joined waitlist")
+
+	return s.GetWaitlistEntry(ctx, conferenceID, userID)
+}
+
+func (s *registrationService) GetWaitlistEntry(ctx context.Context, conferenceID,
+	userID uuid.UUID) (dto.WaitlistEntryResponse, error) {
+
+	entry, err := s.r.GetActiveWaitlistEntry(ctx, conferenceID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return dto.WaitlistEntryResponse{}, errorpkg.ErrNotOnWaitlist
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+			"userID":       userID,
+		}, "[RegistrationService][GetWaitlistEntry] Failed to get waitlist entry")
+		return dto.WaitlistEntryResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	var resp dto.WaitlistEntryResponse
+	resp.PopulateFromEntity(entry)
+
+	if entry.Status == enum.WaitlistWaiting {
+		position, err2 := s.r.GetWaitlistPosition(ctx, entry)
+		if err2 != nil {
+			traceID := log.ErrorWithTraceID(map[string]interface{}{
+				"error": err2,
+				"entry": entry,
+			}, "[RegistrationService][GetWaitlistEntry] Failed to get waitlist position")
+			return dto.WaitlistEntryResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+		}
+		resp.Position = &position
+	}
+
+	return resp, nil
+}
+
+func (s *registrationService) LeaveWaitlist(ctx context.Context, conferenceID, userID uuid.UUID) error {
+	entry, err := s.r.GetActiveWaitlistEntry(ctx, conferenceID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotOnWaitlist
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+			"userID":       userID,
+		}, "[RegistrationService][LeaveWaitlist] Failed to get waitlist entry")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if err = s.r.UpdateWaitlistEntryStatus(ctx, entry.ID, enum.WaitlistLeft); err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error": err,
+			"entry": entry,
+		}, "[RegistrationService][LeaveWaitlist] Failed to update waitlist entry")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"entry": entry,
+	}, "[RegistrationService][LeaveWaitlist] User left waitlist")
+
+	// A declined offer frees the held seat for the next person
+	if entry.Status == enum.WaitlistOffered {
+		return s.PromoteWaitlist(ctx, conferenceID)
+	}
+
+	return nil
+}
+
+func (s *registrationService) ConfirmWaitlistOffer(ctx context.Context, conferenceID, userID uuid.UUID) error {
+	entry, err := s.r.GetActiveWaitlistEntry(ctx, conferenceID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotOnWaitlist
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+			"userID":       userID,
+		}, "[RegistrationService][ConfirmWaitlistOffer] Failed to get waitlist entry")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if entry.Status != enum.WaitlistOffered {
+		return errorpkg.ErrNoWaitlistOffer
+	}
+
+	if entry.OfferExpiresAt == nil || entry.OfferExpiresAt.Before(time.Now()) {
+		if err = s.r.UpdateWaitlistEntryStatus(ctx, entry.ID, enum.WaitlistExpired); err != nil {
+			traceID := log.ErrorWithTraceID(map[string]interface{}{
+				"error": err,
+				"entry": entry,
+			}, "[RegistrationService][ConfirmWaitlistOffer] Failed to expire waitlist entry")
+			return errorpkg.ErrInternalServer.WithTraceID(traceID)
+		}
+
+		if err = s.PromoteWaitlist(ctx, conferenceID); err != nil {
+			return err
+		}
+
+		return errorpkg.ErrWaitlistOfferExpired
+	}
+
+	// The offered seat is excluded from the capacity check for this user, so a regular registration claims it
+	return s.Register(ctx, conferenceID, userID)
+}
+
+func (s *registrationService) PromoteWaitlist(ctx context.Context, conferenceID uuid.UUID) error {
+	offerExpiresAt := time.Now().Add(env.GetEnv().WaitlistOfferDuration)
+
+	entries, err := s.r.OfferWaitlistSeats(ctx, conferenceID, offerExpiresAt)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"conferenceID": conferenceID,
+		}, "[RegistrationService][PromoteWaitlist] Failed to offer waitlist seats")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	conference, err := s.conferenceSvc.GetConferenceByID(ctx, conferenceID)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		user, err2 := s.userSvc.GetUserByID(ctx, entry.UserID)
+		if err2 != nil {
+			log.Error(map[string]interface{}{
+				"error": err2,
+				"entry": entry,
+			}, "[RegistrationService][PromoteWaitlist] Failed to get promoted user")
+			continue
+		}
+
+		go func(email, name string, expiresAt time.Time) {
+			err := s.mailer.Send(
+				email,
+				"[Auditorium Reservation] A Seat Is Available For You",
+				"waitlist_offer.html",
+				map[string]interface{}{
+					"name":       name,
+					"conference": conference.Title,
+					"expires_at": expiresAt.Format(time.RFC1123),
+					"href":       env.GetEnv().FrontendURL + "/conferences/" + conferenceID.String(),
+				})
+
+			if err != nil {
+				log.Error(map[string]interface{}{
+					"error": err.Error(),
+				}, "[RegistrationService][PromoteWaitlist] failed to send email")
+			}
+		}(user.Email, user.Name, *entry.OfferExpiresAt)
+
+		log.Info(map[string]interface{}{
+			"entry": entry,
+		}, "[RegistrationService][PromoteWaitlist] Seat offered to waitlisted user")
+	}
+
+	return nil
+}
+
+func (s *registrationService) ExpireWaitlistOffers(ctx context.Context) error {
+	entries, err := s.r.ExpireWaitlistOffers(ctx)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error": err,
+		}, "[RegistrationService][ExpireWaitlistOffers] Failed to expire waitlist offers")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	// Promotion failures are already logged, keep going so one conference can't block the others
+	var promoteErr error
+	promoted := make(map[uuid.UUID]bool)
+	for _, entry := range entries {
+		log.Info(map[string]interface{}{
+			"entry": entry,
+		}, "[RegistrationService][ExpireWaitlistOffers] Waitlist offer expired")
+
+		if promoted[entry.ConferenceID] {
+			continue
+		}
+		promoted[entry.ConferenceID] = true
+
+		if err = s.PromoteWaitlist(ctx, entry.ConferenceID); err != nil {
+			promoteErr = err
+		}
+	}
+
+	return promoteErr
+}
+
+func (s *registrationService) ReleaseUnconfirmedRegistrations(ctx context.Context) error {
+	conferenceIDs, err := s.r.ReleaseUnconfirmedRegistrations(ctx)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error": err,
+		}, "[RegistrationService][ReleaseUnconfirmedRegistrations] Failed to release unconfirmed registrations")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	// Promotion failures are already logged, keep going so one conference can't block the others
+	var promoteErr error
+	for _, conferenceID := range conferenceIDs {
+		log.Info(map[string]interface{}{
+			"conferenceID": conferenceID,
+		}, "[RegistrationService][ReleaseUnconfirmedRegistrations] Unconfirmed registrations released")
+
+		if err = s.PromoteWaitlist(ctx, conferenceID); err != nil {
+			promoteErr = err
+		}
+	}
+
+	return promoteErr
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
+	"github.com/nathakusuma/auditorium-reservation-backend/internal/middleware"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/validator"
+)
+
+type roomHandler struct {
+	val validator.IValidator
+	svc contract.IRoomService
+}
+
+func InitRoomHandler(
+	router fiber.Router,
+	midw *middleware.Middleware,
+	validator validator.IValidator,
+	roomSvc contract.IRoomService,
+) {
+	handler := roomHandler{
+		svc: roomSvc,
+		val: validator,
+	}
+
+	roomGroup := router.Group("/rooms")
+	roomGroup.Use(midw.RequireAuthenticated())
+
+	roomGroup.Post("",
+		midw.RequireOneOfRoles(enum.RoleAdmin),
+		handler.createRoom(),
+	)
+	roomGroup.Get("/:id",
+		handler.getRoomByID(),
+	)
+	roomGroup.Get("",
+		handler.getRooms(),
+	)
+	roomGroup.Patch("/:id",
+		midw.RequireOneOfRoles(enum.RoleAdmin),
+		handler.updateRoom(),
+	)
+	roomGroup.Delete("/:id",
+		midw.RequireOneOfRoles(enum.RoleAdmin),
+		handler.deleteRoom(),
+	)
+}
+
+func (h *roomHandler) createRoom() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var req dto.CreateRoomRequest
+		if err := ctx.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := h.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		roomID, err := h.svc.CreateRoom(ctx.Context(), &req)
+		if err != nil {
+			return err
+		}
+
+		return ctx.Status(fiber.StatusCreated).JSON(map[string]interface{}{
+			"room": dto.RoomResponse{ID: roomID},
+		})
+	}
+}
+
+func (h *roomHandler) getRoomByID() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		roomID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		room, err := h.svc.GetRoomByID(ctx.Context(), roomID)
+		if err != nil {
+			return err
+		}
+
+		return ctx.JSON(map[string]interface{}{
+			"room": room,
+		})
+	}
+}
+
+func (h *roomHandler) getRooms() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var lazyReq dto.LazyLoadQuery
+		if err := ctx.QueryParser(&lazyReq); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := h.val.ValidateStruct(lazyReq); err != nil {
+			return err
+		}
+
+		rooms, lazyResp, err := h.svc.GetRooms(ctx.Context(), lazyReq)
+		if err != nil {
+			return err
+		}
+
+		return ctx.JSON(map[string]interface{}{
+			"rooms":      rooms,
+			"pagination": lazyResp,
+		})
+	}
+}
+
+func (h *roomHandler) updateRoom() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		roomID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		var req dto.UpdateRoomRequest
+		if err = ctx.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = h.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		if err = h.svc.UpdateRoom(ctx.Context(), roomID, req); err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *roomHandler) deleteRoom() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		roomID, err := uuid.Parse(ctx.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = h.svc.DeleteRoom(ctx.Context(), roomID); err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusNoContent)
+	}
+}
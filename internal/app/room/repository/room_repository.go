@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+)
+
+type roomRepository struct {
+	db *sqlx.DB
+}
+
+func NewRoomRepository(db *sqlx.DB) contract.IRoomRepository {
+	return &roomRepository{
+		db: db,
+	}
+}
+
+func (r *roomRepository) createRoom(ctx context.Context, tx sqlx.ExtContext, room *entity.Room) error {
+	_, err := sqlx.NamedExecContext(
+		ctx,
+		tx,
+		`INSERT INTO rooms (id, name, building, capacity, amenities)
+		VALUES (:id, :name, :building, :capacity, :amenities)`,
+		room,
+	)
+	return err
+}
+
+func (r *roomRepository) CreateRoom(ctx context.Context, room *entity.Room) error {
+	return r.createRoom(ctx, r.db, room)
+}
+
+func (r *roomRepository) GetRoomByID(ctx context.Context, id uuid.UUID) (*entity.Room, error) {
+	var room entity.Room
+
+	err := r.db.GetContext(ctx, &room, `
+		SELECT id, name, building, capacity, amenities, created_at, updated_at, deleted_at
+		FROM rooms
+		WHERE id = $1
+		AND deleted_at IS NULL`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &room, nil
+}
+
+func (r *roomRepository) GetRooms(ctx context.Context,
+	lazy dto.LazyLoadQuery) ([]entity.Room, dto.LazyLoadResponse, error) {
+
+	var args []interface{}
+
+	query := `SELECT id, name, building, capacity, amenities, created_at, updated_at, deleted_at
+		FROM rooms
+		WHERE deleted_at IS NULL`
+
+	// Add pagination filters
+	if lazy.AfterID != uuid.Nil {
+		args = append(args, lazy.AfterID)
+		query += fmt.Sprintf(" AND id > $%d", len(args))
+	}
+	if lazy.BeforeID != uuid.Nil {
+		args = append(args, lazy.BeforeID)
+		query += fmt.Sprintf(" AND id < $%d", len(args))
+	}
+
+	// Add ordering and limit
+	if lazy.BeforeID != uuid.Nil {
+		query += " ORDER BY id DESC"
+	} else {
+		query += " ORDER BY id ASC"
+	}
+	args = append(args, lazy.Limit+1) // Request one extra record to determine if there are more results
+	query += fmt.Sprintf(" LIMIT $%d", len(args))
+
+	var rooms []entity.Room
+	if err := r.db.SelectContext(ctx, &rooms, query, args...); err != nil {
+		return nil, dto.LazyLoadResponse{}, fmt.Errorf("failed to query rooms: %w", err)
+	}
+
+	// Prepare response
+	lazyResp := dto.LazyLoadResponse{
+		HasMore: false,
+		FirstID: nil,
+		LastID:  nil,
+	}
+
+	if len(rooms) > 0 {
+		// Check if we got an extra record
+		if len(rooms) > lazy.Limit {
+			lazyResp.HasMore = true
+			rooms = rooms[:lazy.Limit]
+		}
+
+		// For BeforeID, reverse the final result set to maintain ascending order
+		if lazy.BeforeID != uuid.Nil {
+			for i := 0; i < len(rooms)/2; i++ {
+				j := len(rooms) - 1 - i
+				rooms[i], rooms[j] = rooms[j], rooms[i]
+			}
+		}
+
+		lazyResp.FirstID = rooms[0].ID
+		lazyResp.LastID = rooms[len(rooms)-1].ID
+	}
+
+	return rooms, lazyResp, nil
+}
+
+func (r *roomRepository) updateRoom(ctx context.Context, tx sqlx.ExtContext, room *entity.Room) error {
+	res, err := sqlx.NamedExecContext(
+		ctx,
+		tx,
+		`UPDATE rooms
+		SET name = :name,
+			building = :building,
+			capacity = :capacity,
+			amenities = :amenities,
+			updated_at = now()
+		WHERE id = :id
+		AND deleted_at IS NULL`,
+		room,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *roomRepository) UpdateRoom(ctx context.Context, room *entity.Room) error {
+	return r.updateRoom(ctx, r.db, room)
+}
+
+func (r *roomRepository) deleteRoom(ctx context.Context, tx sqlx.ExtContext, id uuid.UUID) error {
+	res, err := tx.ExecContext(ctx,
+		`UPDATE rooms SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *roomRepository) DeleteRoom(ctx context.Context, id uuid.UUID) error {
+	return r.deleteRoom(ctx, r.db, id)
+}
+
+func (r *roomRepository) GetUpcomingConferencesExceedingSeats(ctx context.Context, roomID uuid.UUID,
+	seats int) ([]entity.Conference, error) {
+
+	var conferences []entity.Conference
+
+	err := r.db.SelectContext(ctx, &conferences, `
+		SELECT
+			c.id, c.title, c.description, c.speaker_name, c.speaker_title,
+			c.target_audience, c.prerequisites, c.seats, c.starts_at, c.ends_at,
+			c.host_id, c.room_id, c.status, c.created_at, c.updated_at
+		FROM conferences c
+		WHERE c.deleted_at IS NULL
+		AND c.room_id = $1
+		AND c.status IN ('pending', 'needs_revision', 'approved')
+		AND c.ends_at > $2
+		AND c.seats > $3
+		ORDER BY c.starts_at
+		LIMIT 10
+		`, roomID, time.Now(), seats)
+	if err != nil {
+		return nil, err
+	}
+
+	return conferences, nil
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/log"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/uuidpkg"
+)
+
+type roomService struct {
+	r    contract.IRoomRepository
+	uuid uuidpkg.IUUID
+}
+
+func NewRoomService(roomRepo contract.IRoomRepository, uuid uuidpkg.IUUID) contract.IRoomService {
+	return &roomService{r: roomRepo, uuid: uuid}
+}
+
+func (s *roomService) CreateRoom(ctx context.Context, req *dto.CreateRoomRequest) (uuid.UUID, error) {
+	requesterID := ctx.Value("user.id")
+
+	roomID, err := s.uuid.NewV7()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"request":      req,
+			"requester.id": requesterID,
+		}, "[RoomService][CreateRoom] Failed to generate room ID")
+		return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	room := entity.Room{
+		ID:        roomID,
+		Name:      req.Name,
+		Building:  req.Building,
+		Capacity:  req.Capacity,
+		Amenities: req.Amenities,
+	}
+
+	if err = s.r.CreateRoom(ctx, &room); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "rooms_building_name_key" {
+			return uuid.Nil, errorpkg.ErrRoomAlreadyExists
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"request":      req,
+			"requester.id": requesterID,
+		}, "[RoomService][CreateRoom] Failed to create room")
+		return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"room":         room,
+		"requester.id": requesterID,
+	}, "[RoomService][CreateRoom] Room created")
+
+	return roomID, nil
+}
+
+func (s *roomService) getRoomByID(ctx context.Context, id uuid.UUID) (*entity.Room, error) {
+	room, err := s.r.GetRoomByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorpkg.ErrNotFound.WithMessage("Room not found.")
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"room.id":      id,
+			"requester.id": ctx.Value("user.id"),
+		}, "[RoomService][getRoomByID] Failed to get room")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	return room, nil
+}
+
+func (s *roomService) GetRoomByID(ctx context.Context, id uuid.UUID) (*dto.RoomResponse, error) {
+	room, err := s.getRoomByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(dto.RoomResponse).PopulateFromEntity(room), nil
+}
+
+func (s *roomService) GetRooms(ctx context.Context,
+	lazy dto.LazyLoadQuery) ([]dto.RoomResponse, dto.LazyLoadResponse, error) {
+
+	if lazy.AfterID != uuid.Nil && lazy.BeforeID != uuid.Nil {
+		return nil, dto.LazyLoadResponse{}, errorpkg.ErrInvalidPagination
+	}
+
+	rooms, lazyResp, err := s.r.GetRooms(ctx, lazy)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"requester.id": ctx.Value("user.id"),
+		}, "[RoomService][GetRooms] Failed to get rooms")
+		return nil, dto.LazyLoadResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	resp := make([]dto.RoomResponse, len(rooms))
+	for i, room := range rooms {
+		resp[i].PopulateFromEntity(&room)
+	}
+
+	return resp, lazyResp, nil
+}
+
+func (s *roomService) UpdateRoom(ctx context.Context, id uuid.UUID, req dto.UpdateRoomRequest) error {
+	requesterID := ctx.Value("user.id")
+
+	room, err := s.getRoomByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Shrinking a room must not leave upcoming conferences with more seats than it can hold
+	if req.Capacity != nil && *req.Capacity < room.Capacity {
+		conflicts, err2 := s.r.GetUpcomingConferencesExceedingSeats(ctx, id, *req.Capacity)
+		if err2 != nil {
+			traceID := log.ErrorWithTraceID(map[string]interface{}{
+				"error":        err2.Error(),
+				"room.id":      id,
+				"requester.id": requesterID,
+			}, "[RoomService][UpdateRoom] Failed to get conferences exceeding capacity")
+			return errorpkg.ErrInternalServer.WithTraceID(traceID)
+		}
+
+		if len(conflicts) > 0 {
+			return errorpkg.ErrSeatsExceedRoomCapacity.WithDetail(map[string]interface{}{
+				"conferences": toConferenceSummaries(conflicts),
+			})
+		}
+	}
+
+	req.GenerateUpdateEntity(room)
+
+	if err = s.r.UpdateRoom(ctx, room); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotFound.WithMessage("Room not found.")
+		}
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.ConstraintName == "rooms_building_name_key" {
+			return errorpkg.ErrRoomAlreadyExists
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"room":         room,
+			"requester.id": requesterID,
+		}, "[RoomService][UpdateRoom] Failed to update room")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"room":         room,
+		"requester.id": requesterID,
+	}, "[RoomService][UpdateRoom] Room updated")
+
+	return nil
+}
+
+func (s *roomService) DeleteRoom(ctx context.Context, id uuid.UUID) error {
+	requesterID := ctx.Value("user.id")
+
+	// A room with upcoming conferences cannot be removed
+	conflicts, err := s.r.GetUpcomingConferencesExceedingSeats(ctx, id, 0)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"room.id":      id,
+			"requester.id": requesterID,
+		}, "[RoomService][DeleteRoom] Failed to get upcoming conferences")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if len(conflicts) > 0 {
+		return errorpkg.ErrRoomInUse.WithDetail(map[string]interface{}{
+			"conferences": toConferenceSummaries(conflicts),
+		})
+	}
+
+	if err = s.r.DeleteRoom(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotFound.WithMessage("Room not found.")
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"room.id":      id,
+			"requester.id": requesterID,
+		}, "[RoomService][DeleteRoom] Failed to delete room")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"room.id":      id,
+		"requester.id": requesterID,
+	}, "[RoomService][DeleteRoom] Room deleted")
+
+	return nil
+}
+
+func toConferenceSummaries(conferences []entity.Conference) []dto.ConferenceResponse {
+	resp := make([]dto.ConferenceResponse, len(conferences))
+	for i, conference := range conferences {
+		resp[i] = dto.ConferenceResponse{
+			ID:       conference.ID,
+			Title:    conference.Title,
+			Seats:    conference.Seats,
+			StartsAt: &conference.StartsAt,
+			EndsAt:   &conference.EndsAt,
+			Status:   conference.Status,
+		}
+	}
+
+	return resp
+}
@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
+	"github.com/nathakusuma/auditorium-reservation-backend/internal/middleware"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/validator"
+)
+
+type surveyHandler struct {
+	svc contract.ISurveyService
+	val validator.IValidator
+}
+
+func InitSurveyHandler(
+	router fiber.Router,
+	middleware *middleware.Middleware,
+	validator validator.IValidator,
+	surveyService contract.ISurveyService) {
+
+	handler := surveyHandler{
+		svc: surveyService,
+		val: validator,
+	}
+
+	surveyGroup := router.Group("/surveys")
+	surveyGroup.Use(middleware.RequireAuthenticated())
+
+	surveyGroup.Post("/templates",
+		middleware.RequireOneOfRoles(enum.RoleEventCoordinator),
+		handler.createTemplate(),
+	)
+
+	surveyGroup.Get("/templates", handler.getTemplates())
+	surveyGroup.Get("/templates/:id", handler.getTemplateByID())
+
+	surveyGroup.Delete("/templates/:id",
+		middleware.RequireOneOfRoles(enum.RoleEventCoordinator),
+		handler.deleteTemplate(),
+	)
+
+	surveyGroup.Get("/conferences/:id", handler.getConferenceSurvey())
+
+	surveyGroup.Put("/conferences/:id",
+		middleware.RequireOneOfRoles(enum.RoleUser, enum.RoleEventCoordinator),
+		handler.attachTemplate(),
+	)
+
+	surveyGroup.Delete("/conferences/:id",
+		middleware.RequireOneOfRoles(enum.RoleUser, enum.RoleEventCoordinator),
+		handler.detachTemplate(),
+	)
+
+	surveyGroup.Get("/conferences/:id/results",
+		middleware.RequireOneOfRoles(enum.RoleUser, enum.RoleEventCoordinator),
+		handler.getResults(),
+	)
+
+	surveyGroup.Get("/conferences/:id/results.csv",
+		middleware.RequireOneOfRoles(enum.RoleUser, enum.RoleEventCoordinator),
+		handler.exportResponses(),
+	)
+}
+
+func (h *surveyHandler) createTemplate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var req dto.CreateSurveyTemplateRequest
+		if err := c.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := h.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		resp, err := h.svc.CreateTemplate(c.Context(), req)
+		if err != nil {
+			return err
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(map[string]interface{}{
+			"template": resp,
+		})
+	}
+}
+
+func (h *surveyHandler) getTemplates() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var lazyReq dto.LazyLoadQuery
+		if err := c.QueryParser(&lazyReq); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err := h.val.ValidateStruct(lazyReq); err != nil {
+			return err
+		}
+
+		templates, lazyResp, err := h.svc.GetTemplates(c.Context(), lazyReq)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"templates":  templates,
+			"pagination": lazyResp,
+		})
+	}
+}
+
+func (h *surveyHandler) getTemplateByID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		template, err := h.svc.GetTemplateByID(c.Context(), id)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"template": template,
+		})
+	}
+}
+
+func (h *surveyHandler) deleteTemplate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = h.svc.DeleteTemplate(c.Context(), id); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *surveyHandler) getConferenceSurvey() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		survey, err := h.svc.GetConferenceSurvey(c.Context(), conferenceID)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"survey": survey,
+		})
+	}
+}
+
+type attachTemplateRequest struct {
+	TemplateID uuid.UUID `json:"template_id" validate:"required"`
+}
+
+func (h *surveyHandler) attachTemplate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		var req attachTemplateRequest
+		if err = c.BodyParser(&req); err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = h.val.ValidateStruct(req); err != nil {
+			return err
+		}
+
+		if err = h.svc.AttachTemplate(c.Context(), conferenceID, req.TemplateID); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *surveyHandler) detachTemplate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		if err = h.svc.DetachTemplate(c.Context(), conferenceID); err != nil {
+			return err
+		}
+
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+func (h *surveyHandler) getResults() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		results, err := h.svc.GetResults(c.Context(), conferenceID)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(map[string]interface{}{
+			"results": results,
+		})
+	}
+}
+
+func (h *surveyHandler) exportResponses() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conferenceID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return errorpkg.ErrFailParseRequest
+		}
+
+		data, err := h.svc.ExportResponses(c.Context(), conferenceID)
+		if err != nil {
+			return err
+		}
+
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		c.Set(fiber.HeaderContentDisposition,
+			fmt.Sprintf(`attachment; filename="survey-%s.csv"`, conferenceID))
+		return c.Send(data)
+	}
+}
@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+)
+
+type surveyRepository struct {
+	db *sqlx.DB
+}
+
+func NewSurveyRepository(db *sqlx.DB) contract.ISurveyRepository {
+	return &surveyRepository{
+		db: db,
+	}
+}
+
+func (r *surveyRepository) CreateTemplate(ctx context.Context, template *entity.SurveyTemplate) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.NamedExecContext(ctx, `
+		INSERT INTO survey_templates (id, title, description, created_by, created_at, updated_at)
+		VALUES (:id, :title, :description, :created_by, :created_at, :updated_at)`, template); err != nil {
+		return fmt.Errorf("failed to create survey template: %w", err)
+	}
+
+	for _, question := range template.Questions {
+		var options *string
+		if question.Options != nil {
+			encoded, err2 := json.Marshal(question.Options)
+			if err2 != nil {
+				return fmt.Errorf("failed to encode question options: %w", err2)
+			}
+			optionsStr := string(encoded)
+			options = &optionsStr
+		}
+
+		if _, err = tx.ExecContext(ctx, `
+			INSERT INTO survey_questions (id, template_id, position, type, prompt, required, options, scale_max)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			question.ID, question.TemplateID, question.Position, question.Type, question.Prompt,
+			question.Required, options, question.ScaleMax); err != nil {
+			return fmt.Errorf("failed to create survey question: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *surveyRepository) GetTemplates(ctx context.Context,
+	lazy dto.LazyLoadQuery) ([]entity.SurveyTemplate, dto.LazyLoadResponse, error) {
+
+	var args []interface{}
+	query := `SELECT id, title, description, created_by, created_at, updated_at, deleted_at
+		FROM survey_templates
+		WHERE deleted_at IS NULL`
+
+	if lazy.AfterID != uuid.Nil {
+		args = append(args, lazy.AfterID)
+		query += fmt.Sprintf(" AND id > $%d", len(args))
+	}
+	if lazy.BeforeID != uuid.Nil {
+		args = append(args, lazy.BeforeID)
+		query += fmt.Sprintf(" AND id < $%d", len(args))
+	}
+
+	if lazy.BeforeID != uuid.Nil {
+		query += " ORDER BY id DESC"
+	} else {
+		query += " ORDER BY id ASC"
+	}
+	args = append(args, lazy.Limit+1) // Request one extra record to determine if there are more results
+	query += fmt.Sprintf(" LIMIT $%d", len(args))
+
+	var templates []entity.SurveyTemplate
+	if err := r.db.SelectContext(ctx, &templates, query, args...); err != nil {
+		return nil, dto.LazyLoadResponse{}, fmt.Errorf("failed to query survey templates: %w", err)
+	}
+
+	lazyResp := dto.LazyLoadResponse{}
+	if len(templates) > 0 {
+		if len(templates) > lazy.Limit {
+			lazyResp.HasMore = true
+			templates = templates[:lazy.Limit]
+		}
+
+		// For BeforeID, reverse the final result set to maintain ascending order
+		if lazy.BeforeID != uuid.Nil {
+			for i := 0; i < len(templates)/2; i++ {
+				j := len(templates) - 1 - i
+				templates[i], templates[j] = templates[j], templates[i]
+			}
+		}
+
+		lazyResp.FirstID = templates[0].ID
+		lazyResp.LastID = templates[len(templates)-1].ID
+	}
+
+	return templates, lazyResp, nil
+}
+
+func (r *surveyRepository) GetTemplateByID(ctx context.Context, id uuid.UUID) (*entity.SurveyTemplate, error) {
+	var template entity.SurveyTemplate
+	if err := r.db.GetContext(ctx, &template, `
+		SELECT id, title, description, created_by, created_at, updated_at, deleted_at
+		FROM survey_templates
+		WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, template_id, position, type, prompt, required, options, scale_max
+		FROM survey_questions
+		WHERE template_id = $1
+		ORDER BY position`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	template.Questions = []entity.SurveyQuestion{}
+	for rows.Next() {
+		var question entity.SurveyQuestion
+		var options []byte
+		if err = rows.Scan(&question.ID, &question.TemplateID, &question.Position, &question.Type,
+			&question.Prompt, &question.Required, &options, &question.ScaleMax); err != nil {
+			return nil, err
+		}
+
+		if options != nil {
+			if err = json.Unmarshal(options, &question.Options); err != nil {
+				return nil, fmt.Errorf("failed to decode question options: %w", err)
+			}
+		}
+
+		template.Questions = append(template.Questions, question)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (r *surveyRepository) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE survey_templates
+		SET deleted_at = now()
+		WHERE id = $1
+		AND deleted_at IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete survey template: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *surveyRepository) AttachTemplate(ctx context.Context, survey *entity.ConferenceSurvey) error {
+	_, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO conference_surveys (conference_id, template_id, attached_by, attached_at)
+		VALUES (:conference_id, :template_id, :attached_by, :attached_at)
+		ON CONFLICT (conference_id) DO UPDATE
+		SET template_id = EXCLUDED.template_id,
+			attached_by = EXCLUDED.attached_by,
+			attached_at = EXCLUDED.attached_at`, survey)
+	return err
+}
+
+func (r *surveyRepository) DetachTemplate(ctx context.Context, conferenceID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM conference_surveys WHERE conference_id = $1`, conferenceID)
+	if err != nil {
+		return fmt.Errorf("failed to detach survey: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *surveyRepository) GetConferenceSurvey(ctx context.Context,
+	conferenceID uuid.UUID) (*entity.ConferenceSurvey, error) {
+
+	var survey entity.ConferenceSurvey
+	if err := r.db.GetContext(ctx, &survey, `
+		SELECT conference_id, template_id, attached_by, attached_at
+		FROM conference_surveys
+		WHERE conference_id = $1`, conferenceID); err != nil {
+		return nil, err
+	}
+
+	return &survey, nil
+}
+
+func (r *surveyRepository) HasAnswers(ctx context.Context, conferenceID uuid.UUID) (bool, error) {
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM survey_answers a
+			JOIN feedbacks f ON a.feedback_id = f.id
+			WHERE f.conference_id = $1
+		)`, conferenceID); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *surveyRepository) CountAnswers(ctx context.Context,
+	conferenceID uuid.UUID) ([]dto.SurveyAnswerCountRow, int, error) {
+
+	var rows []dto.SurveyAnswerCountRow
+	if err := r.db.SelectContext(ctx, &rows, `
+		SELECT a.question_id, COALESCE(a.rating_value::TEXT, a.choice_value) AS value, COUNT(*) AS count
+		FROM survey_answers a
+		JOIN feedbacks f ON a.feedback_id = f.id
+		WHERE f.conference_id = $1
+		AND f.deleted_at IS NULL
+		AND f.hidden_at IS NULL
+		AND a.text_value IS NULL
+		GROUP BY a.question_id, value
+		UNION ALL
+		SELECT a.question_id, NULL AS value, COUNT(DISTINCT a.feedback_id) AS count
+		FROM survey_answers a
+		JOIN feedbacks f ON a.feedback_id = f.id
+		WHERE f.conference_id = $1
+		AND f.deleted_at IS NULL
+		AND f.hidden_at IS NULL
+		GROUP BY a.question_id`, conferenceID); err != nil {
+		return nil, 0, err
+	}
+
+	var respondents int
+	if err := r.db.GetContext(ctx, &respondents, `
+		SELECT COUNT(DISTINCT a.feedback_id)
+		FROM survey_answers a
+		JOIN feedbacks f ON a.feedback_id = f.id
+		WHERE f.conference_id = $1
+		AND f.deleted_at IS NULL
+		AND f.hidden_at IS NULL`, conferenceID); err != nil {
+		return nil, 0, err
+	}
+
+	return rows, respondents, nil
+}
+
+func (r *surveyRepository) GetAnswersByConference(ctx context.Context,
+	conferenceID uuid.UUID) ([]entity.Feedback, error) {
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT f.id, f.created_at, a.question_id, a.answer_index, a.rating_value, a.choice_value, a.text_value
+		FROM feedbacks f
+		JOIN survey_answers a ON a.feedback_id = f.id
+		WHERE f.conference_id = $1
+		AND f.deleted_at IS NULL
+		AND f.hidden_at IS NULL
+		ORDER BY f.id, a.question_id, a.answer_index`, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var feedbacks []entity.Feedback
+	for rows.Next() {
+		var feedback entity.Feedback
+		var answer entity.SurveyAnswer
+		if err = rows.Scan(&feedback.ID, &feedback.CreatedAt, &answer.QuestionID, &answer.AnswerIndex,
+			&answer.RatingValue, &answer.ChoiceValue, &answer.TextValue); err != nil {
+			return nil, err
+		}
+		answer.FeedbackID = feedback.ID
+
+		// Rows are ordered by feedback, so answers of the same feedback are next to each other
+		if len(feedbacks) == 0 || feedbacks[len(feedbacks)-1].ID != feedback.ID {
+			feedback.ConferenceID = conferenceID
+			feedbacks = append(feedbacks, feedback)
+		}
+
+		last := &feedbacks[len(feedbacks)-1]
+		last.SurveyAnswers = append(last.SurveyAnswers, answer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return feedbacks, nil
+}
@@ -0,0 +1,646 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/contract"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/dto"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/entity"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/log"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/uuidpkg"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/validator"
+)
+
+const defaultRatingScale = 5
+
+type surveyService struct {
+	r             contract.ISurveyRepository
+	conferenceSvc contract.IConferenceService
+	val           validator.IValidator
+	uuid          uuidpkg.IUUID
+}
+
+func NewSurveyService(
+	surveyRepo contract.ISurveyRepository,
+	conferenceSvc contract.IConferenceService,
+	val validator.IValidator,
+	uuid uuidpkg.IUUID,
+) contract.ISurveyService {
+
+	return &surveyService{
+		r:             surveyRepo,
+		conferenceSvc: conferenceSvc,
+		val:           val,
+		uuid:          uuid,
+	}
+}
+
+func (s *surveyService) CreateTemplate(ctx context.Context,
+	req dto.CreateSurveyTemplateRequest) (dto.SurveyTemplateResponse, error) {
+
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	templateID, err := s.uuid.NewV7()
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"requester.id": requesterID,
+		}, "[SurveyService][CreateTemplate] Failed to generate template ID")
+		return dto.SurveyTemplateResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	now := time.Now()
+	template := entity.SurveyTemplate{
+		ID:          templateID,
+		Title:       req.Title,
+		Description: req.Description,
+		CreatedBy:   &requesterID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Questions:   make([]entity.SurveyQuestion, len(req.Questions)),
+	}
+
+	for i, questionReq := range req.Questions {
+		isChoice := questionReq.Type == enum.SurveySingleChoice || questionReq.Type == enum.SurveyMultipleChoice
+		if isChoice != (len(questionReq.Options) > 0) {
+			return dto.SurveyTemplateResponse{}, errorpkg.ErrInvalidSurveyQuestion.WithDetail(map[string]interface{}{
+				"position": i,
+				"reason":   "Options are required for choice questions and not allowed for other questions.",
+			})
+		}
+
+		if questionReq.ScaleMax != nil && questionReq.Type != enum.SurveyRating {
+			return dto.SurveyTemplateResponse{}, errorpkg.ErrInvalidSurveyQuestion.WithDetail(map[string]interface{}{
+				"position": i,
+				"reason":   "Scale is only allowed for rating questions.",
+			})
+		}
+
+		questionID, err2 := s.uuid.NewV7()
+		if err2 != nil {
+			traceID := log.ErrorWithTraceID(map[string]interface{}{
+				"error":        err2,
+				"requester.id": requesterID,
+			}, "[SurveyService][CreateTemplate] Failed to generate question ID")
+			return dto.SurveyTemplateResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+		}
+
+		question := entity.SurveyQuestion{
+			ID:         questionID,
+			TemplateID: templateID,
+			Position:   i,
+			Type:       questionReq.Type,
+			Prompt:     questionReq.Prompt,
+			Required:   questionReq.Required,
+			Options:    questionReq.Options,
+			ScaleMax:   questionReq.ScaleMax,
+		}
+		if question.Type == enum.SurveyRating && question.ScaleMax == nil {
+			scaleMax := defaultRatingScale
+			question.ScaleMax = &scaleMax
+		}
+
+		template.Questions[i] = question
+	}
+
+	if err = s.r.CreateTemplate(ctx, &template); err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"template":     template,
+			"requester.id": requesterID,
+		}, "[SurveyService][CreateTemplate] Failed to create template")
+		return dto.SurveyTemplateResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"template.id":  templateID,
+		"questions":    len(template.Questions),
+		"requester.id": requesterID,
+	}, "[SurveyService][CreateTemplate] Survey template created")
+
+	var resp dto.SurveyTemplateResponse
+	resp.PopulateFromEntity(&template)
+	return resp, nil
+}
+
+func (s *surveyService) GetTemplates(ctx context.Context,
+	lazyReq dto.LazyLoadQuery) ([]dto.SurveyTemplateResponse, dto.LazyLoadResponse, error) {
+
+	templates, lazyResp, err := s.r.GetTemplates(ctx, lazyReq)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"requester.id": ctx.Value("user.id"),
+		}, "[SurveyService][GetTemplates] Failed to get templates")
+		return nil, dto.LazyLoadResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	resp := make([]dto.SurveyTemplateResponse, len(templates))
+	for i := range templates {
+		resp[i].PopulateFromEntity(&templates[i])
+	}
+
+	return resp, lazyResp, nil
+}
+
+func (s *surveyService) getTemplate(ctx context.Context, id uuid.UUID, allowDeleted bool) (*entity.SurveyTemplate,
+	error) {
+
+	template, err := s.r.GetTemplateByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"template.id":  id,
+			"requester.id": ctx.Value("user.id"),
+		}, "[SurveyService][getTemplate] Failed to get template")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if template.DeletedAt != nil && !allowDeleted {
+		return nil, errorpkg.ErrNotFound
+	}
+
+	return template, nil
+}
+
+func (s *surveyService) GetTemplateByID(ctx context.Context, id uuid.UUID) (*dto.SurveyTemplateResponse, error) {
+	template, err := s.getTemplate(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(dto.SurveyTemplateResponse).PopulateFromEntity(template), nil
+}
+
+func (s *surveyService) DeleteTemplate(ctx context.Context, id uuid.UUID) error {
+	// Conferences the template is already attached to keep their survey
+	if err := s.r.DeleteTemplate(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"template.id":  id,
+			"requester.id": ctx.Value("user.id"),
+		}, "[SurveyService][DeleteTemplate] Failed to delete template")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"template.id":  id,
+		"requester.id": ctx.Value("user.id"),
+	}, "[SurveyService][DeleteTemplate] Survey template deleted")
+
+	return nil
+}
+
+// checkNoAnswers makes sure the survey of the conference can still be changed
+func (s *surveyService) checkNoAnswers(ctx context.Context, conferenceID uuid.UUID) error {
+	hasAnswers, err := s.r.HasAnswers(ctx, conferenceID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": conferenceID,
+			"requester.id":  ctx.Value("user.id"),
+		}, "[SurveyService][checkNoAnswers] Failed to check survey answers")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	if hasAnswers {
+		return errorpkg.ErrSurveyAlreadyAnswered
+	}
+
+	return nil
+}
+
+func (s *surveyService) AttachTemplate(ctx context.Context, conferenceID, templateID uuid.UUID) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	if _, err := s.conferenceSvc.GetConferenceForStaff(ctx, conferenceID); err != nil {
+		return err
+	}
+
+	if _, err := s.getTemplate(ctx, templateID, false); err != nil {
+		return err
+	}
+
+	if err := s.checkNoAnswers(ctx, conferenceID); err != nil {
+		return err
+	}
+
+	survey := entity.ConferenceSurvey{
+		ConferenceID: conferenceID,
+		TemplateID:   templateID,
+		AttachedBy:   &requesterID,
+		AttachedAt:   time.Now(),
+	}
+
+	if err := s.r.AttachTemplate(ctx, &survey); err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err,
+			"survey":       survey,
+			"requester.id": requesterID,
+		}, "[SurveyService][AttachTemplate] Failed to attach template")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"survey": survey,
+	}, "[SurveyService][AttachTemplate] Survey attached to conference")
+
+	return nil
+}
+
+func (s *surveyService) DetachTemplate(ctx context.Context, conferenceID uuid.UUID) error {
+	requesterID, _ := ctx.Value("user.id").(uuid.UUID)
+
+	if _, err := s.conferenceSvc.GetConferenceForStaff(ctx, conferenceID); err != nil {
+		return err
+	}
+
+	if err := s.checkNoAnswers(ctx, conferenceID); err != nil {
+		return err
+	}
+
+	if err := s.r.DetachTemplate(ctx, conferenceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errorpkg.ErrNotFound
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": conferenceID,
+			"requester.id":  requesterID,
+		}, "[SurveyService][DetachTemplate] Failed to detach template")
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	log.Info(map[string]interface{}{
+		"conference.id": conferenceID,
+		"requester.id":  requesterID,
+	}, "[SurveyService][DetachTemplate] Survey detached from conference")
+
+	return nil
+}
+
+// getConferenceTemplate returns the template attached to the conference, or nil if there is none
+func (s *surveyService) getConferenceTemplate(ctx context.Context,
+	conferenceID uuid.UUID) (*entity.SurveyTemplate, error) {
+
+	survey, err := s.r.GetConferenceSurvey(ctx, conferenceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": conferenceID,
+			"requester.id":  ctx.Value("user.id"),
+		}, "[SurveyService][getConferenceTemplate] Failed to get conference survey")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	return s.getTemplate(ctx, survey.TemplateID, true)
+}
+
+func (s *surveyService) GetConferenceSurvey(ctx context.Context,
+	conferenceID uuid.UUID) (*dto.SurveyTemplateResponse, error) {
+
+	// Goes through the conference service for its visibility rules
+	if _, err := s.conferenceSvc.GetConferenceByID(ctx, conferenceID); err != nil {
+		return nil, err
+	}
+
+	template, err := s.getConferenceTemplate(ctx, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if template == nil {
+		return nil, errorpkg.ErrNoSurveyAttached
+	}
+
+	return new(dto.SurveyTemplateResponse).PopulateFromEntity(template), nil
+}
+
+func invalidAnswer(questionID uuid.UUID, reason interface{}) error {
+	return errorpkg.ErrInvalidSurveyAnswer.WithDetail(map[string]interface{}{
+		"question_id": questionID,
+		"reason":      reason,
+	})
+}
+
+func (s *surveyService) ValidateAnswers(ctx context.Context, conferenceID uuid.UUID,
+	answers []dto.SurveyAnswerRequest) ([]entity.SurveyAnswer, error) {
+
+	template, err := s.getConferenceTemplate(ctx, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if template == nil {
+		if len(answers) > 0 {
+			return nil, errorpkg.ErrNoSurveyAttached
+		}
+		return nil, nil
+	}
+
+	answerByQuestion := make(map[uuid.UUID]dto.SurveyAnswerRequest, len(answers))
+	for _, answer := range answers {
+		if _, ok := answerByQuestion[answer.QuestionID]; ok {
+			return nil, invalidAnswer(answer.QuestionID, "Question is answered more than once.")
+		}
+		answerByQuestion[answer.QuestionID] = answer
+	}
+
+	var result []entity.SurveyAnswer
+	for _, question := range template.Questions {
+		answer, ok := answerByQuestion[question.ID]
+		delete(answerByQuestion, question.ID)
+
+		isEmpty := !ok || (answer.Rating == nil && len(answer.Choices) == 0 && answer.Text == nil)
+		if isEmpty {
+			if question.Required {
+				return nil, invalidAnswer(question.ID, "Question is required.")
+			}
+			continue
+		}
+
+		rows, err2 := s.validateAnswer(&question, &answer)
+		if err2 != nil {
+			return nil, err2
+		}
+		result = append(result, rows...)
+	}
+
+	// Whatever is left doesn't belong to this survey
+	for questionID := range answerByQuestion {
+		return nil, invalidAnswer(questionID, "Question is not part of this survey.")
+	}
+
+	return result, nil
+}
+
+// validateAnswer validates a single non-empty answer and turns it into answer rows
+func (s *surveyService) validateAnswer(question *entity.SurveyQuestion,
+	answer *dto.SurveyAnswerRequest) ([]entity.SurveyAnswer, error) {
+
+	switch question.Type {
+	case enum.SurveyRating:
+		if answer.Rating == nil || answer.Choices != nil || answer.Text != nil {
+			return nil, invalidAnswer(question.ID, "Rating questions only take a rating.")
+		}
+
+		scaleMax := defaultRatingScale
+		if question.ScaleMax != nil {
+			scaleMax = *question.ScaleMax
+		}
+		if err := s.val.ValidateVariable(*answer.Rating, "min=1,max="+strconv.Itoa(scaleMax)); err != nil {
+			return nil, invalidAnswer(question.ID, err)
+		}
+
+		return []entity.SurveyAnswer{{QuestionID: question.ID, RatingValue: answer.Rating}}, nil
+
+	case enum.SurveySingleChoice, enum.SurveyMultipleChoice:
+		if answer.Rating != nil || answer.Text != nil {
+			return nil, invalidAnswer(question.ID, "Choice questions only take choices.")
+		}
+
+		tag := "min=1,unique"
+		if question.Type == enum.SurveySingleChoice {
+			tag = "len=1"
+		}
+		if err := s.val.ValidateVariable(answer.Choices, tag); err != nil {
+			return nil, invalidAnswer(question.ID, err)
+		}
+
+		rows := make([]entity.SurveyAnswer, len(answer.Choices))
+		for i := range answer.Choices {
+			// Options may contain spaces, which the oneof tag can't express
+			if !slices.Contains(question.Options, answer.Choices[i]) {
+				return nil, invalidAnswer(question.ID, "Choice is not one of the options.")
+			}
+			rows[i] = entity.SurveyAnswer{QuestionID: question.ID, AnswerIndex: i, ChoiceValue: &answer.Choices[i]}
+		}
+
+		return rows, nil
+
+	default:
+		if answer.Text == nil || answer.Rating != nil || answer.Choices != nil {
+			return nil, invalidAnswer(question.ID, "Text questions only take text.")
+		}
+
+		if err := s.val.ValidateVariable(*answer.Text, "min=1,max=2000"); err != nil {
+			return nil, invalidAnswer(question.ID, err)
+		}
+
+		return []entity.SurveyAnswer{{QuestionID: question.ID, TextValue: answer.Text}}, nil
+	}
+}
+
+// getResultsTemplate checks the requester may see the results and loads the attached template
+func (s *surveyService) getResultsTemplate(ctx context.Context,
+	conferenceID uuid.UUID) (*entity.SurveyTemplate, error) {
+
+	if _, err := s.conferenceSvc.GetConferenceForStaff(ctx, conferenceID); err != nil {
+		return nil, err
+	}
+
+	template, err := s.getConferenceTemplate(ctx, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if template == nil {
+		return nil, errorpkg.ErrNoSurveyAttached
+	}
+
+	return template, nil
+}
+
+func (s *surveyService) GetResults(ctx context.Context, conferenceID uuid.UUID) (dto.SurveyResultsResponse, error) {
+	template, err := s.getResultsTemplate(ctx, conferenceID)
+	if err != nil {
+		return dto.SurveyResultsResponse{}, err
+	}
+
+	counts, respondents, err := s.r.CountAnswers(ctx, conferenceID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": conferenceID,
+			"requester.id":  ctx.Value("user.id"),
+		}, "[SurveyService][GetResults] Failed to count answers")
+		return dto.SurveyResultsResponse{}, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	resp := dto.SurveyResultsResponse{
+		ConferenceID: conferenceID,
+		Respondents:  respondents,
+		Questions:    make([]dto.SurveyQuestionResultResponse, len(template.Questions)),
+	}
+	resp.Template.PopulateFromEntity(template)
+
+	resultByQuestion := make(map[uuid.UUID]*dto.SurveyQuestionResultResponse, len(template.Questions))
+	for i, question := range template.Questions {
+		result := dto.SurveyQuestionResultResponse{
+			QuestionID: question.ID,
+			Type:       question.Type,
+			Prompt:     question.Prompt,
+		}
+
+		// Every rating value and option shows up, even when nobody picked it
+		switch question.Type {
+		case enum.SurveyRating:
+			result.Distribution = make(map[string]int)
+			for value := 1; value <= *question.ScaleMax; value++ {
+				result.Distribution[strconv.Itoa(value)] = 0
+			}
+		case enum.SurveySingleChoice, enum.SurveyMultipleChoice:
+			result.Distribution = make(map[string]int, len(question.Options))
+			for _, option := range question.Options {
+				result.Distribution[option] = 0
+			}
+		}
+
+		resp.Questions[i] = result
+		resultByQuestion[question.ID] = &resp.Questions[i]
+	}
+
+	sums := make(map[uuid.UUID]int)
+	for _, count := range counts {
+		result, ok := resultByQuestion[count.QuestionID]
+		if !ok {
+			continue
+		}
+
+		if count.Value == nil {
+			result.Responses = count.Count
+			continue
+		}
+
+		result.Distribution[*count.Value] += count.Count
+		if result.Type == enum.SurveyRating {
+			value, _ := strconv.Atoi(*count.Value)
+			sums[count.QuestionID] += value * count.Count
+		}
+	}
+
+	for i := range resp.Questions {
+		result := &resp.Questions[i]
+		if result.Type == enum.SurveyRating && result.Responses > 0 {
+			avg := math.Round(float64(sums[result.QuestionID])/float64(result.Responses)*100) / 100
+			result.Average = &avg
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *surveyService) ExportResponses(ctx context.Context, conferenceID uuid.UUID) ([]byte, error) {
+	template, err := s.getResultsTemplate(ctx, conferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	feedbacks, err := s.r.GetAnswersByConference(ctx, conferenceID)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": conferenceID,
+			"requester.id":  ctx.Value("user.id"),
+		}, "[SurveyService][ExportResponses] Failed to get answers")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	columnByQuestion := make(map[uuid.UUID]int, len(template.Questions))
+	header := []string{"feedback_id", "submitted_at"}
+	for _, question := range template.Questions {
+		columnByQuestion[question.ID] = len(header)
+		header = append(header, csvCell(question.Prompt))
+	}
+
+	// Respondents are identified by feedback only, so anonymous feedback stays anonymous
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err = writer.Write(header); err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": conferenceID,
+		}, "[SurveyService][ExportResponses] Failed to write CSV header")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	for _, feedback := range feedbacks {
+		record := make([]string, len(header))
+		record[0] = feedback.ID.String()
+		record[1] = feedback.CreatedAt.Format(time.RFC3339)
+
+		values := make(map[int][]string)
+		for _, answer := range feedback.SurveyAnswers {
+			column, ok := columnByQuestion[answer.QuestionID]
+			if !ok {
+				continue
+			}
+
+			switch {
+			case answer.RatingValue != nil:
+				values[column] = append(values[column], strconv.Itoa(*answer.RatingValue))
+			case answer.ChoiceValue != nil:
+				values[column] = append(values[column], *answer.ChoiceValue)
+			case answer.TextValue != nil:
+				values[column] = append(values[column], *answer.TextValue)
+			}
+		}
+
+		for column, value := range values {
+			record[column] = csvCell(strings.Join(value, "; "))
+		}
+
+		if err = writer.Write(record); err != nil {
+			traceID := log.ErrorWithTraceID(map[string]interface{}{
+				"error":         err,
+				"conference.id": conferenceID,
+			}, "[SurveyService][ExportResponses] Failed to write CSV record")
+			return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+		}
+	}
+
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":         err,
+			"conference.id": conferenceID,
+		}, "[SurveyService][ExportResponses] Failed to flush CSV")
+		return nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// csvCell keeps a spreadsheet from reading the value as a formula by prefixing it with a quote
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
@@ -0,0 +1,27 @@
+package service
+
+import "testing"
+
+func TestCSVCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: ""},
+		{value: "Great talk", want: "Great talk"},
+		{value: "5", want: "5"},
+		{value: "a=b", want: "a=b"},
+		{value: "=HYPERLINK(\"http://evil.test\")", want: "'=HYPERLINK(\"http://evil.test\")"},
+		{value: "+1+1", want: "'+1+1"},
+		{value: "-2+3", want: "'-2+3"},
+		{value: "@SUM(A1:A2)", want: "'@SUM(A1:A2)"},
+		{value: "\t=1+1", want: "'\t=1+1"},
+		{value: "\r=1+1", want: "'\r=1+1"},
+	}
+
+	for _, tt := range tests {
+		if got := csvCell(tt.value); got != tt.want {
+			t.Errorf("csvCell(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
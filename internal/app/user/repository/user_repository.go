@@ -92,6 +92,25 @@ func (r *userRepository) UpdateUser(ctx context.Context, user *entity.User) erro
 	return r.updateUser(ctx, r.conn, user)
 }
 
+func (r *userRepository) UpdatePasswordHash(ctx context.Context, id uuid.UUID, oldHash, newHash string) error {
+	res, err := r.conn.ExecContext(ctx,
+		`UPDATE users SET password_hash = $3 WHERE id = $1 AND password_hash = $2`, id, oldHash, newHash)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
 func (r *userRepository) deleteUser(ctx context.Context, tx sqlx.ExtContext, id uuid.UUID) error {
 	res, err := tx.ExecContext(ctx,
 		`UPDATE users SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL`, id)
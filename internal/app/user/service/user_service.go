@@ -2,12 +2,14 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
 	"database/sql"
 	"errors"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
 	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/hasher"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/log"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/supabase"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/uuidpkg"
@@ -20,19 +22,20 @@ import (
 
 type userService struct {
 	userRepo contract.IUserRepository
-	// bcrypt   bcrypt.IBcrypt
+	hasher   hasher.IHasher
 	supabase supabase.ISupabase
 	uuid     uuidpkg.IUUID
 }
 
 func NewUserService(
 	userRepo contract.IUserRepository,
-	// bcrypt bcrypt.IBcrypt,
+	hasher hasher.IHasher,
 	supabase supabase.ISupabase,
 	uuid uuidpkg.IUUID,
 ) contract.IUserService {
 	return &userService{
 		userRepo: userRepo,
+		hasher:   hasher,
 		supabase: supabase,
 		uuid:     uuid,
 	}
@@ -59,25 +62,29 @@ func (s *userService) CreateUser(ctx context.Context, req *dto.CreateUserRequest
 		return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
 	}
 
-	// Deleted For Cryptographic Failures Vulnerable.
-	// passwordHash, err := s.bcrypt.Hash(req.Password)
-	// if err != nil {
-	// 	traceID := log.ErrorWithTraceID(map[string]interface{}{
-	// 		"error":        err.Error(),
-	// 		"request":      loggableReq,
-	// 		"requester.id": creatorID,
-	// 	}, "[UserService][CreateUser] Failed to hash password")
+	passwordHash, err := s.hasher.Hash(req.Password)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":        err.Error(),
+			"request":      loggableReq,
+			"requester.id": creatorID,
+		}, "[UserService][CreateUser] Failed to hash password")
+
+		return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
 
-	// 	return uuid.Nil, errorpkg.ErrInternalServer.WithTraceID(traceID)
-	// }
+	role := req.Role
+	if role == "" {
+		role = enum.RoleUser
+	}
 
 	// create user data
 	user := &entity.User{
 		ID:           userID,
 		Name:         req.Name,
 		Email:        req.Email,
-		PasswordHash: req.Password,
-		Role:         enum.RoleUser,
+		PasswordHash: passwordHash,
+		Role:         role,
 	}
 
 	err = s.userRepo.CreateUser(ctx, user)
@@ -136,6 +143,49 @@ func (s *userService) GetUserByID(ctx context.Context, id uuid.UUID) (*entity.Us
 	return s.getUserByField(ctx, "id", id.String())
 }
 
+func (s *userService) CheckPassword(ctx context.Context, user *entity.User, password string) bool {
+	var ok bool
+	if hasher.IsHashed(user.PasswordHash) {
+		ok = s.hasher.Compare(password, user.PasswordHash)
+	} else {
+		// Legacy row from before passwords were hashed
+		ok = subtle.ConstantTimeCompare([]byte(password), []byte(user.PasswordHash)) == 1
+	}
+
+	if !ok || !s.hasher.NeedsRehash(user.PasswordHash) {
+		return ok
+	}
+
+	// The password is known to be correct here, so the row can be upgraded in place.
+	// Failing to do so must not fail the login, the next one will try again.
+	newPasswordHash, err := s.hasher.Hash(password)
+	if err != nil {
+		log.Error(map[string]interface{}{
+			"error":   err.Error(),
+			"user.id": user.ID,
+		}, "[UserService][CheckPassword] Failed to rehash password")
+		return ok
+	}
+
+	err = s.userRepo.UpdatePasswordHash(ctx, user.ID, user.PasswordHash, newPasswordHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Error(map[string]interface{}{
+			"error":   err.Error(),
+			"user.id": user.ID,
+		}, "[UserService][CheckPassword] Failed to store rehashed password")
+		return ok
+	}
+
+	if err == nil {
+		user.PasswordHash = newPasswordHash
+		log.Info(map[string]interface{}{
+			"user.id": user.ID,
+		}, "[UserService][CheckPassword] Password rehashed")
+	}
+
+	return ok
+}
+
 func (s *userService) UpdatePassword(ctx context.Context, email, newPassword string) error {
 	// get user by email
 	user, err := s.GetUserByEmail(ctx, email)
@@ -143,19 +193,18 @@ func (s *userService) UpdatePassword(ctx context.Context, email, newPassword str
 		return err
 	}
 
-	// Deleted for Cryptographic Failures.
-	// newPasswordHash, err := s.bcrypt.Hash(newPassword)
-	// if err != nil {
-	// 	traceID := log.ErrorWithTraceID(map[string]interface{}{
-	// 		"error":      err.Error(),
-	// 		"user.email": email,
-	// 	}, "[UserService][UpdatePassword] Failed to hash password")
+	newPasswordHash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		traceID := log.ErrorWithTraceID(map[string]interface{}{
+			"error":      err.Error(),
+			"user.email": email,
+		}, "[UserService][UpdatePassword] Failed to hash password")
 
-	// 	return errorpkg.ErrInternalServer.WithTraceID(traceID)
-	// }
+		return errorpkg.ErrInternalServer.WithTraceID(traceID)
+	}
 
 	// update user password
-	user.PasswordHash = newPassword
+	user.PasswordHash = newPasswordHash
 	err = s.userRepo.UpdateUser(ctx, user)
 	if err != nil {
 		traceID := log.ErrorWithTraceID(map[string]interface{}{
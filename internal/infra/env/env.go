@@ -3,36 +3,64 @@ package env
 import (
 	"fmt"
 	"github.com/iamolegga/enviper"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 	"os"
+	"strings"
 	"sync"
 	"time"
 )
 
 type Env struct {
-	AppEnv                   string        `mapstructure:"APP_ENV"`
-	AppPort                  string        `mapstructure:"APP_PORT"`
-	AppURL                   string        `mapstructure:"APP_URL"`
-	FrontendURL              string        `mapstructure:"FRONTEND_URL"`
-	AppName                  string        `mapstructure:"APP_NAME"`
-	DBHost                   string        `mapstructure:"DB_HOST"`
-	DBPort                   string        `mapstructure:"DB_PORT"`
-	DBUser                   string        `mapstructure:"DB_USER"`
-	DBPass                   string        `mapstructure:"DB_PASS"`
-	DBName                   string        `mapstructure:"DB_NAME"`
-	RedisHost                string        `mapstructure:"REDIS_HOST"`
-	RedisPort                string        `mapstructure:"REDIS_PORT"`
-	RedisPass                string        `mapstructure:"REDIS_PASS"`
-	RedisDB                  int           `mapstructure:"REDIS_DB"`
-	JwtAccessSecretKey       []byte        // JWT_ACCESS_SECRET_KEY
-	JwtAccessExpireDuration  time.Duration // JWT_ACCESS_EXPIRE_DURATION
-	JwtRefreshExpireDuration time.Duration // JWT_REFRESH_EXPIRE_DURATION
-	SmtpHost                 string        `mapstructure:"SMTP_HOST"`
-	SmtpPort                 int           `mapstructure:"SMTP_PORT"`
-	SmtpUsername             string        `mapstructure:"SMTP_USERNAME"`
-	SmtpEmail                string        `mapstructure:"SMTP_EMAIL"`
-	SmtpPassword             string        `mapstructure:"SMTP_PASSWORD"`
+	AppEnv                    string                   `mapstructure:"APP_ENV"`
+	AppPort                   string                   `mapstructure:"APP_PORT"`
+	AppURL                    string                   `mapstructure:"APP_URL"`
+	FrontendURL               string                   `mapstructure:"FRONTEND_URL"`
+	AppName                   string                   `mapstructure:"APP_NAME"`
+	DBHost                    string                   `mapstructure:"DB_HOST"`
+	DBPort                    string                   `mapstructure:"DB_PORT"`
+	DBUser                    string                   `mapstructure:"DB_USER"`
+	DBPass                    string                   `mapstructure:"DB_PASS"`
+	DBName                    string                   `mapstructure:"DB_NAME"`
+	RedisHost                 string                   `mapstructure:"REDIS_HOST"`
+	RedisPort                 string                   `mapstructure:"REDIS_PORT"`
+	RedisPass                 string                   `mapstructure:"REDIS_PASS"`
+	RedisDB                   int                      `mapstructure:"REDIS_DB"`
+	JwtAccessSecretKey        []byte                   // JWT_ACCESS_SECRET_KEY
+	JwtAccessKeysDir          string                   `mapstructure:"JWT_ACCESS_KEYS_DIR"`
+	JwtAccessSigningKeyID     string                   `mapstructure:"JWT_ACCESS_SIGNING_KEY_ID"`
+	JwtAccessExpireDuration   time.Duration            // JWT_ACCESS_EXPIRE_DURATION
+	JwtRefreshExpireDuration  time.Duration            // JWT_REFRESH_EXPIRE_DURATION
+	TicketSecretKey           []byte                   // TICKET_SECRET_KEY
+	KioskSecretKey            []byte                   // KIOSK_SECRET_KEY
+	OTPSecretKey              []byte                   // OTP_SECRET_KEY
+	OTPMaxAttempts            int                      `mapstructure:"OTP_MAX_ATTEMPTS"`
+	OTPResendCooldown         time.Duration            // OTP_RESEND_COOLDOWN
+	OTPLockDuration           time.Duration            // OTP_LOCK_DURATION
+	MFASecretKey              []byte                   // MFA_SECRET_KEY
+	LoginMaxFailures          int                      `mapstructure:"LOGIN_MAX_FAILURES"`
+	LoginIPMaxFailures        int                      `mapstructure:"LOGIN_IP_MAX_FAILURES"`
+	LoginFailureWindow        time.Duration            // LOGIN_FAILURE_WINDOW
+	LoginLockDuration         time.Duration            // LOGIN_LOCK_DURATION
+	LoginMaxLockDuration      time.Duration            // LOGIN_MAX_LOCK_DURATION
+	OIDCIssuerURL             string                   `mapstructure:"OIDC_ISSUER_URL"`
+	OIDCClientID              string                   `mapstructure:"OIDC_CLIENT_ID"`
+	OIDCClientSecret          string                   `mapstructure:"OIDC_CLIENT_SECRET"`
+	OIDCRedirectURL           string                   `mapstructure:"OIDC_REDIRECT_URL"`
+	OIDCRoleClaim             string                   `mapstructure:"OIDC_ROLE_CLAIM"`
+	OIDCRoleMapping           map[string]enum.UserRole // OIDC_ROLE_MAPPING
+	TrustedProxies            string                   `mapstructure:"TRUSTED_PROXIES"`
+	SmtpHost                  string                   `mapstructure:"SMTP_HOST"`
+	SmtpPort                  int                      `mapstructure:"SMTP_PORT"`
+	SmtpUsername              string                   `mapstructure:"SMTP_USERNAME"`
+	SmtpEmail                 string                   `mapstructure:"SMTP_EMAIL"`
+	SmtpPassword              string                   `mapstructure:"SMTP_PASSWORD"`
+	WaitlistOfferDuration     time.Duration            // WAITLIST_OFFER_DURATION
+	RegistrationCancelCutoff  time.Duration            // REGISTRATION_CANCEL_CUTOFF
+	RescheduleReconfirmWindow time.Duration            // RESCHEDULE_RECONFIRM_WINDOW
+	FeedbackAutoHideFlags     int                      `mapstructure:"FEEDBACK_AUTO_HIDE_FLAGS"`
+	PasswordHashAlgorithm     string                   `mapstructure:"PASSWORD_HASH_ALGORITHM"`
 }
 
 var (
@@ -84,11 +112,48 @@ func NewEnv() *Env {
 
 		// Process JWT configurations
 		env.JwtAccessSecretKey = []byte(viperInstance.GetString("JWT_ACCESS_SECRET_KEY"))
+		env.TicketSecretKey = []byte(viperInstance.GetString("TICKET_SECRET_KEY"))
+		env.KioskSecretKey = []byte(viperInstance.GetString("KIOSK_SECRET_KEY"))
+		env.OTPSecretKey = []byte(viperInstance.GetString("OTP_SECRET_KEY"))
+		env.MFASecretKey = []byte(viperInstance.GetString("MFA_SECRET_KEY"))
+
+		// OTPs are only six digits, without a secret their stored hashes are reversed in no time
+		if len(env.OTPSecretKey) == 0 {
+			log.Fatal().Msg("[ENV] OTP_SECRET_KEY must not be empty")
+		}
+
+		// Wrong OTPs allowed before the email is locked out
+		if env.OTPMaxAttempts <= 0 {
+			env.OTPMaxAttempts = 5
+		}
+
+		// Failed logins before the account or IP address is locked out.
+		// An IP address may be shared by many users, so it gets more room.
+		if env.LoginMaxFailures <= 0 {
+			env.LoginMaxFailures = 5
+		}
+		if env.LoginIPMaxFailures <= 0 {
+			env.LoginIPMaxFailures = 50
+		}
+
+		// Open flags needed before feedback is hidden automatically
+		if env.FeedbackAutoHideFlags <= 0 {
+			env.FeedbackAutoHideFlags = 3
+		}
+
+		// Claim of the ID token holding the identity provider's groups
+		if env.OIDCRoleClaim == "" {
+			env.OIDCRoleClaim = "groups"
+		}
 
 		// Parse durations
 		if err := parseDurations(env); err != nil {
 			log.Fatal().Msgf("[ENV] failed to parse durations: %s", err.Error())
 		}
+
+		if err := parseOIDCRoleMapping(env); err != nil {
+			log.Fatal().Msgf("[ENV] failed to parse OIDC role mapping: %s", err.Error())
+		}
 	})
 
 	return env
@@ -117,5 +182,89 @@ func parseDurations(env *Env) error {
 		return fmt.Errorf("invalid JWT_REFRESH_EXPIRE_DURATION: %w", err)
 	}
 
+	env.WaitlistOfferDuration, err = parseDurationOrDefault("WAITLIST_OFFER_DURATION", 24*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	env.RegistrationCancelCutoff, err = parseDurationOrDefault("REGISTRATION_CANCEL_CUTOFF", 24*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	env.RescheduleReconfirmWindow, err = parseDurationOrDefault("RESCHEDULE_RECONFIRM_WINDOW", 72*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	env.OTPResendCooldown, err = parseDurationOrDefault("OTP_RESEND_COOLDOWN", time.Minute)
+	if err != nil {
+		return err
+	}
+
+	env.OTPLockDuration, err = parseDurationOrDefault("OTP_LOCK_DURATION", 30*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	env.LoginFailureWindow, err = parseDurationOrDefault("LOGIN_FAILURE_WINDOW", 15*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	// Doubled with every failure past the limit, up to LOGIN_MAX_LOCK_DURATION
+	env.LoginLockDuration, err = parseDurationOrDefault("LOGIN_LOCK_DURATION", time.Minute)
+	if err != nil {
+		return err
+	}
+
+	env.LoginMaxLockDuration, err = parseDurationOrDefault("LOGIN_MAX_LOCK_DURATION", time.Hour)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// parseOIDCRoleMapping reads OIDC_ROLE_MAPPING, comma-separated claim=role pairs such as
+// "auditorium-admins=admin,event-office=event_coordinator"
+func parseOIDCRoleMapping(env *Env) error {
+	env.OIDCRoleMapping = make(map[string]enum.UserRole)
+
+	for _, pair := range strings.Split(viperInstance.GetString("OIDC_ROLE_MAPPING"), ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		claim, role, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("%q is not a claim=role pair", pair)
+		}
+
+		userRole := enum.UserRole(strings.TrimSpace(role))
+		switch userRole {
+		case enum.RoleAdmin, enum.RoleEventCoordinator, enum.RoleUser:
+		default:
+			return fmt.Errorf("unknown role %q", role)
+		}
+
+		env.OIDCRoleMapping[strings.TrimSpace(claim)] = userRole
+	}
+
 	return nil
 }
+
+// Helper function to parse optional durations, falling back to a default when unset
+func parseDurationOrDefault(key string, fallback time.Duration) (time.Duration, error) {
+	value := viperInstance.GetString(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return duration, nil
+}
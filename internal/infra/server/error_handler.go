@@ -2,9 +2,11 @@ package server
 
 import (
 	"errors"
-	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
+	"math"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/errorpkg"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/validator"
 )
 
@@ -12,6 +14,12 @@ func ErrorHandler() fiber.ErrorHandler {
 	return func(ctx *fiber.Ctx, err error) error {
 		var apiErr *errorpkg.ErrorResponse
 		if errors.As(err, &apiErr) {
+			if apiErr.RetryAfter > 0 {
+				// Rounded up, so clients never retry too early
+				seconds := int(math.Ceil(apiErr.RetryAfter.Seconds()))
+				ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+			}
+
 			return ctx.Status(apiErr.HttpStatusCode).JSON(apiErr)
 		}
 
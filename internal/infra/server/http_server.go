@@ -1,6 +1,9 @@
 package server
 
 import (
+	"strings"
+	"time"
+
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jmoiron/sqlx"
@@ -13,18 +16,33 @@ import (
 	feedbackhnd "github.com/nathakusuma/auditorium-reservation-backend/internal/app/feedback/handler"
 	feedbackrepo "github.com/nathakusuma/auditorium-reservation-backend/internal/app/feedback/repository"
 	feedbacksvc "github.com/nathakusuma/auditorium-reservation-backend/internal/app/feedback/service"
+	kioskhnd "github.com/nathakusuma/auditorium-reservation-backend/internal/app/kiosk/handler"
+	kioskrepo "github.com/nathakusuma/auditorium-reservation-backend/internal/app/kiosk/repository"
+	kiosksvc "github.com/nathakusuma/auditorium-reservation-backend/internal/app/kiosk/service"
+	mfahnd "github.com/nathakusuma/auditorium-reservation-backend/internal/app/mfa/handler"
+	mfarepo "github.com/nathakusuma/auditorium-reservation-backend/internal/app/mfa/repository"
+	mfasvc "github.com/nathakusuma/auditorium-reservation-backend/internal/app/mfa/service"
 	registrationhnd "github.com/nathakusuma/auditorium-reservation-backend/internal/app/registration/handler"
 	registrationrepo "github.com/nathakusuma/auditorium-reservation-backend/internal/app/registration/repository"
 	registrationsvc "github.com/nathakusuma/auditorium-reservation-backend/internal/app/registration/service"
+	roomhnd "github.com/nathakusuma/auditorium-reservation-backend/internal/app/room/handler"
+	roomrepo "github.com/nathakusuma/auditorium-reservation-backend/internal/app/room/repository"
+	roomsvc "github.com/nathakusuma/auditorium-reservation-backend/internal/app/room/service"
+	surveyhnd "github.com/nathakusuma/auditorium-reservation-backend/internal/app/survey/handler"
+	surveyrepo "github.com/nathakusuma/auditorium-reservation-backend/internal/app/survey/repository"
+	surveysvc "github.com/nathakusuma/auditorium-reservation-backend/internal/app/survey/service"
 	userhnd "github.com/nathakusuma/auditorium-reservation-backend/internal/app/user/handler"
 	userrepo "github.com/nathakusuma/auditorium-reservation-backend/internal/app/user/repository"
 	usersvc "github.com/nathakusuma/auditorium-reservation-backend/internal/app/user/service"
 	"github.com/nathakusuma/auditorium-reservation-backend/internal/infra/env"
 	"github.com/nathakusuma/auditorium-reservation-backend/internal/middleware"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/hasher"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/jwt"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/log"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/mail"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/oidc"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/supabase"
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/totp"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/uuidpkg"
 	"github.com/nathakusuma/auditorium-reservation-backend/pkg/validator"
 	"github.com/redis/go-redis/v9"
@@ -49,6 +67,17 @@ func NewHttpServer() HttpServer {
 		ErrorHandler: ErrorHandler(),
 	}
 
+	// Behind a proxy, ctx.IP() would be the proxy's address for every request,
+	// so the client IP is taken from X-Forwarded-For, but only when a trusted proxy sent it
+	if trustedProxies := env.GetEnv().TrustedProxies; trustedProxies != "" {
+		config.ProxyHeader = fiber.HeaderXForwardedFor
+		config.EnableTrustedProxyCheck = true
+		config.EnableIPValidation = true
+		for _, proxy := range strings.Split(trustedProxies, ",") {
+			config.TrustedProxies = append(config.TrustedProxies, strings.TrimSpace(proxy))
+		}
+	}
+
 	app := fiber.New(config)
 
 	return &httpServer{
@@ -83,38 +112,109 @@ func (s *httpServer) MountMiddlewares() {
 }
 
 func (s *httpServer) MountRoutes(db *sqlx.DB, rds *redis.Client) {
-	// Deleted for Cryptographic Failures.
-	// bcryptInstance := bcrypt.GetBcrypt()
-	jwtAccess := jwt.NewJwt(env.GetEnv().JwtAccessExpireDuration, env.GetEnv().JwtAccessSecretKey)
+	hasherInstance, err := hasher.NewHasher(env.GetEnv().PasswordHashAlgorithm)
+	if err != nil {
+		log.Fatal(map[string]interface{}{
+			"error": err.Error(),
+		}, "[SERVER][MountRoutes] failed to create password hasher")
+	}
+
+	accessKeyring, err := jwt.LoadKeyring(env.GetEnv().JwtAccessKeysDir, env.GetEnv().JwtAccessSigningKeyID,
+		env.GetEnv().JwtAccessSecretKey)
+	if err != nil {
+		log.Fatal(map[string]interface{}{
+			"error": err.Error(),
+		}, "[SERVER][MountRoutes] failed to load access token keys")
+	}
+
+	totpInstance, err := totp.NewTOTP("Auditorium Reservation", env.GetEnv().MFASecretKey)
+	if err != nil {
+		log.Fatal(map[string]interface{}{
+			"error": err.Error(),
+		}, "[SERVER][MountRoutes] failed to create totp")
+	}
+
+	ticket, err := jwt.NewTicket(env.GetEnv().TicketSecretKey)
+	if err != nil {
+		log.Fatal(map[string]interface{}{
+			"error": err.Error(),
+		}, "[SERVER][MountRoutes] failed to create ticket signer")
+	}
+
+	kiosk, err := jwt.NewKiosk(env.GetEnv().KioskSecretKey)
+	if err != nil {
+		log.Fatal(map[string]interface{}{
+			"error": err.Error(),
+		}, "[SERVER][MountRoutes] failed to create kiosk signer")
+	}
+
+	// Campus login is optional, it's off unless an identity provider is configured
+	var oidcProvider oidc.IProvider
+	if env.GetEnv().OIDCIssuerURL != "" {
+		oidcProvider = oidc.NewProvider(oidc.Config{
+			IssuerURL:    env.GetEnv().OIDCIssuerURL,
+			ClientID:     env.GetEnv().OIDCClientID,
+			ClientSecret: env.GetEnv().OIDCClientSecret,
+			RedirectURL:  env.GetEnv().OIDCRedirectURL,
+		})
+	}
+
+	jwtAccess := jwt.NewJwt(env.GetEnv().JwtAccessExpireDuration, accessKeyring)
 	mailer := mail.NewMailDialer()
 	uuidInstance := uuidpkg.GetUUID()
 	validatorInstance := validator.NewValidator()
-	middlewareInstance := middleware.NewMiddleware(jwtAccess)
+	middlewareInstance := middleware.NewMiddleware(jwtAccess, kiosk)
 	supabase := supabase.New()
 
 	s.app.Get("/", func(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusOK).SendString("Healthy")
 	})
 
+	// Public keys for anyone verifying our access tokens
+	s.app.Get("/.well-known/jwks.json", func(ctx *fiber.Ctx) error {
+		ctx.Set(fiber.HeaderCacheControl, "public, max-age=300")
+		return ctx.JSON(jwtAccess.JWKS())
+	})
+
 	api := s.app.Group("/api")
 	v1 := api.Group("/v1")
 
 	userRepository := userrepo.NewUserRepository(db)
 	authRepository := authrepo.NewAuthRepository(db, rds)
+	mfaRepository := mfarepo.NewMFARepository(db)
+	roomRepository := roomrepo.NewRoomRepository(db)
 	conferenceRepository := conferencerepo.NewConferenceRepository(db)
 	registrationRepository := registrationrepo.NewRegistrationRepository(db)
 	feedbackRepository := feedbackrepo.NewFeedbackRepository(db)
-
-	userService := usersvc.NewUserService(userRepository, supabase, uuidInstance)
-	authService := authsvc.NewAuthService(authRepository, userService, jwtAccess, mailer, uuidInstance)
-	conferenceService := conferencesvc.NewConferenceService(conferenceRepository, uuidInstance)
-	registrationService := registrationsvc.NewRegistrationService(registrationRepository, conferenceService)
+	kioskRepository := kioskrepo.NewKioskRepository(db)
+	surveyRepository := surveyrepo.NewSurveyRepository(db)
+
+	userService := usersvc.NewUserService(userRepository, hasherInstance, supabase, uuidInstance)
+	mfaService := mfasvc.NewMFAService(mfaRepository, userService, totpInstance)
+	authService := authsvc.NewAuthService(authRepository, userService, mfaService, oidcProvider,
+		jwtAccess, mailer, uuidInstance)
+	roomService := roomsvc.NewRoomService(roomRepository, uuidInstance)
+	conferenceService := conferencesvc.NewConferenceService(conferenceRepository, registrationRepository,
+		roomService, mailer, uuidInstance)
+	registrationService := registrationsvc.NewRegistrationService(registrationRepository, conferenceService,
+		userService, mailer, ticket, uuidInstance)
+	surveyService := surveysvc.NewSurveyService(surveyRepository, conferenceService, validatorInstance, uuidInstance)
 	feedbackService := feedbacksvc.NewFeedbackService(feedbackRepository, registrationService, conferenceService,
-		uuidInstance)
+		surveyService, mailer, uuidInstance)
+	kioskService := kiosksvc.NewKioskService(kioskRepository, conferenceService, kiosk, ticket, uuidInstance)
 
 	userhnd.InitUserHandler(v1, middlewareInstance, validatorInstance, userService)
 	authhnd.InitAuthHandler(v1, middlewareInstance, validatorInstance, authService)
+	mfahnd.InitMFAHandler(v1, middlewareInstance, validatorInstance, mfaService)
+	roomhnd.InitRoomHandler(v1, middlewareInstance, validatorInstance, roomService)
 	conferencehnd.InitConferenceHandler(v1, middlewareInstance, validatorInstance, conferenceService)
 	registrationhnd.InitRegistrationHandler(v1, middlewareInstance, validatorInstance, registrationService)
 	feedbackhnd.InitFeedbackHandler(v1, middlewareInstance, validatorInstance, feedbackService)
+	kioskhnd.InitKioskHandler(v1, middlewareInstance, validatorInstance, kioskService)
+	surveyhnd.InitSurveyHandler(v1, middlewareInstance, validatorInstance, surveyService)
+
+	runPeriodically("ExpireWaitlistOffers", time.Minute, registrationService.ExpireWaitlistOffers)
+	runPeriodically("ReleaseUnconfirmedRegistrations", time.Minute,
+		registrationService.ReleaseUnconfirmedRegistrations)
+	runPeriodically("DeleteExpiredSessions", time.Hour, authService.DeleteExpiredSessions)
 }
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/nathakusuma/auditorium-reservation-backend/pkg/log"
+)
+
+// runPeriodically runs job in the background on every tick of interval for the lifetime of the process.
+// Jobs are expected to log their own failures; the returned error is only recorded here.
+func runPeriodically(name string, interval time.Duration, job func(ctx context.Context) error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			if err := job(ctx); err != nil {
+				log.Warn(map[string]interface{}{
+					"error": err.Error(),
+					"job":   name,
+				}, "[SERVER][runPeriodically] background job failed")
+			}
+			cancel()
+		}
+	}()
+}
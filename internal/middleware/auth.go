@@ -41,6 +41,37 @@ func (m *Middleware) RequireAuthenticated() fiber.Handler {
 
 		ctx.Locals("user.id", uuid.MustParse(claims.Subject))
 		ctx.Locals("user.role", claims.Role)
+		ctx.Locals("session.id", claims.SessionID)
+
+		return ctx.Next()
+	}
+}
+
+// RequireKioskDevice authenticates a kiosk device by its device token. It doesn't set a user in the context.
+func (m *Middleware) RequireKioskDevice() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		header := ctx.Get("Authorization")
+		if header == "" {
+			return errorpkg.ErrNoBearerToken
+		}
+
+		headerSlice := strings.Split(header, " ")
+		if len(headerSlice) != 2 || headerSlice[0] != "Bearer" {
+			return errorpkg.ErrInvalidBearerToken
+		}
+
+		var claims jwt.KioskDeviceClaims
+		err := m.kiosk.DecodeDeviceToken(headerSlice[1], &claims)
+		if err != nil {
+			return errorpkg.ErrInvalidBearerToken
+		}
+
+		deviceID, err := uuid.Parse(claims.Subject)
+		if err != nil {
+			return errorpkg.ErrInvalidBearerToken
+		}
+
+		ctx.Locals("kiosk.id", deviceID)
 
 		return ctx.Next()
 	}
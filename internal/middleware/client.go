@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxUserAgentLength = 512
+
+// ClientInfo puts the client's IP address and user agent in the context, for services that record them
+func (m *Middleware) ClientInfo() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		userAgent := ctx.Get(fiber.HeaderUserAgent)
+		if len(userAgent) > maxUserAgentLength {
+			// Cutting may split a multi-byte character, which the database would reject
+			userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+		}
+
+		ctx.Locals("client.ip", ctx.IP())
+		ctx.Locals("client.user_agent", userAgent)
+
+		return ctx.Next()
+	}
+}
@@ -3,13 +3,16 @@ package middleware
 import "github.com/nathakusuma/auditorium-reservation-backend/pkg/jwt"
 
 type Middleware struct {
-	jwt jwt.IJwt
+	jwt   jwt.IJwt
+	kiosk jwt.IKiosk
 }
 
 func NewMiddleware(
 	jwt jwt.IJwt,
+	kiosk jwt.IKiosk,
 ) *Middleware {
 	return &Middleware{
-		jwt: jwt,
+		jwt:   jwt,
+		kiosk: kiosk,
 	}
 }
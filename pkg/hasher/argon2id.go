@@ -0,0 +1,115 @@
+package hasher
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+// argon2idParams are encoded into every hash in the PHC string format:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+type argon2idParams struct {
+	memory     uint32 // in KiB
+	iterations uint32
+	threads    uint8
+	saltLength int
+	keyLength  uint32
+}
+
+type argon2idStruct struct {
+	params argon2idParams
+}
+
+func newArgon2id() algorithm {
+	return &argon2idStruct{
+		params: argon2idParams{
+			memory:     64 * 1024,
+			iterations: 3,
+			threads:    2,
+			saltLength: 16,
+			keyLength:  32,
+		},
+	}
+}
+
+func (a *argon2idStruct) identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2idPrefix)
+}
+
+func (a *argon2idStruct) Hash(plain string) (string, error) {
+	salt := make([]byte, a.params.saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(plain), salt, a.params.iterations, a.params.memory, a.params.threads,
+		a.params.keyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		a.params.memory, a.params.iterations, a.params.threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func decodeArgon2id(encoded string) (argon2idParams, []byte, []byte, error) {
+	var params argon2idParams
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, fmt.Errorf("invalid argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, err
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations,
+		&params.threads); err != nil {
+		return params, nil, nil, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	params.saltLength = len(salt)
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	params.keyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
+
+func (a *argon2idStruct) Compare(password, encoded string) bool {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.threads,
+		params.keyLength)
+
+	return subtle.ConstantTimeCompare(key, otherKey) == 1
+}
+
+func (a *argon2idStruct) NeedsRehash(encoded string) bool {
+	params, _, _, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+
+	return params != a.params
+}
@@ -0,0 +1,47 @@
+package hasher
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+const bcryptCost = 12
+
+type bcryptStruct struct {
+	cost int
+}
+
+func newBcrypt() algorithm {
+	return &bcryptStruct{
+		cost: bcryptCost,
+	}
+}
+
+func isBcryptHash(encoded string) bool {
+	// $2a$, $2b$ and $2y$ followed by a two digit cost, e.g. $2a$12$...
+	return len(encoded) == 60 && encoded[0] == '$' && encoded[1] == '2' && encoded[3] == '$'
+}
+
+func (b *bcryptStruct) identifies(encoded string) bool {
+	return isBcryptHash(encoded)
+}
+
+func (b *bcryptStruct) Hash(plain string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(plain), b.cost)
+
+	return string(bytes), err
+}
+
+func (b *bcryptStruct) Compare(password, encoded string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+
+	return err == nil
+}
+
+func (b *bcryptStruct) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return true
+	}
+
+	return cost < b.cost
+}
@@ -0,0 +1,104 @@
+package hasher
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+type IHasher interface {
+	Hash(plain string) (string, error)
+	// Compare reports whether the password matches the encoded hash.
+	// Values that aren't a known hash encoding never match.
+	Compare(password, encoded string) bool
+	// NeedsRehash reports whether the encoded hash was made with another algorithm or other parameters
+	// than the ones new hashes are made with.
+	NeedsRehash(encoded string) bool
+}
+
+// algorithm is a single hashing scheme. Its hashes carry their own parameters, so they can be compared
+// without any configuration.
+type algorithm interface {
+	IHasher
+	identifies(encoded string) bool
+}
+
+type hasherStruct struct {
+	current    algorithm
+	algorithms []algorithm
+}
+
+// NewHasher returns a hasher that makes new hashes with the given algorithm and still compares hashes
+// made with any of the supported ones.
+func NewHasher(name string) (IHasher, error) {
+	argon2id := newArgon2id()
+	bcrypt := newBcrypt()
+
+	h := &hasherStruct{
+		algorithms: []algorithm{argon2id, bcrypt},
+	}
+
+	switch name {
+	case AlgorithmArgon2id, "":
+		h.current = argon2id
+	case AlgorithmBcrypt:
+		h.current = bcrypt
+	default:
+		return nil, fmt.Errorf("unknown password hashing algorithm %q", name)
+	}
+
+	return h, nil
+}
+
+func (h *hasherStruct) find(encoded string) algorithm {
+	for _, alg := range h.algorithms {
+		if alg.identifies(encoded) {
+			return alg
+		}
+	}
+
+	return nil
+}
+
+func (h *hasherStruct) Hash(plain string) (string, error) {
+	return h.current.Hash(plain)
+}
+
+func (h *hasherStruct) Compare(password, encoded string) bool {
+	alg := h.find(encoded)
+	if alg == nil {
+		return false
+	}
+
+	return alg.Compare(password, encoded)
+}
+
+func (h *hasherStruct) NeedsRehash(encoded string) bool {
+	if !h.current.identifies(encoded) {
+		return true
+	}
+
+	return h.current.NeedsRehash(encoded)
+}
+
+// IsHashed reports whether the value is a well-formed hash made by one of the supported algorithms,
+// as opposed to a legacy plaintext password. A plaintext that merely starts like a hash is not one.
+func IsHashed(encoded string) bool {
+	if strings.HasPrefix(encoded, argon2idPrefix) {
+		_, _, _, err := decodeArgon2id(encoded)
+		return err == nil
+	}
+
+	if isBcryptHash(encoded) {
+		_, err := bcrypt.Cost([]byte(encoded))
+		return err == nil
+	}
+
+	return false
+}
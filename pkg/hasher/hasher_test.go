@@ -0,0 +1,166 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHasher_HashAndCompare(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		prefix    string
+	}{
+		{name: "argon2id", algorithm: AlgorithmArgon2id, prefix: argon2idPrefix},
+		{name: "default", algorithm: "", prefix: argon2idPrefix},
+		{name: "bcrypt", algorithm: AlgorithmBcrypt, prefix: "$2a$12$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewHasher(tt.algorithm)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			encoded, err := h.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !strings.HasPrefix(encoded, tt.prefix) {
+				t.Errorf("expected hash to start with %q, got %q", tt.prefix, encoded)
+			}
+			if !IsHashed(encoded) {
+				t.Errorf("expected %q to be recognized as a hash", encoded)
+			}
+			if !h.Compare("correct horse battery staple", encoded) {
+				t.Error("expected the password to match its hash")
+			}
+			if h.Compare("correct horse battery stapler", encoded) {
+				t.Error("expected another password not to match")
+			}
+			if h.NeedsRehash(encoded) {
+				t.Error("expected a fresh hash not to need a rehash")
+			}
+		})
+	}
+}
+
+func TestHasher_CompareAcrossAlgorithms(t *testing.T) {
+	argon2id, err := NewHasher(AlgorithmArgon2id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bcrypt, err := NewHasher(AlgorithmBcrypt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	encoded, err := bcrypt.Hash("secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !argon2id.Compare("secret", encoded) {
+		t.Error("expected a bcrypt hash to still be compared after switching to argon2id")
+	}
+	if !argon2id.NeedsRehash(encoded) {
+		t.Error("expected a bcrypt hash to need a rehash after switching to argon2id")
+	}
+}
+
+func TestNewHasher_UnknownAlgorithm(t *testing.T) {
+	if _, err := NewHasher("md5"); err == nil {
+		t.Error("expected an unknown algorithm to be rejected")
+	}
+}
+
+func TestIsHashed(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+		want    bool
+	}{
+		{name: "argon2id", encoded: "$argon2id$v=19$m=65536,t=3,p=2$c29tZXNhbHRzb21lc2FsdA$" +
+			"Y2hhbmdlbWVjaGFuZ2VtZWNoYW5nZW1lY2hhbmdlbWU", want: true},
+		{name: "bcrypt", encoded: "$2a$12$R9h/cIPz0gi.URNNX3kh2OPST9/PgBkqquzi.Ss7KIUgO2t0jWMUW", want: true},
+		{name: "empty", encoded: "", want: false},
+		{name: "plaintext", encoded: "hunter2", want: false},
+		{name: "plaintext starting with a dollar", encoded: "$ecretPassw0rd", want: false},
+		{name: "plaintext starting like argon2id", encoded: "$argon2id$not really", want: false},
+		{name: "argon2id with a bad salt", encoded: "$argon2id$v=19$m=65536,t=3,p=2$!!!$" +
+			"Y2hhbmdlbWVjaGFuZ2VtZWNoYW5nZW1lY2hhbmdlbWU", want: false},
+		{name: "plaintext shaped like bcrypt", encoded: "$2a$xx$" + strings.Repeat("a", 53), want: false},
+		{name: "bcrypt prefix but too short", encoded: "$2a$12$tooshort", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsHashed(tt.encoded); got != tt.want {
+				t.Errorf("IsHashed(%q) = %v, want %v", tt.encoded, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasher_CompareRejectsPlaintext(t *testing.T) {
+	h, err := NewHasher(AlgorithmArgon2id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A legacy plaintext row is never a hash, even when the password equals the stored value
+	for _, plain := range []string{"hunter2", "$ecretPassw0rd", "$argon2id$not really"} {
+		if h.Compare(plain, plain) {
+			t.Errorf("expected plaintext %q not to match itself as a hash", plain)
+		}
+		if !h.NeedsRehash(plain) {
+			t.Errorf("expected plaintext %q to need a rehash", plain)
+		}
+	}
+}
+
+func TestNeedsRehash_ParameterChange(t *testing.T) {
+	weakArgon2id := &argon2idStruct{
+		params: argon2idParams{
+			memory:     8 * 1024,
+			iterations: 1,
+			threads:    1,
+			saltLength: 16,
+			keyLength:  32,
+		},
+	}
+	weakBcrypt := &bcryptStruct{cost: 4}
+
+	tests := []struct {
+		name      string
+		algorithm string
+		old       algorithm
+	}{
+		{name: "argon2id with weaker parameters", algorithm: AlgorithmArgon2id, old: weakArgon2id},
+		{name: "bcrypt with a lower cost", algorithm: AlgorithmBcrypt, old: weakBcrypt},
+		{name: "argon2id after switching to bcrypt", algorithm: AlgorithmBcrypt, old: weakArgon2id},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewHasher(tt.algorithm)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			encoded, err := tt.old.Hash("secret")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !h.Compare("secret", encoded) {
+				t.Error("expected a hash made with older parameters to still match")
+			}
+			if !h.NeedsRehash(encoded) {
+				t.Error("expected a hash made with older parameters to need a rehash")
+			}
+		})
+	}
+}
@@ -9,28 +9,30 @@ import (
 )
 
 type IJwt interface {
-	Create(userID uuid.UUID, role enum.UserRole) (string, error)
+	Create(userID uuid.UUID, role enum.UserRole, sessionID uuid.UUID) (string, error)
 	Decode(tokenString string, claims *Claims) error
+	JWKS() JWKS
 }
 
 type Claims struct {
 	jwt.RegisteredClaims
-	Role enum.UserRole `json:"role"`
+	Role      enum.UserRole `json:"role"`
+	SessionID uuid.UUID     `json:"sid"`
 }
 
 type JwtStruct struct {
-	exp    time.Duration
-	secret []byte
+	exp     time.Duration
+	keyring *Keyring
 }
 
-func NewJwt(exp time.Duration, secret []byte) IJwt {
+func NewJwt(exp time.Duration, keyring *Keyring) IJwt {
 	return &JwtStruct{
-		exp:    exp,
-		secret: secret,
+		exp:     exp,
+		keyring: keyring,
 	}
 }
 
-func (j *JwtStruct) Create(userID uuid.UUID, role enum.UserRole) (string, error) {
+func (j *JwtStruct) Create(userID uuid.UUID, role enum.UserRole, sessionID uuid.UUID) (string, error) {
 	claims := Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    "auditorium-reservation-backend",
@@ -38,11 +40,11 @@ func (j *JwtStruct) Create(userID uuid.UUID, role enum.UserRole) (string, error)
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.exp)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
-		Role: role,
+		Role:      role,
+		SessionID: sessionID,
 	}
 
-	unsignedJWT := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedJWT, err := unsignedJWT.SignedString(j.secret)
+	signedJWT, err := j.keyring.sign(claims)
 	if err != nil {
 		return "", err
 	}
@@ -51,9 +53,12 @@ func (j *JwtStruct) Create(userID uuid.UUID, role enum.UserRole) (string, error)
 }
 
 func (j *JwtStruct) Decode(tokenString string, claims *Claims) error {
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(_ *jwt.Token) (any, error) {
-		return j.secret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, claims, j.keyring.verificationKey,
+		jwt.WithValidMethods([]string{
+			jwt.SigningMethodRS256.Alg(),
+			jwt.SigningMethodEdDSA.Alg(),
+			jwt.SigningMethodHS256.Alg(),
+		}))
 
 	if err != nil {
 		return err
@@ -65,3 +70,7 @@ func (j *JwtStruct) Decode(tokenString string, claims *Claims) error {
 
 	return nil
 }
+
+func (j *JwtStruct) JWKS() JWKS {
+	return j.keyring.JWKS()
+}
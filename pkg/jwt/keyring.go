@@ -0,0 +1,215 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Keyring holds the keys access tokens are signed and verified with. Every key is loaded from a PEM file in
+// one directory, the file name without its extension being the key ID ("kid").
+//
+// Rotation is staged:
+//  1. Add the new private key. It verifies and is published in the JWKS, but doesn't sign yet,
+//     so verifiers can pick it up ahead of time.
+//  2. Point the signing key ID to it. The old key keeps verifying the tokens it already signed.
+//  3. Once those have expired, replace the old private key with its public key or remove it.
+//
+// A public key file verifies tokens without being able to sign any.
+type Keyring struct {
+	signing      *signingKey
+	keys         map[string]*signingKey
+	legacySecret []byte
+}
+
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private crypto.Signer // nil for verify-only keys
+	public  crypto.PublicKey
+}
+
+// LoadKeyring reads every .pem file in dir. signingKeyID must name one of the private keys there.
+// When dir is empty, tokens are signed with legacySecret (HS256) instead.
+// Otherwise legacySecret, if set, only verifies tokens issued before the switch.
+func LoadKeyring(dir, signingKeyID string, legacySecret []byte) (*Keyring, error) {
+	k := &Keyring{
+		keys:         make(map[string]*signingKey),
+		legacySecret: legacySecret,
+	}
+
+	if dir == "" {
+		if len(legacySecret) == 0 {
+			return nil, errors.New("neither signing keys nor a secret are configured")
+		}
+		return k, nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		id := strings.TrimSuffix(filepath.Base(path), ".pem")
+
+		key, err2 := loadKey(id, path)
+		if err2 != nil {
+			return nil, fmt.Errorf("failed to load key %q: %w", id, err2)
+		}
+
+		k.keys[id] = key
+	}
+
+	signing, ok := k.keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q not found in %s", signingKeyID, dir)
+	}
+	if signing.private == nil {
+		return nil, fmt.Errorf("signing key %q is a public key", signingKeyID)
+	}
+	k.signing = signing
+
+	return k, nil
+}
+
+func loadKey(id, path string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	key := &signingKey{id: id}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err2 := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err2 != nil {
+			return nil, err2
+		}
+
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key")
+		}
+		key.private = signer
+		key.public = signer.Public()
+	case "PUBLIC KEY":
+		parsed, err2 := x509.ParsePKIXPublicKey(block.Bytes)
+		if err2 != nil {
+			return nil, err2
+		}
+		key.public = parsed
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+
+	switch pub := key.public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < 2048 {
+			return nil, errors.New("RSA keys must be at least 2048 bits")
+		}
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, errors.New("only RSA and Ed25519 keys are supported")
+	}
+
+	return key, nil
+}
+
+// sign signs the claims with the current signing key, putting its ID in the header
+func (k *Keyring) sign(claims jwt.Claims) (string, error) {
+	if k.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(k.legacySecret)
+	}
+
+	token := jwt.NewWithClaims(k.signing.method, claims)
+	token.Header["kid"] = k.signing.id
+
+	return token.SignedString(k.signing.private)
+}
+
+// verificationKey picks the key a token was signed with. The algorithm must be the one of that key,
+// so a public key can never be used as an HMAC secret.
+func (k *Keyring) verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if token.Method != jwt.SigningMethodHS256 || len(k.legacySecret) == 0 {
+			return nil, jwt.ErrTokenUnverifiable
+		}
+		return k.legacySecret, nil
+	}
+
+	key, ok := k.keys[kid]
+	if !ok || token.Method != key.method {
+		return nil, jwt.ErrTokenUnverifiable
+	}
+
+	return key.public, nil
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public half of every key, including keys that only verify
+func (k *Keyring) JWKS() JWKS {
+	jwks := JWKS{Keys: make([]JWK, 0, len(k.keys))}
+
+	for _, key := range k.keys {
+		jwk := JWK{
+			Use: "sig",
+			Alg: key.method.Alg(),
+			Kid: key.id,
+		}
+
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].Kid < jwks.Keys[j].Kid
+	})
+
+	return jwks
+}
@@ -0,0 +1,335 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/nathakusuma/auditorium-reservation-backend/domain/enum"
+)
+
+func writePrivateKey(t *testing.T, dir, id string, key crypto.Signer) {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal private key: %v", err)
+	}
+
+	writePEM(t, dir, id, "PRIVATE KEY", der)
+}
+
+func writePublicKey(t *testing.T, dir, id string, key crypto.PublicKey) {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+
+	writePEM(t, dir, id, "PUBLIC KEY", der)
+}
+
+func writePEM(t *testing.T, dir, id, blockType string, der []byte) {
+	t.Helper()
+
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, id+".pem"), data, 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+}
+
+type testKeys struct {
+	dir     string
+	rsa     *rsa.PrivateKey
+	ed25519 ed25519.PrivateKey
+	old     ed25519.PublicKey
+	// rsaPEM is the public RSA key as a verifier would download it
+	rsaPEM []byte
+}
+
+// newTestKeys writes an RSA and an Ed25519 private key and a verify-only Ed25519 public key
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+
+	keys := testKeys{dir: t.TempDir()}
+
+	var err error
+	keys.rsa, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	writePrivateKey(t, keys.dir, "rsa", keys.rsa)
+
+	_, keys.ed25519, err = ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate Ed25519 key: %v", err)
+	}
+	writePrivateKey(t, keys.dir, "ed25519", keys.ed25519)
+
+	keys.old, _, err = ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate Ed25519 key: %v", err)
+	}
+	writePublicKey(t, keys.dir, "old", keys.old)
+
+	der, err := x509.MarshalPKIXPublicKey(keys.rsa.Public())
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+	keys.rsaPEM = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	return keys
+}
+
+func testClaims() Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		Role:      enum.RoleUser,
+		SessionID: uuid.New(),
+	}
+}
+
+func signWith(t *testing.T, method jwt.SigningMethod, kid string, key any) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, testClaims())
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	return signed
+}
+
+func TestKeyring_SignAndVerify(t *testing.T) {
+	keys := newTestKeys(t)
+
+	for _, signingKeyID := range []string{"rsa", "ed25519"} {
+		t.Run(signingKeyID, func(t *testing.T) {
+			keyring, err := LoadKeyring(keys.dir, signingKeyID, nil)
+			if err != nil {
+				t.Fatalf("failed to load keyring: %v", err)
+			}
+			j := NewJwt(time.Hour, keyring)
+
+			userID := uuid.New()
+			token, err := j.Create(userID, enum.RoleAdmin, uuid.New())
+			if err != nil {
+				t.Fatalf("failed to create token: %v", err)
+			}
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+			if err != nil {
+				t.Fatalf("failed to parse token: %v", err)
+			}
+			if kid := parsed.Header["kid"]; kid != signingKeyID {
+				t.Errorf("expected kid %q, got %v", signingKeyID, kid)
+			}
+
+			var claims Claims
+			if err = j.Decode(token, &claims); err != nil {
+				t.Fatalf("failed to decode token: %v", err)
+			}
+			if claims.Subject != userID.String() || claims.Role != enum.RoleAdmin {
+				t.Errorf("unexpected claims: %+v", claims)
+			}
+		})
+	}
+}
+
+func TestKeyring_Verify(t *testing.T) {
+	keys := newTestKeys(t)
+	legacySecret := []byte("legacy-secret")
+
+	_, stranger, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate Ed25519 key: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		legacySecret []byte
+		token        string
+		wantErr      bool
+	}{
+		{
+			name:  "known kid",
+			token: signWith(t, jwt.SigningMethodEdDSA, "ed25519", keys.ed25519),
+		},
+		{
+			name:    "unknown kid",
+			token:   signWith(t, jwt.SigningMethodEdDSA, "stranger", stranger),
+			wantErr: true,
+		},
+		{
+			name:    "known kid signed by another key",
+			token:   signWith(t, jwt.SigningMethodEdDSA, "ed25519", stranger),
+			wantErr: true,
+		},
+		{
+			name:    "RSA kid with an EdDSA header",
+			token:   signWith(t, jwt.SigningMethodEdDSA, "rsa", keys.ed25519),
+			wantErr: true,
+		},
+		{
+			// The classic confusion attack: HMAC keyed with the published public key
+			name:    "RSA kid with an HS256 header",
+			token:   signWith(t, jwt.SigningMethodHS256, "rsa", keys.rsaPEM),
+			wantErr: true,
+		},
+		{
+			name:         "legacy HS256 without kid",
+			legacySecret: legacySecret,
+			token:        signWith(t, jwt.SigningMethodHS256, "", legacySecret),
+		},
+		{
+			name:         "legacy HS256 with a kid",
+			legacySecret: legacySecret,
+			token:        signWith(t, jwt.SigningMethodHS256, "rsa", legacySecret),
+			wantErr:      true,
+		},
+		{
+			name:    "HS256 without kid and no legacy secret",
+			token:   signWith(t, jwt.SigningMethodHS256, "", legacySecret),
+			wantErr: true,
+		},
+		{
+			name:         "legacy HS256 with the wrong secret",
+			legacySecret: legacySecret,
+			token:        signWith(t, jwt.SigningMethodHS256, "", []byte("another-secret")),
+			wantErr:      true,
+		},
+		{
+			name:         "RS256 without kid",
+			legacySecret: legacySecret,
+			token:        signWith(t, jwt.SigningMethodRS256, "", keys.rsa),
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring, err := LoadKeyring(keys.dir, "rsa", tt.legacySecret)
+			if err != nil {
+				t.Fatalf("failed to load keyring: %v", err)
+			}
+
+			var claims Claims
+			err = NewJwt(time.Hour, keyring).Decode(tt.token, &claims)
+			if tt.wantErr && err == nil {
+				t.Error("expected the token to be rejected")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expected the token to be accepted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestKeyring_LegacyOnly(t *testing.T) {
+	legacySecret := []byte("legacy-secret")
+
+	keyring, err := LoadKeyring("", "", legacySecret)
+	if err != nil {
+		t.Fatalf("failed to load keyring: %v", err)
+	}
+	j := NewJwt(time.Hour, keyring)
+
+	token, err := j.Create(uuid.New(), enum.RoleUser, uuid.New())
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+
+	var claims Claims
+	if err = j.Decode(token, &claims); err != nil {
+		t.Errorf("failed to decode token: %v", err)
+	}
+
+	if jwks := j.JWKS(); len(jwks.Keys) != 0 {
+		t.Errorf("expected no published keys, got %+v", jwks.Keys)
+	}
+}
+
+func TestLoadKeyring_Errors(t *testing.T) {
+	keys := newTestKeys(t)
+
+	weakDir := t.TempDir()
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	writePrivateKey(t, weakDir, "weak", weak)
+
+	tests := []struct {
+		name         string
+		dir          string
+		signingKeyID string
+		legacySecret []byte
+	}{
+		{name: "nothing configured", dir: "", signingKeyID: ""},
+		{name: "missing signing key", dir: keys.dir, signingKeyID: "missing"},
+		{name: "public signing key", dir: keys.dir, signingKeyID: "old"},
+		{name: "RSA key under 2048 bits", dir: weakDir, signingKeyID: "weak"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadKeyring(tt.dir, tt.signingKeyID, tt.legacySecret); err == nil {
+				t.Error("expected the keyring to be rejected")
+			}
+		})
+	}
+}
+
+func TestKeyring_JWKS(t *testing.T) {
+	keys := newTestKeys(t)
+
+	keyring, err := LoadKeyring(keys.dir, "rsa", []byte("legacy-secret"))
+	if err != nil {
+		t.Fatalf("failed to load keyring: %v", err)
+	}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	want := []JWK{
+		{Kty: "OKP", Use: "sig", Alg: "EdDSA", Kid: "ed25519", Crv: "Ed25519",
+			X: b64(keys.ed25519.Public().(ed25519.PublicKey))},
+		{Kty: "OKP", Use: "sig", Alg: "EdDSA", Kid: "old", Crv: "Ed25519", X: b64(keys.old)},
+		{Kty: "RSA", Use: "sig", Alg: "RS256", Kid: "rsa", N: b64(keys.rsa.N.Bytes()),
+			E: b64(big.NewInt(int64(keys.rsa.E)).Bytes())},
+	}
+
+	// The legacy secret must never be published
+	got := keyring.JWKS().Keys
+	if len(got) != len(want) {
+		t.Fatalf("expected %d keys, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("key %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+
+	if e := got[2].E; e != "AQAB" {
+		t.Errorf("expected the RSA exponent to be AQAB, got %q", e)
+	}
+}
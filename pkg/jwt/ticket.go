@@ -0,0 +1,72 @@
+package jwt
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ITicket signs registration tickets. A ticket carries no expiry, it stays valid as long as its ID matches the
+// registration, so re-registering or cancelling revokes it.
+type ITicket interface {
+	CreateTicket(ticketID, conferenceID, userID uuid.UUID) (string, error)
+	DecodeTicket(tokenString string, claims *TicketClaims) error
+}
+
+type TicketClaims struct {
+	jwt.RegisteredClaims
+	ConferenceID uuid.UUID `json:"cid"`
+}
+
+type TicketStruct struct {
+	secret []byte
+}
+
+func NewTicket(secret []byte) (ITicket, error) {
+	// HS256 accepts an empty key, which would let anyone forge tickets
+	if len(secret) == 0 {
+		return nil, errors.New("ticket secret key is empty")
+	}
+
+	return &TicketStruct{
+		secret: secret,
+	}, nil
+}
+
+func (t *TicketStruct) CreateTicket(ticketID, conferenceID, userID uuid.UUID) (string, error) {
+	claims := TicketClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:   "auditorium-reservation-backend",
+			Subject:  userID.String(),
+			ID:       ticketID.String(),
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+		},
+		ConferenceID: conferenceID,
+	}
+
+	unsignedJWT := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signedJWT, err := unsignedJWT.SignedString(t.secret)
+	if err != nil {
+		return "", err
+	}
+
+	return signedJWT, nil
+}
+
+func (t *TicketStruct) DecodeTicket(tokenString string, claims *TicketClaims) error {
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(_ *jwt.Token) (any, error) {
+		return t.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil {
+		return err
+	}
+
+	if !token.Valid {
+		return jwt.ErrSignatureInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Unknown key IDs trigger a refetch, since providers rotate keys without notice. This keeps tokens with
+// made-up key IDs from making us fetch on every request.
+const minRefreshInterval = time.Minute
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type publicKey struct {
+	alg string
+	key crypto.PublicKey
+}
+
+type keySet struct {
+	uri     string
+	getJSON func(ctx context.Context, url string, v any) error
+
+	mu          sync.Mutex
+	keys        map[string]publicKey
+	lastFetched time.Time
+}
+
+func newKeySet(uri string, getJSON func(ctx context.Context, url string, v any) error) *keySet {
+	return &keySet{
+		uri:     uri,
+		getJSON: getJSON,
+	}
+}
+
+// get returns the key to verify a token with. A token without a key ID is only accepted if the provider
+// publishes a single key.
+func (k *keySet) get(ctx context.Context, kid, alg string) (crypto.PublicKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	key, ok := k.find(kid)
+	if !ok && time.Since(k.lastFetched) >= minRefreshInterval {
+		if err := k.fetch(ctx); err != nil {
+			return nil, err
+		}
+		key, ok = k.find(kid)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("no key with id %q", kid)
+	}
+
+	// A key that states its algorithm may only be used with it
+	if key.alg != "" && key.alg != alg {
+		return nil, fmt.Errorf("key %q is for %s, not %s", kid, key.alg, alg)
+	}
+
+	return key.key, nil
+}
+
+func (k *keySet) find(kid string) (publicKey, bool) {
+	if kid == "" && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key, true
+		}
+	}
+
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+func (k *keySet) fetch(ctx context.Context) error {
+	k.lastFetched = time.Now()
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := k.getJSON(ctx, k.uri, &set); err != nil {
+		return fmt.Errorf("failed to get jwks: %w", err)
+	}
+
+	keys := make(map[string]publicKey, len(set.Keys))
+	for _, j := range set.Keys {
+		if j.Use != "" && j.Use != "sig" {
+			continue
+		}
+
+		key, err := j.publicKey()
+		if err != nil {
+			// one key we can't use shouldn't lock out the others
+			continue
+		}
+
+		keys[j.Kid] = publicKey{alg: j.Alg, key: key}
+	}
+
+	k.keys = keys
+	return nil
+}
+
+func (j *jwk) publicKey() (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := decodeBigInt(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(j.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("rsa exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, err := decodeBigInt(j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(j.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("ec point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(j.X, "="))
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", j.Kty)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
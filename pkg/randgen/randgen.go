@@ -1,23 +1,31 @@
 package randgen
 
 import (
+	cryptorand "crypto/rand"
+	"encoding/base64"
+	"fmt"
 	"math"
-	"math/rand"
+	"math/big"
 )
 
-func RandomNumber(digits int) int {
-	low := int(math.Pow10(digits - 1))
-	high := int(math.Pow10(digits)) - 1
-	return low + rand.Intn(high-low+1)
-}
+// SecureToken returns a URL-safe token made of byteLength bytes from a cryptographically secure source
+func SecureToken(byteLength int) (string, error) {
+	b := make([]byte, byteLength)
+	if _, err := cryptorand.Read(b); err != nil {
+		return "", err
+	}
 
-func RandomString(length int) string {
-	alphaNumRunes := []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890")
-	randomRune := make([]rune, length)
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
 
-	for i := 0; i < length; i++ {
-		randomRune[i] = alphaNumRunes[rand.Intn(len(alphaNumRunes)-1)]
+// SecureNumber returns a numeric code of the given length from a cryptographically secure source
+func SecureNumber(digits int) (string, error) {
+	high := big.NewInt(int64(math.Pow10(digits)))
+	n, err := cryptorand.Int(cryptorand.Reader, high)
+	if err != nil {
+		return "", err
 	}
 
-	return string(randomRune)
+	// Leading zeros are kept, so every code is equally likely
+	return fmt.Sprintf("%0*d", digits, n), nil
 }
@@ -0,0 +1,162 @@
+package totp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	cryptorand "crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The parameters authenticator apps assume when the provisioning URI leaves them out
+const (
+	digits    = 6
+	period    = 30 * time.Second
+	secretLen = 20
+	// skew is how many periods before and after the current one are accepted, for clocks that drift
+	skew = 1
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// ITOTP generates and validates RFC 6238 time-based one-time passwords.
+// Secrets have to be readable to validate codes, so they are encrypted before being stored.
+type ITOTP interface {
+	GenerateSecret() (string, error)
+	// ProvisioningURI is the otpauth:// URI authenticator apps enroll with, usually shown as a QR code
+	ProvisioningURI(accountName, secret string) string
+	// Validate reports whether the code is valid now. It also returns the time step the code belongs to,
+	// so callers can refuse a code that was already used.
+	Validate(secret, code string) (int64, bool)
+
+	EncryptSecret(secret string) (string, error)
+	DecryptSecret(encrypted string) (string, error)
+}
+
+type totpStruct struct {
+	issuer string
+	aead   cipher.AEAD
+}
+
+// NewTOTP returns a TOTP issuer. Any length of encryption key works, it is hashed into an AES-256 key.
+func NewTOTP(issuer string, encryptionKey []byte) (ITOTP, error) {
+	if len(encryptionKey) == 0 {
+		return nil, errors.New("totp secret encryption key is empty")
+	}
+
+	key := sha256.Sum256(encryptionKey)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &totpStruct{
+		issuer: issuer,
+		aead:   aead,
+	}, nil
+}
+
+func (t *totpStruct) GenerateSecret() (string, error) {
+	b := make([]byte, secretLen)
+	if _, err := cryptorand.Read(b); err != nil {
+		return "", err
+	}
+
+	return secretEncoding.EncodeToString(b), nil
+}
+
+func (t *totpStruct) ProvisioningURI(accountName, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", t.issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(digits))
+	query.Set("period", fmt.Sprint(int(period.Seconds())))
+
+	label := url.PathEscape(t.issuer) + ":" + url.PathEscape(accountName)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+func (t *totpStruct) Validate(secret, code string) (int64, bool) {
+	return validateAt(secret, code, time.Now())
+}
+
+func validateAt(secret, code string, now time.Time) (int64, bool) {
+	key, err := secretEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != digits {
+		return 0, false
+	}
+
+	current := now.Unix() / int64(period.Seconds())
+	for step := current - skew; step <= current+skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(generate(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// generate computes the code of a time step, as in RFC 4226 section 5.3
+func generate(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
+
+func (t *totpStruct) EncryptSecret(secret string) (string, error) {
+	nonce := make([]byte, t.aead.NonceSize())
+	if _, err := cryptorand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := t.aead.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (t *totpStruct) DecryptSecret(encrypted string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < t.aead.NonceSize() {
+		return "", errors.New("encrypted totp secret is too short")
+	}
+
+	nonce, ciphertext := sealed[:t.aead.NonceSize()], sealed[t.aead.NonceSize():]
+	secret, err := t.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(secret), nil
+}
@@ -0,0 +1,178 @@
+package totp
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of the RFC 6238 appendix B test vectors, "12345678901234567890"
+var rfc6238Secret = secretEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestGenerate_RFC6238(t *testing.T) {
+	// The RFC lists 8-digit codes; 6-digit codes are their last six digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},          // 94287082
+		{unix: 1111111109, want: "081804"},  // 07081804
+		{unix: 1111111111, want: "050471"},  // 14050471
+		{unix: 1234567890, want: "005924"},  // 89005924
+		{unix: 2000000000, want: "279037"},  // 69279037
+		{unix: 20000000000, want: "353130"}, // 65353130
+	}
+
+	for _, tt := range tests {
+		now := time.Unix(tt.unix, 0)
+		step := tt.unix / int64(period.Seconds())
+
+		if got := generate([]byte("12345678901234567890"), step); got != tt.want {
+			t.Errorf("T=%d: expected %s, got %s", tt.unix, tt.want, got)
+		}
+
+		gotStep, ok := validateAt(rfc6238Secret, tt.want, now)
+		if !ok || gotStep != step {
+			t.Errorf("T=%d: expected %s to validate at step %d, got step %d, ok %v", tt.unix, tt.want,
+				step, gotStep, ok)
+		}
+	}
+}
+
+func TestValidate_Skew(t *testing.T) {
+	key := []byte("12345678901234567890")
+	now := time.Unix(1234567890, 0)
+	current := now.Unix() / int64(period.Seconds())
+
+	tests := []struct {
+		name   string
+		offset int64
+		want   bool
+	}{
+		{name: "two periods early", offset: -2, want: false},
+		{name: "one period early", offset: -1, want: true},
+		{name: "current period", offset: 0, want: true},
+		{name: "one period late", offset: 1, want: true},
+		{name: "two periods late", offset: 2, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := generate(key, current+tt.offset)
+
+			step, ok := validateAt(rfc6238Secret, code, now)
+			if ok != tt.want {
+				t.Fatalf("expected ok %v, got %v", tt.want, ok)
+			}
+			if ok && step != current+tt.offset {
+				t.Errorf("expected step %d, got %d", current+tt.offset, step)
+			}
+		})
+	}
+}
+
+func TestValidate_Malformed(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+	}{
+		{name: "code too short", secret: rfc6238Secret, code: "28708"},
+		{name: "code too long", secret: rfc6238Secret, code: "94287082"},
+		{name: "secret not base32", secret: "not base32!", code: "287082"},
+		{name: "empty code", secret: rfc6238Secret, code: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := validateAt(tt.secret, tt.code, now); ok {
+				t.Error("expected the code to be rejected")
+			}
+		})
+	}
+
+	// Secrets are accepted in any case, the way users sometimes type them
+	if _, ok := validateAt(strings.ToLower(rfc6238Secret), "287082", now); !ok {
+		t.Error("expected a lowercase secret to be accepted")
+	}
+}
+
+func TestEncryptSecret(t *testing.T) {
+	issuer, err := NewTOTP("Auditorium", []byte("encryption-key"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secret, err := issuer.GenerateSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	encrypted, err := issuer.EncryptSecret(secret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(encrypted, secret) {
+		t.Fatal("expected the encrypted secret not to contain the secret")
+	}
+
+	again, err := issuer.EncryptSecret(secret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again == encrypted {
+		t.Error("expected every encryption to use a fresh nonce")
+	}
+
+	decrypted, err := issuer.DecryptSecret(encrypted)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decrypted != secret {
+		t.Errorf("expected %q, got %q", secret, decrypted)
+	}
+
+	t.Run("tampered ciphertext", func(t *testing.T) {
+		sealed, err := base64.StdEncoding.DecodeString(encrypted)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sealed[len(sealed)-1] ^= 0x01
+
+		if _, err = issuer.DecryptSecret(base64.StdEncoding.EncodeToString(sealed)); err == nil {
+			t.Error("expected a tampered secret to be rejected")
+		}
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		other, err := NewTOTP("Auditorium", []byte("another-key"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err = other.DecryptSecret(encrypted); err == nil {
+			t.Error("expected a secret encrypted with another key to be rejected")
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		if _, err = issuer.DecryptSecret(base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+			t.Error("expected a truncated secret to be rejected")
+		}
+	})
+
+	t.Run("not base64", func(t *testing.T) {
+		if _, err = issuer.DecryptSecret("not base64!"); err == nil {
+			t.Error("expected a malformed secret to be rejected")
+		}
+	})
+}
+
+func TestNewTOTP_EmptyKey(t *testing.T) {
+	if _, err := NewTOTP("Auditorium", nil); err == nil {
+		t.Error("expected an empty encryption key to be rejected")
+	}
+}